@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/bundle"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleEcosystem int64
+	bundleAppID     int64
+	bundleVersion   string
+	bundleFile      string
+	bundleSigner    string
+	bundleBatch     string
+	bundleKey       string
+	bundleUnsigned  bool
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export and import applications as bundles",
+}
+
+var exportBundleCmd = &cobra.Command{
+	Use:    "export",
+	Short:  "Export the application of the ecosystem to the bundle",
+	PreRun: loadConfigAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := bundle.Export(strconv.FormatInt(bundleEcosystem, 10), bundleAppID, bundleVersion)
+		if err != nil {
+			log.WithError(err).Fatal("exporting application")
+		}
+		writeBundle(b)
+	},
+}
+
+// upgradeBundleCmd writes the contracts which are missing on the networks started before they have
+// been added to the genesis data. Migrations of nodes don't change the contracts of ecosystems,
+// the bundle is imported into the first ecosystem separately for every application.
+var upgradeBundleCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Write the bundle of the contracts of the application which have been added after 0.1.6b9",
+	Run: func(cmd *cobra.Command, args []string) {
+		writeBundle(bundle.Upgrade(bundleAppID))
+	},
+}
+
+// writeBundle seals and signs the bundle and writes it to the file or to the stdout
+func writeBundle(b *bundle.Bundle) {
+	if err := b.Seal(); err != nil {
+		log.WithError(err).Fatal("sealing bundle")
+	}
+	if len(bundleKey) > 0 {
+		signBundle(b)
+	}
+	out, err := json.MarshalIndent(b, ``, `  `)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Fatal("marshalling bundle")
+	}
+	if len(bundleFile) == 0 {
+		fmt.Println(string(out))
+		return
+	}
+	if err = ioutil.WriteFile(bundleFile, out, 0644); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": bundleFile}).Fatal("writing bundle")
+	}
+}
+
+// signBundle signs the bundle hash with the private key from the file of the user
+func signBundle(b *bundle.Bundle) {
+	key, err := ioutil.ReadFile(bundleKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": bundleKey}).Fatal("reading private key")
+	}
+	private := strings.TrimSpace(string(key))
+	privateBytes, err := hex.DecodeString(private)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Fatal("decoding private key from hex")
+	}
+	public, err := crypto.PrivateToPublic(privateBytes)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal("getting public key")
+	}
+	sign, err := crypto.Sign(private, b.Hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal("signing bundle")
+	}
+	if err = b.SetSignature(hex.EncodeToString(public), sign); err != nil {
+		log.WithError(err).Fatal("signing bundle")
+	}
+}
+
+var importBundleCmd = &cobra.Command{
+	Use:    "import",
+	Short:  "Show the changes of the bundle and write the contracts which install it",
+	PreRun: loadConfigAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := ioutil.ReadFile(bundleFile)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": bundleFile}).Fatal("reading bundle")
+		}
+		b, err := bundle.Parse(input)
+		if err != nil {
+			log.WithError(err).Fatal("parsing bundle")
+		}
+		if err = b.Verify(bundleSigner, bundleUnsigned); err != nil {
+			log.WithError(err).Fatal("verifying bundle")
+		}
+		plan, err := bundle.Prepare(b, strconv.FormatInt(bundleEcosystem, 10), bundleAppID)
+		if err != nil {
+			log.WithError(err).Fatal("preparing install plan")
+		}
+		fmt.Printf("%s %s\n", b.Name, b.Version)
+		for _, change := range plan.Changes {
+			fmt.Printf("%-6s %s %s %s\n", change.Action, change.Type, change.Name, strings.Join(change.Fields, `,`))
+		}
+		if plan.Application == 0 {
+			fmt.Println("the application must be created before the installation of resources")
+		}
+		if len(bundleBatch) > 0 && len(plan.Contracts) > 0 {
+			out, err := json.MarshalIndent(map[string]interface{}{`contracts`: plan.Contracts}, ``, `  `)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Fatal("marshalling batch")
+			}
+			if err = ioutil.WriteFile(bundleBatch, out, 0644); err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": bundleBatch}).Fatal("writing batch")
+			}
+		}
+		fmt.Printf("%d contracts\n", len(plan.Contracts))
+	},
+}
+
+func init() {
+	bundleCmd.PersistentFlags().Int64Var(&bundleEcosystem, "ecosystem", 1, "Ecosystem ID")
+	bundleCmd.PersistentFlags().Int64Var(&bundleAppID, "app", 0, "Application ID")
+	bundleCmd.PersistentFlags().StringVar(&bundleFile, "file", "", "Bundle file")
+
+	exportBundleCmd.Flags().StringVar(&bundleVersion, "version", "", "Version of the application")
+	exportBundleCmd.Flags().StringVar(&bundleKey, "key", "", "File with the private key which signs the bundle")
+	exportBundleCmd.MarkFlagRequired("app")
+
+	upgradeBundleCmd.Flags().StringVar(&bundleKey, "key", "", "File with the private key which signs the bundle")
+	upgradeBundleCmd.MarkFlagRequired("app")
+
+	importBundleCmd.Flags().StringVar(&bundleSigner, "signer", "", "Public key which must sign the bundle")
+	importBundleCmd.Flags().BoolVar(&bundleUnsigned, "unsigned", false, "Accept the bundle without signature")
+	importBundleCmd.Flags().StringVar(&bundleBatch, "batch", "", "Write the contracts for prepareMultiple API to the file, without it only the diff is shown")
+	importBundleCmd.MarkFlagRequired("file")
+
+	bundleCmd.AddCommand(exportBundleCmd, importBundleCmd, upgradeBundleCmd)
+}
@@ -120,6 +120,20 @@ func init() {
 	viper.BindPFlag("TokenMovement.From", configCmd.Flags().Lookup("tmovFrom"))
 	viper.BindPFlag("TokenMovement.Subject", configCmd.Flags().Lookup("tmovSubj"))
 
+	// CDC
+	configCmd.Flags().BoolVar(&conf.Config.CDC.Enabled, "cdc", false, "Enable change data capture of tables")
+	configCmd.Flags().StringVar(&conf.Config.CDC.Sink, "cdcSink", "", "Send table changes to jsonl|centrifugo")
+	configCmd.Flags().StringVar(&conf.Config.CDC.Path, "cdcPath", "", "Filepath of jsonl sink (default dataDir/changes.jsonl)")
+	viper.BindPFlag("CDC.Enabled", configCmd.Flags().Lookup("cdc"))
+	viper.BindPFlag("CDC.Sink", configCmd.Flags().Lookup("cdcSink"))
+	viper.BindPFlag("CDC.Path", configCmd.Flags().Lookup("cdcPath"))
+
+	// Mempool
+	configCmd.Flags().IntVar(&conf.Config.Mempool.MaxCount, "mempoolCount", 10000, "Max count of transactions in the mempool")
+	configCmd.Flags().Int64Var(&conf.Config.Mempool.MaxSize, "mempoolSize", 64<<20, "Max size of transactions in the mempool in bytes")
+	viper.BindPFlag("Mempool.MaxCount", configCmd.Flags().Lookup("mempoolCount"))
+	viper.BindPFlag("Mempool.MaxSize", configCmd.Flags().Lookup("mempoolSize"))
+
 	// Etc
 	configCmd.Flags().StringVar(&conf.Config.PidFilePath, "pid", "",
 		fmt.Sprintf("Genesis pid file name (default dataDir/%s)", consts.DefaultPidFilename),
@@ -128,6 +142,7 @@ func init() {
 		fmt.Sprintf("Genesis lock file name (default dataDir/%s)", consts.DefaultLockFilename),
 	)
 	configCmd.Flags().StringVar(&conf.Config.KeysDir, "keysDir", "", "Keys directory (default dataDir)")
+	configCmd.Flags().StringVar(&conf.Config.SignerSocket, "signerSocket", "", "Unix socket of the remote signer of node key (default NodePrivateKey file)")
 	configCmd.Flags().StringVar(&conf.Config.DataDir, "dataDir", "", "Data directory (default cwd/genesis-data)")
 	configCmd.Flags().StringVar(&conf.Config.TempDir, "tempDir", "", "Temporary directory (default temporary directory of OS)")
 	configCmd.Flags().StringVar(&conf.Config.FirstBlockPath, "firstBlock", "", "First block path (default dataDir/1block)")
@@ -135,18 +150,23 @@ func init() {
 	configCmd.Flags().StringVar(&conf.Config.TLSCert, "tls-cert", "", "Filepath to the fullchain of certificates")
 	configCmd.Flags().StringVar(&conf.Config.TLSKey, "tls-key", "", "Filepath to the private key")
 	configCmd.Flags().Int64Var(&conf.Config.MaxPageGenerationTime, "mpgt", 1000, "Max page generation time in ms")
+	configCmd.Flags().IntVar(&conf.Config.TemplateCacheSize, "tplCache", 256, "Max count of DBFind results of templates in the cache")
+	configCmd.Flags().Int64Var(&conf.Config.MaxReorgDepth, "maxReorgDepth", 0, "Max count of blocks which can be rolled back on fork (default rb_blocks_1)")
 	configCmd.Flags().StringSliceVar(&conf.Config.NodesAddr, "nodesAddr", []string{}, "List of addresses for downloading blockchain")
 	configCmd.Flags().StringVar(&conf.Config.RunningMode, "runMode", "PublicBlockchain", "Node running mode")
 
 	viper.BindPFlag("PidFilePath", configCmd.Flags().Lookup("pid"))
 	viper.BindPFlag("LockFilePath", configCmd.Flags().Lookup("lock"))
 	viper.BindPFlag("KeysDir", configCmd.Flags().Lookup("keysDir"))
+	viper.BindPFlag("SignerSocket", configCmd.Flags().Lookup("signerSocket"))
 	viper.BindPFlag("DataDir", configCmd.Flags().Lookup("dataDir"))
 	viper.BindPFlag("FirstBlockPath", configCmd.Flags().Lookup("firstBlock"))
 	viper.BindPFlag("TLS", configCmd.Flags().Lookup("tls"))
 	viper.BindPFlag("TLSCert", configCmd.Flags().Lookup("tls-cert"))
 	viper.BindPFlag("TLSKey", configCmd.Flags().Lookup("tls-key"))
 	viper.BindPFlag("MaxPageGenerationTime", configCmd.Flags().Lookup("mpgt"))
+	viper.BindPFlag("TemplateCacheSize", configCmd.Flags().Lookup("tplCache"))
+	viper.BindPFlag("MaxReorgDepth", configCmd.Flags().Lookup("maxReorgDepth"))
 	viper.BindPFlag("TempDir", configCmd.Flags().Lookup("tempDir"))
 	viper.BindPFlag("NodesAddr", configCmd.Flags().Lookup("nodesAddr"))
 	viper.BindPFlag("RunningMode", configCmd.Flags().Lookup("runMode"))
@@ -83,7 +83,7 @@ var generateFirstBlockCmd = &cobra.Command{
 			return
 		}
 
-		block, err := block.MarshallBlock(header, [][]byte{tx}, []byte("0"), "")
+		block, err := block.MarshallBlock(header, [][]byte{tx}, []byte("0"), nil)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Fatal("first block marshalling")
 			return
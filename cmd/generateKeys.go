@@ -11,6 +11,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/keystore"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -18,29 +19,51 @@ import (
 
 const fileMode = 0600
 
+var (
+	keyTypeName string
+	encryptKeys bool
+)
+
 // generateKeysCmd represents the generateKeys command
 var generateKeysCmd = &cobra.Command{
 	Use:    "generateKeys",
 	Short:  "Keys generation",
 	PreRun: loadConfig,
 	Run: func(cmd *cobra.Command, args []string) {
-		_, publicKey, err := createKeyPair(
+		keyType, err := crypto.ParseKeyType(keyTypeName)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "key_type": keyTypeName}).Fatal("parsing key type")
+			return
+		}
+		if encryptKeys {
+			pass, err := readPassphrase(true)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Fatal("reading keystore passphrase")
+				return
+			}
+			keystore.Default().Unlock(pass)
+			keystore.Zero(pass)
+			defer keystore.Default().Lock()
+		}
+		publicKey, err := createKeyPair(
 			filepath.Join(conf.Config.KeysDir, consts.PrivateKeyFilename),
 			filepath.Join(conf.Config.KeysDir, consts.PublicKeyFilename),
+			keyType,
 		)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err}).Fatal("generating user keys")
 			return
 		}
-		_, _, err = createKeyPair(
+		_, err = createKeyPair(
 			filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename),
 			filepath.Join(conf.Config.KeysDir, consts.NodePublicKeyFilename),
+			crypto.KeyECDSA,
 		)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err}).Fatal("generating node keys")
 			return
 		}
-		address := crypto.Address(publicKey)
+		address := keyType.Address(publicKey)
 		keyIDPath := filepath.Join(conf.Config.KeysDir, consts.KeyIDFilename)
 		err = createFile(keyIDPath, []byte(strconv.FormatInt(address, 10)))
 		if err != nil {
@@ -63,14 +86,17 @@ func createFile(filename string, data []byte) error {
 	return ioutil.WriteFile(filename, data, fileMode)
 }
 
-func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err error) {
-	priv, pub, err = crypto.GenBytesKeys()
+func createKeyPair(privFilename, pubFilename string, keyType crypto.KeyType) (pub []byte, err error) {
+	priv, pub, err := keyType.GenerateKeys()
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("generate keys")
 		return
 	}
+	defer keystore.Zero(priv)
 
-	err = createFile(privFilename, []byte(hex.EncodeToString(priv)))
+	hexPriv := []byte(hex.EncodeToString(priv))
+	err = keystore.Default().WriteKey(privFilename, hexPriv)
+	keystore.Zero(hexPriv)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err, "path": privFilename}).Error("creating private key")
 		return
@@ -84,3 +110,9 @@ func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err erro
 
 	return
 }
+
+func init() {
+	generateKeysCmd.Flags().StringVar(&keyTypeName, "keyType", "ecdsa", "Type of the user key (ecdsa, ed25519)")
+	generateKeysCmd.Flags().BoolVar(&encryptKeys, "encrypt", false, "Encrypt private keys with passphrase")
+	generateKeysCmd.Flags().StringVar(&keystorePassFile, "keystorePassFile", "", "File with the passphrase of private keys")
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/keystore"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var keystorePassFile string
+
+// privateKeyFiles are the files of private keys which can be encrypted
+var privateKeyFiles = []string{consts.PrivateKeyFilename, consts.NodePrivateKeyFilename}
+
+// readPassphrase returns the passphrase of keystore from the environment variable, the file or the terminal
+func readPassphrase(confirm bool) ([]byte, error) {
+	if pass := os.Getenv(keystore.PassphraseEnv); len(pass) > 0 {
+		return []byte(pass), nil
+	}
+	if len(keystorePassFile) > 0 {
+		data, err := ioutil.ReadFile(keystorePassFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading passphrase file %s", keystorePassFile)
+		}
+		return bytes.TrimRight(data, "\r\n"), nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil, fmt.Errorf("passphrase is required, use %s or --keystorePassFile", keystore.PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Keystore passphrase: ")
+	pass, err := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading passphrase")
+	}
+	if len(pass) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, "Repeat passphrase: ")
+		repeat, err := terminal.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		defer keystore.Zero(repeat)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading passphrase")
+		}
+		if !bytes.Equal(pass, repeat) {
+			keystore.Zero(pass)
+			return nil, errors.New("passphrases don't match")
+		}
+	}
+	return pass, nil
+}
+
+// unlockKeystore asks the passphrase if the node key is encrypted and checks it
+func unlockKeystore() {
+	nodeKeyPath := filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename)
+	data, err := ioutil.ReadFile(nodeKeyPath)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": nodeKeyPath}).Fatal("reading node private key")
+	}
+	if !keystore.IsEncrypted(data) {
+		log.WithFields(log.Fields{"path": nodeKeyPath}).Warning("node private key is not encrypted, use encryptKeys command")
+		return
+	}
+	pass, err := readPassphrase(false)
+	if err != nil {
+		log.WithError(err).Fatal("reading keystore passphrase")
+	}
+	keystore.Default().Unlock(pass)
+	keystore.Zero(pass)
+
+	key, err := keystore.Default().ReadKey(nodeKeyPath)
+	if err != nil {
+		log.WithError(err).Fatal("unlocking node private key")
+	}
+	keystore.Zero(key)
+}
+
+// encryptKeysCmd encrypts the plaintext private keys
+var encryptKeysCmd = &cobra.Command{
+	Use:    "encryptKeys",
+	Short:  "Encrypting private keys with passphrase",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		pass, err := readPassphrase(true)
+		if err != nil {
+			log.WithError(err).Fatal("reading keystore passphrase")
+		}
+		keystore.Default().Unlock(pass)
+		keystore.Zero(pass)
+		defer keystore.Default().Lock()
+
+		for _, name := range privateKeyFiles {
+			path := filepath.Join(conf.Config.KeysDir, name)
+			data, err := ioutil.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{"path": path}).Fatal("reading private key")
+			}
+			if keystore.IsEncrypted(data) {
+				log.WithFields(log.Fields{"path": path}).Info("private key is already encrypted")
+				continue
+			}
+			err = keystore.Default().WriteKey(path, bytes.TrimSpace(data))
+			keystore.Zero(data)
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{"path": path}).Fatal("encrypting private key")
+			}
+			log.WithFields(log.Fields{"path": path}).Info("private key is encrypted")
+		}
+	},
+}
+
+func init() {
+	encryptKeysCmd.Flags().StringVar(&keystorePassFile, "keystorePassFile", "", "File with the passphrase of private keys")
+}
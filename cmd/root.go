@@ -25,6 +25,9 @@ func init() {
 		startCmd,
 		configCmd,
 		stopNetworkCmd,
+		translationsCmd,
+		bundleCmd,
+		encryptKeysCmd,
 	)
 
 	// This flags are visible for all child commands
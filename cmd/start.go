@@ -12,10 +12,12 @@ var startCmd = &cobra.Command{
 	Short:  "Starting node",
 	PreRun: loadConfigWKey,
 	Run: func(cmd *cobra.Command, args []string) {
+		unlockKeystore()
 		daylight.Start()
 	},
 }
 
 func init() {
 	startCmd.Flags().BoolVar(&conf.Config.TestRollBack, "testRollBack", false, "Starts special set of daemons")
+	startCmd.Flags().StringVar(&keystorePassFile, "keystorePassFile", "", "File with the passphrase of private keys")
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/language"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trEcosystem int64
+	trAppID     int64
+	trLang      string
+	trSource    string
+	trFormat    string
+	trFile      string
+	trBatch     string
+)
+
+type translationBatchItem struct {
+	Contract string            `json:"contract"`
+	Params   map[string]string `json:"params"`
+}
+
+// translationsCmd represents the translations command
+var translationsCmd = &cobra.Command{
+	Use:   "translations",
+	Short: "Export and import language resources in PO or XLIFF format",
+}
+
+var exportTranslationsCmd = &cobra.Command{
+	Use:    "export",
+	Short:  "Export language resources of the ecosystem for translation",
+	PreRun: loadConfigAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(trSource) == 0 {
+			trSource = language.DefLang()
+		}
+		list, err := language.LoadTranslations(strconv.FormatInt(trEcosystem, 10), trAppID)
+		if err != nil {
+			log.WithError(err).Fatal("loading translations")
+		}
+		out := os.Stdout
+		if len(trFile) > 0 {
+			if out, err = os.Create(trFile); err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": trFile}).Fatal("creating file")
+			}
+			defer out.Close()
+		}
+		if err = language.Export(out, trFormat, language.Units(list, trSource, trLang), trSource, trLang); err != nil {
+			log.WithError(err).Fatal("exporting translations")
+		}
+	},
+}
+
+var importTranslationsCmd = &cobra.Command{
+	Use:    "import",
+	Short:  "Show the changes of the translated file and write them as one EditLangs contract",
+	PreRun: loadConfigAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, err := ioutil.ReadFile(trFile)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": trFile}).Fatal("reading file")
+		}
+		units, lang, err := language.Import(strings.NewReader(string(input)), trFormat)
+		if err != nil {
+			log.WithError(err).Fatal("parsing translations")
+		}
+		if len(trLang) > 0 {
+			lang = trLang
+		}
+		lang = strings.ToLower(lang)
+		if len(lang) == 0 {
+			log.Fatal("language of translations is undefined")
+		}
+		prefix := strconv.FormatInt(trEcosystem, 10)
+		list, err := language.LoadTranslations(prefix, trAppID)
+		if err != nil {
+			log.WithError(err).Fatal("loading translations")
+		}
+		changes, unknown, err := language.Diff(list, lang, units)
+		if err != nil {
+			log.WithError(err).Fatal("comparing translations")
+		}
+		// all changes are written by one transaction, so the translation is applied entirely or not at all
+		params := map[string]string{
+			`IdList[]`:    strconv.Itoa(len(changes)),
+			`TransList[]`: strconv.Itoa(len(changes)),
+		}
+		for i, change := range changes {
+			fmt.Printf("%s [%s]\n- %s\n+ %s\n", change.Name, lang, change.Old, change.New)
+			params[fmt.Sprintf(`IdList[%d]`, i)] = strconv.FormatInt(change.ID, 10)
+			params[fmt.Sprintf(`TransList[%d]`, i)] = change.Trans
+		}
+		for _, name := range unknown {
+			fmt.Printf("unknown resource %s\n", name)
+		}
+		printUsage(prefix)
+		if len(trBatch) > 0 && len(changes) > 0 {
+			batch := []translationBatchItem{{Contract: `EditLangs`, Params: params}}
+			out, err := json.MarshalIndent(map[string]interface{}{`contracts`: batch}, ``, `  `)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Fatal("marshalling batch")
+			}
+			if err = ioutil.WriteFile(trBatch, out, 0644); err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "filepath": trBatch}).Fatal("writing batch")
+			}
+		}
+		fmt.Printf("%d changes, %d unknown resources\n", len(changes), len(unknown))
+	},
+}
+
+var checkTranslationsCmd = &cobra.Command{
+	Use:    "check",
+	Short:  "Show missing and obsolete language resources",
+	PreRun: loadConfigAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		printUsage(strconv.FormatInt(trEcosystem, 10))
+	},
+}
+
+func printUsage(prefix string) {
+	missing, obsolete, err := language.CheckUsage(prefix, trAppID)
+	if err != nil {
+		log.WithError(err).Fatal("checking usage of translations")
+	}
+	for _, name := range missing {
+		fmt.Printf("missing resource %s\n", name)
+	}
+	for _, name := range obsolete {
+		fmt.Printf("obsolete resource %s\n", name)
+	}
+}
+
+func loadConfigAndDB(cmd *cobra.Command, args []string) {
+	loadConfig(cmd, args)
+	if err := model.GormInit(
+		conf.Config.DB.Host,
+		conf.Config.DB.Port,
+		conf.Config.DB.User,
+		conf.Config.DB.Password,
+		conf.Config.DB.Name,
+	); err != nil {
+		log.WithError(err).Fatal("init db")
+	}
+}
+
+func init() {
+	translationsCmd.PersistentFlags().Int64Var(&trEcosystem, "ecosystem", 1, "Ecosystem ID")
+	translationsCmd.PersistentFlags().Int64Var(&trAppID, "app", 0, "Application ID, 0 means all applications")
+	translationsCmd.PersistentFlags().StringVar(&trFormat, "format", language.FormatPO, "Format of the file (po, xliff)")
+
+	exportTranslationsCmd.Flags().StringVar(&trLang, "lang", "", "Target language")
+	exportTranslationsCmd.Flags().StringVar(&trSource, "source", "", "Source language (default language of the node)")
+	exportTranslationsCmd.Flags().StringVar(&trFile, "file", "", "Output file (default stdout)")
+	exportTranslationsCmd.MarkFlagRequired("lang")
+
+	importTranslationsCmd.Flags().StringVar(&trLang, "lang", "", "Language of the translations (default language of the file)")
+	importTranslationsCmd.Flags().StringVar(&trFile, "file", "", "Translated file")
+	importTranslationsCmd.Flags().StringVar(&trBatch, "batch", "", "Write EditLangs contract for prepareMultiple API to the file")
+	importTranslationsCmd.MarkFlagRequired("file")
+
+	translationsCmd.AddCommand(exportTranslationsCmd, importTranslationsCmd, checkTranslationsCmd)
+}
@@ -59,6 +59,7 @@ type apiData struct {
 	vde           bool
 	vm            *script.VM
 	token         *jwt.Token
+	raw           bool // raw is true if the handler has written the response itself
 }
 
 // ParamString reaturs string value of the api params
@@ -203,6 +204,9 @@ func DefaultHandler(method, pattern string, params map[string]int, handlers ...a
 				return
 			}
 		}
+		if data.raw {
+			return
+		}
 
 		jsonResult, err := json.Marshal(data.result)
 		if err != nil {
@@ -230,10 +230,14 @@ func postTxResult(txname string, form *url.Values) (id int64, msg string, err er
 		return
 	}
 
+	nonce := form.Get(`nonce`)
 	form = &url.Values{}
 	if err = appendSign(ret, form); err != nil {
 		return
 	}
+	if len(nonce) > 0 {
+		form.Set(`nonce`, nonce)
+	}
 	requestID := ret["request_id"].(string)
 
 	ret = map[string]interface{}{}
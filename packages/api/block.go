@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
@@ -36,6 +37,9 @@ type getBlockInfoResult struct {
 	Time          int64  `json:"time"`
 	Tx            int32  `json:"tx_count"`
 	RollbacksHash []byte `json:"rollbacks_hash"`
+	Final         bool   `json:"final"`
+
+	Attestations []consensus.Attestation `json:"attestations"`
 }
 
 func getBlockInfo(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) (err error) {
@@ -50,6 +54,11 @@ func getBlockInfo(w http.ResponseWriter, r *http.Request, data *apiData, logger
 		log.WithFields(log.Fields{"type": consts.NotFound, "id": blockID}).Error("block with id not found")
 		return errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
 	}
-	data.result = &getBlockInfoResult{Hash: block.Hash, EcosystemID: block.EcosystemID, KeyID: block.KeyID, Time: block.Time, Tx: block.Tx, RollbacksHash: block.RollbacksHash}
+	attestations, err := consensus.GetAttestations(&block)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &getBlockInfoResult{Hash: block.Hash, EcosystemID: block.EcosystemID, KeyID: block.KeyID, Time: block.Time, Tx: block.Tx, RollbacksHash: block.RollbacksHash,
+		Final: block.Final, Attestations: attestations}
 	return nil
 }
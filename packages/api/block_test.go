@@ -1,9 +1,14 @@
 package api
 
 import (
+	"net/url"
 	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetMaxBlockID(t *testing.T) {
@@ -17,3 +22,27 @@ func TestGetBlockInfo(t *testing.T) {
 	err := sendGet(`block/1`, nil, &ret)
 	assert.NoError(t, err)
 }
+
+func TestBlockFinality(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	form := url.Values{"Name": {randName(`final`)}, "Value": {`1`}, "Conditions": {`true`}}
+	blockID, _, err := postTxResult(`NewParameter`, &form)
+	require.NoError(t, err)
+
+	// the block becomes final when the attestations of nodes have been collected
+	var ret getBlockInfoResult
+	for i := 0; i < 15; i++ {
+		require.NoError(t, sendGet(`block/`+converter.Int64ToStr(blockID), nil, &ret))
+		if ret.Final {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	assert.True(t, ret.Final)
+	assert.NotEmpty(t, ret.Attestations)
+
+	var first getBlockInfoResult
+	require.NoError(t, sendGet(`block/1`, nil, &first))
+	assert.True(t, first.Final)
+}
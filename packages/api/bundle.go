@@ -0,0 +1,118 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/bundle"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func checkBundleAccess(w http.ResponseWriter, data *apiData) error {
+	sc := getSmartContract(data)
+	for _, itype := range bundle.InstallOrder {
+		if _, err := sc.AccessTablePerm(getPrefix(data)+`_`+itype, `read`); err != nil {
+			return errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+		}
+	}
+	return nil
+}
+
+// exportBundle returns the sealed application bundle. The client signs its hash
+// and sends the signature to signBundle.
+func exportBundle(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if err := checkBundleAccess(w, data); err != nil {
+		return err
+	}
+	appID := converter.StrToInt64(data.ParamString(`app_id`))
+	b, err := bundle.Export(getPrefix(data), appID, data.ParamString(`version`))
+	if err == bundle.ErrApplication {
+		return errorAPI(w, `E_APPNOTFOUND`, http.StatusBadRequest, appID)
+	}
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if err = b.Seal(); err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.json"`, b.Name, b.Version))
+	data.result = b
+	return nil
+}
+
+// signBundle adds the signature of the bundle hash which has been made by the client
+// with the key of the wallet
+func signBundle(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	b, err := bundle.Parse([]byte(data.ParamString(`data`)))
+	if err != nil {
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if err = b.Verify(``, true); err != nil {
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	key := &model.Key{}
+	key.SetTablePrefix(data.ecosystemId)
+	if _, err = key.Get(data.keyId); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting public key from keys")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	public := key.PublicKey
+	if len(public) == 0 && data.params[`pubkey`] != nil {
+		public = data.params[`pubkey`].([]byte)
+		if len(public) > 64 {
+			public = public[len(public)-64:]
+		}
+	}
+	if len(public) == 0 {
+		return errorAPI(w, `E_EMPTYPUBLIC`, http.StatusBadRequest)
+	}
+	if err = b.SetSignature(hex.EncodeToString(public), data.params[`signature`].([]byte)); err != nil {
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.json"`, b.Name, b.Version))
+	data.result = b
+	return nil
+}
+
+// importBundle verifies the bundle and returns the diff with the ecosystem and the contracts
+// which install the application. The contracts can be sent as one batch via prepareMultiple.
+// The unsigned bundle is accepted only if unsigned parameter is 1.
+func importBundle(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	b, err := bundle.Parse([]byte(data.ParamString(`data`)))
+	if err != nil {
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if err = b.Verify(data.ParamString(`signer`), data.ParamInt64(`unsigned`) == 1); err != nil {
+		return errorAPI(w, `E_BUNDLE`, http.StatusBadRequest, err.Error())
+	}
+	if err = checkBundleAccess(w, data); err != nil {
+		return err
+	}
+	plan, err := bundle.Prepare(b, getPrefix(data), data.ParamInt64(`app_id`))
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = plan
+	return nil
+}
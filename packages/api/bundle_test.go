@@ -0,0 +1,70 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/bundle"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	out, err := sendRawRequest("GET", "bundle/1?version=1.0", nil)
+	assert.NoError(t, err)
+	var b bundle.Bundle
+	if !assert.NoError(t, json.Unmarshal(out, &b)) {
+		return
+	}
+	assert.Equal(t, "1.0", b.Version)
+	assert.Empty(t, b.Signature)
+
+	var plan bundle.Plan
+	assert.Error(t, sendPost("bundle/import", &url.Values{"data": {string(out)}, "app_id": {"1"}}, &plan))
+	assert.NoError(t, sendPost("bundle/import", &url.Values{"data": {string(out)}, "app_id": {"1"},
+		"unsigned": {"1"}}, &plan))
+
+	sign, err := crypto.Sign(gPrivate, b.Hash)
+	assert.NoError(t, err)
+	var signed bundle.Bundle
+	assert.NoError(t, sendPost("bundle/sign", &url.Values{"data": {string(out)},
+		"signature": {hex.EncodeToString(sign)}, "pubkey": {gPublic}}, &signed))
+	assert.NotEmpty(t, signed.Signature)
+	assert.Error(t, sendPost("bundle/sign", &url.Values{"data": {string(out)},
+		"signature": {hex.EncodeToString(sign[1:])}, "pubkey": {gPublic}}, &signed))
+
+	out, err = json.Marshal(signed)
+	assert.NoError(t, err)
+	assert.NoError(t, sendPost("bundle/import", &url.Values{"data": {string(out)}, "app_id": {"1"},
+		"signer": {signed.PublicKey}}, &plan))
+	assert.Empty(t, plan.Contracts)
+
+	b.Version = "1.1"
+	modified, err := json.Marshal(b)
+	assert.NoError(t, err)
+	assert.Error(t, sendPost("bundle/import", &url.Values{"data": {string(modified)}}, &plan))
+
+	_, err = sendRawRequest("GET", "bundle/100000", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/cdc"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	changesDefaultLimit = 100
+	changesMaxLimit     = 1000
+)
+
+type changesResult struct {
+	List []*cdc.Event `json:"list"`
+	// Next is the value of the after parameter for the next page
+	Next int64 `json:"next"`
+}
+
+func getChanges(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if !conf.Config.CDC.Enabled {
+		return errorAPI(w, `E_CDCDISABLED`, http.StatusBadRequest)
+	}
+	limit := int(data.ParamInt64(`limit`))
+	if limit <= 0 {
+		limit = changesDefaultLimit
+	} else if limit > changesMaxLimit {
+		limit = changesMaxLimit
+	}
+	after := data.ParamInt64(`after`)
+	changes, err := model.GetChanges(getPrefix(data), data.ParamInt64(`since`), after, limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting changes")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	result := &changesResult{List: make([]*cdc.Event, 0, len(changes)), Next: after}
+	canRead := readableTables(data)
+	for i := range changes {
+		// the cursor moves over the changes of unreadable tables as well
+		result.Next = changes[i].ID
+		if !canRead(changes[i].NameTable) {
+			continue
+		}
+		event, err := cdc.NewEvent(&changes[i])
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling change")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		result.List = append(result.List, event)
+	}
+	data.result = result
+	return nil
+}
+
+// readableTables returns the function which checks the read permission of the caller for the table.
+// The permissions are evaluated once for each table.
+func readableTables(data *apiData) func(table string) bool {
+	sc := getSmartContract(data)
+	prefix := getPrefix(data) + `_`
+	tables := make(map[string]bool)
+	return func(table string) bool {
+		if ok, found := tables[table]; found {
+			return ok
+		}
+		_, err := sc.AccessTablePerm(table, `read`)
+		tables[table] = strings.HasPrefix(table, prefix) && err == nil
+		return tables[table]
+	}
+}
+
+type channelSign struct {
+	Sign   string `json:"sign,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+// subscribeChanges signs the subscriptions of the centrifugo client to the private channels of the table changes.
+// The subscription is refused with 403 status for the tables which can't be read by the caller.
+func subscribeChanges(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if !conf.Config.CDC.Enabled {
+		return errorAPI(w, `E_CDCDISABLED`, http.StatusBadRequest)
+	}
+	canRead := readableTables(data)
+	client := data.ParamString(`client`)
+	result := make(map[string]channelSign)
+	for _, channel := range strings.Split(data.ParamString(`channels`), `,`) {
+		channel = strings.TrimSpace(channel)
+		if !strings.HasPrefix(channel, cdc.ChannelPrefix) || !canRead(strings.TrimPrefix(channel, cdc.ChannelPrefix)) {
+			result[channel] = channelSign{Status: http.StatusForbidden}
+			continue
+		}
+		sign, err := publisher.ChannelSign(client, channel)
+		if err != nil {
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		result[channel] = channelSign{Sign: sign}
+	}
+	data.result = result
+	return nil
+}
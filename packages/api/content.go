@@ -20,6 +20,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -41,6 +42,10 @@ type contentResult struct {
 	NodesCount int64           `json:"nodesCount,omitempty"`
 }
 
+type validateResult struct {
+	Errors []template.ValidationError `json:"errors"`
+}
+
 type hashResult struct {
 	Hash string `json:"hash"`
 }
@@ -48,6 +53,13 @@ type hashResult struct {
 const (
 	strTrue = `true`
 	strOne  = `1`
+
+	formatJSON = `json`
+	formatHTML = `html`
+
+	// publicPagesParam is the application parameter with the comma separated names of the pages
+	// of the application which can be rendered for the clients without authorization
+	publicPagesParam = `public_pages`
 )
 
 func initVars(r *http.Request, data *apiData) *map[string]string {
@@ -107,10 +119,25 @@ func pageValue(w http.ResponseWriter, data *apiData, logger *log.Entry) (*model.
 }
 
 func getPage(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var theme template.Theme
+	switch data.ParamString(`format`) {
+	case ``, formatJSON:
+	case formatHTML:
+		if theme = template.GetTheme(data.ParamString(`theme`)); theme == nil {
+			return errorAPI(w, `E_THEME`, http.StatusBadRequest, data.ParamString(`theme`))
+		}
+	default:
+		return errorAPI(w, `E_CONTENTFORMAT`, http.StatusBadRequest, data.ParamString(`format`))
+	}
 	page, err := pageValue(w, data, logger)
 	if err != nil {
 		return err
 	}
+	return renderPage(w, r, data, page, theme, logger)
+}
+
+func renderPage(w http.ResponseWriter, r *http.Request, data *apiData, page *model.Page, theme template.Theme,
+	logger *log.Entry) error {
 	menu, err := model.Single(`SELECT value FROM "`+getPrefix(data)+`_menu" WHERE name = ?`,
 		page.Menu).String()
 	if err != nil {
@@ -127,6 +154,15 @@ func getPage(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 		vars := initVars(r, data)
 		(*vars)["app_id"] = converter.Int64ToStr(page.AppID)
 
+		if theme != nil {
+			out := template.Template2HTML(page.Value, &timeout, vars, theme)
+			if timeout {
+				return
+			}
+			data.result = out
+			success <- true
+			return
+		}
 		ret := template.Template2JSON(page.Value, &timeout, vars)
 		if timeout {
 			return
@@ -155,9 +191,69 @@ func getPage(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.
 		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error(page.Name + " is a heavy page")
 		return errorAPI(w, `E_HEAVYPAGE`, http.StatusInternalServerError)
 	}
+	if out, ok := data.result.([]byte); ok {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(out)
+		data.raw = true
+	}
 	return nil
 }
 
+// isPublicPage returns true if the page is listed in the public_pages parameter of its application
+func isPublicPage(data *apiData, page *model.Page) (bool, error) {
+	param := &model.AppParam{}
+	param.SetTablePrefix(getPrefix(data))
+	found, err := param.Get(nil, page.AppID, publicPagesParam)
+	if err != nil || !found {
+		return false, err
+	}
+	for _, name := range strings.Split(param.Value, `,`) {
+		if strings.TrimSpace(name) == page.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getPublicPage returns HTML of the page for the clients without authorization.
+// Only the pages which are marked as public by the application are rendered, the page is rendered
+// for the anonymous user, so DBFind returns only the data of the tables which can be read by anyone.
+func getPublicPage(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	theme := template.GetTheme(data.ParamString(`theme`))
+	if theme == nil {
+		return errorAPI(w, `E_THEME`, http.StatusBadRequest, data.ParamString(`theme`))
+	}
+	ecosystemID, _, err := checkEcosystem(w, data, logger)
+	if err != nil {
+		return err
+	}
+	if ecosystemID == 0 {
+		ecosystemID = 1
+	}
+	data.ecosystemId = ecosystemID
+	data.keyId = 0
+	data.roleId = 0
+	r.Form.Set(`ecosystem`, converter.Int64ToStr(ecosystemID))
+	r.Form.Del(`keyID`)
+	r.Form.Del(`roleID`)
+	r.Form.Del(`vde`)
+
+	page, err := pageValue(w, data, logger)
+	if err != nil {
+		return err
+	}
+	public, err := isPublicPage(data, page)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting public pages")
+		return errorAPI(w, `E_SERVER`, http.StatusInternalServerError)
+	}
+	if _, errAccess := getSmartContract(data).AccessTablePerm(getPrefix(data)+`_pages`, `read`); !public || errAccess != nil {
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "page": page.Name}).Warn("page is not public")
+		return errorAPI(w, `E_NOTFOUND`, http.StatusNotFound)
+	}
+	return renderPage(w, r, data, page, theme, logger)
+}
+
 func getPageHash(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) (err error) {
 	err = getPage(w, r, data, logger)
 	if err == nil {
@@ -219,3 +315,12 @@ func getSource(w http.ResponseWriter, r *http.Request, data *apiData, logger *lo
 	data.result = &contentResult{Tree: ret}
 	return nil
 }
+
+func validateContent(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var ecosystem int64
+	if !data.vde {
+		ecosystem = data.ecosystemId
+	}
+	data.result = &validateResult{Errors: template.ValidateTemplate(data.ParamString(`template`), ecosystem)}
+	return nil
+}
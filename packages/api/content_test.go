@@ -20,6 +20,8 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/GenesisKernel/go-genesis/packages/template"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -126,3 +128,62 @@ func TestContent(t *testing.T) {
 		assert.Equal(t, v.expected, string(ret.Tree))
 	}
 }
+
+func TestContentHTML(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	name := randName(`page`)
+	assert.NoError(t, postTx(`NewPage`, &url.Values{
+		"ApplicationId": {`1`},
+		"Name":          {name},
+		"Value":         {`SetTitle(Title)Div(myclass){Span(My <text>)}`},
+		"Menu":          {`default_menu`},
+		"Conditions":    {"true"},
+	}))
+
+	out, err := sendRawRequest(`POST`, `content/page/`+name, &url.Values{"format": {"html"}, "theme": {"plain"}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `<title>Title</title>`)
+	assert.Contains(t, string(out), `<body><div class="myclass"><span>My &lt;text&gt;</span></div></body>`)
+
+	_, err = sendRawRequest(`POST`, `content/page/`+name, &url.Values{"format": {"html"}, "theme": {"unknown"}})
+	assert.EqualError(t, err, `400 {"error": "E_THEME", "msg": "Theme unknown has not been found" , "params": ["unknown"]}`)
+}
+
+func TestPublicPage(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	name := randName(`public`)
+	_, appID, err := postTxResult(`NewApplication`, &url.Values{"Name": {name}, "Conditions": {"true"}})
+	assert.NoError(t, err)
+	assert.NoError(t, postTx(`NewPage`, &url.Values{
+		"ApplicationId": {appID},
+		"Name":          {name},
+		"Value":         {`Div(myclass){Span(Public)}`},
+		"Menu":          {`default_menu`},
+		"Conditions":    {"true"},
+	}))
+
+	_, err = sendRawRequest(`GET`, `content/page/`+name+`?ecosystem=1`, nil)
+	assert.EqualError(t, err, `404 {"error": "E_NOTFOUND", "msg": "Page not found" }`)
+
+	assert.NoError(t, postTx(`NewAppParam`, &url.Values{"ApplicationId": {appID},
+		"Name": {`public_pages`}, "Value": {`unknown,` + name}, "Conditions": {"true"}}))
+	out, err := sendRawRequest(`GET`, `content/page/`+name+`?ecosystem=1`, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `<div class="myclass"><span>Public</span></div>`)
+}
+
+func TestContentValidate(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	var ret validateResult
+	assert.NoError(t, sendPost(`content/validate`, &url.Values{
+		"template": {"Div(myclass){\r\n\tInclude(unknown_block)Span(text}"},
+	}, &ret))
+	if assert.Len(t, ret.Errors, 2) {
+		assert.Equal(t, template.ValidationError{Line: 2, Column: 2, Level: template.LevelWarning,
+			Message: `Block unknown_block has not been found`}, ret.Errors[0])
+		assert.Equal(t, template.LevelError, ret.Errors[1].Level)
+	}
+}
@@ -66,6 +66,17 @@ func getPublicKey(signID int64, ecosystemID int64, pubkey []byte, w http.Respons
 	return publicKey, nil
 }
 
+// getKeyType returns the type of the registered key, the unknown keys are ECDSA keys
+func getKeyType(signID int64, ecosystemID int64, w http.ResponseWriter, logger *log.Entry) (int64, error) {
+	key := &model.Key{}
+	key.SetTablePrefix(ecosystemID)
+	if _, err := key.Get(signID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting type of key from keys")
+		return 0, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return key.KeyType, nil
+}
+
 func getDataMultiRequestParams(fields []*script.FieldInfo, params map[string]string, w http.ResponseWriter, logger *log.Entry) ([]byte, error) {
 	idata := []byte{}
 	var err error
@@ -191,6 +202,7 @@ type contractMultiRequest struct {
 	MaxSum         string   `json:"max_sum"`
 	Payover        string   `json:"payover"`
 	SignedBy       string   `json:"signed_by"`
+	Nonce          string   `json:"nonce"`
 	Signatures     []string `json:"signatures"`
 	Time           string   `json:"time"`
 }
@@ -229,6 +241,10 @@ func (c *contractHandlers) contractMulti(w http.ResponseWriter, r *http.Request,
 	if err != nil {
 		return err
 	}
+	keyType, err := getKeyType(signID, data.ecosystemId, w, logger)
+	if err != nil {
+		return err
+	}
 	signatures := multiRequest.Signatures
 	if len(signatures) == 0 {
 		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("signatures is empty")
@@ -237,6 +253,7 @@ func (c *contractHandlers) contractMulti(w http.ResponseWriter, r *http.Request,
 	tokenEcosystem := converter.StrToInt64(multiRequest.TokenEcosystem)
 	maxSum := multiRequest.MaxSum
 	payover := multiRequest.Payover
+	nonce := converter.StrToInt64(multiRequest.Nonce)
 	hashes := []string{}
 	for i, c := range req.Contracts {
 		contract := smart.VMGetContract(data.vm, c.Contract, uint32(data.ecosystemId))
@@ -267,6 +284,7 @@ func (c *contractHandlers) contractMulti(w http.ResponseWriter, r *http.Request,
 				PublicKey:     publicKey,
 				NetworkID:     consts.NETWORK_ID,
 				BinSignatures: converter.EncodeLengthPlusData(signatureBytes),
+				KeyType:       keyType,
 			},
 			RequestID:      req.ID,
 			TokenEcosystem: tokenEcosystem,
@@ -275,6 +293,9 @@ func (c *contractHandlers) contractMulti(w http.ResponseWriter, r *http.Request,
 			SignedBy:       signedBy,
 			Data:           idata,
 		}
+		if nonce != 0 {
+			toSerialize.Header.Nonce = nonce + int64(i)
+		}
 		serializedData, err := msgpack.Marshal(toSerialize)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
@@ -326,6 +347,11 @@ func (c *contractHandlers) contract(w http.ResponseWriter, r *http.Request, data
 		return err
 	}
 
+	keyType, err := getKeyType(signID, data.ecosystemId, w, logger)
+	if err != nil {
+		return err
+	}
+
 	signature := data.params[`signature`].([]byte)
 	if len(signature) == 0 {
 		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("signature is empty")
@@ -338,7 +364,7 @@ func (c *contractHandlers) contract(w http.ResponseWriter, r *http.Request, data
 			return err
 		}
 	}
-	toSerialize = tx.SmartContract{
+	smartTx := tx.SmartContract{
 		Header: tx.Header{
 			Type:          int(info.ID),
 			Time:          converter.StrToInt64(data.params[`time`].(string)),
@@ -348,6 +374,8 @@ func (c *contractHandlers) contract(w http.ResponseWriter, r *http.Request, data
 			PublicKey:     publicKey,
 			NetworkID:     consts.NETWORK_ID,
 			BinSignatures: converter.EncodeLengthPlusData(signature),
+			Nonce:         data.params[`nonce`].(int64),
+			KeyType:       keyType,
 		},
 		RequestID:      req.ID,
 		TokenEcosystem: data.params[`token_ecosystem`].(int64),
@@ -356,6 +384,17 @@ func (c *contractHandlers) contract(w http.ResponseWriter, r *http.Request, data
 		SignedBy:       signedBy,
 		Data:           idata,
 	}
+	if multisig := data.ParamString(`multisig`); len(multisig) > 0 {
+		// the transaction of multisig wallet waits for the signatures of other keys of the wallet
+		if smartTx.KeyID = converter.StringToAddress(multisig); smartTx.KeyID == 0 {
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, multisig)
+		}
+		smartTx.PublicKey = nil
+		smartTx.BinSignatures = nil
+		smartTx.KeyType = 0
+		return proposeMultisig(w, data, logger, &smartTx, signature)
+	}
+	toSerialize = smartTx
 	serializedData, err := msgpack.Marshal(toSerialize)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
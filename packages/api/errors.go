@@ -18,40 +18,58 @@ package api
 
 var (
 	apiErrors = map[string]string{
-		`E_CONTRACT`:        `There is not %s contract`,
-		`E_DBNIL`:           `DB is nil`,
-		`E_DELETEDKEY`:      `The key is deleted`,
-		`E_ECOSYSTEM`:       `Ecosystem %d doesn't exist`,
-		`E_EMPTYPUBLIC`:     `Public key is undefined`,
-		`E_EMPTYSIGN`:       `Signature is undefined`,
-		`E_HASHWRONG`:       `Hash is incorrect`,
-		`E_HASHNOTFOUND`:    `Hash has not been found`,
-		`E_HEAVYPAGE`:       `This page is heavy`,
-		`E_INSTALLED`:       `Apla is already installed`,
-		`E_INVALIDWALLET`:   `Wallet %s is not valid`,
-		`E_LIMITFORSIGN`:    `Length of forsign is too big (%d)`,
-		`E_LIMITTXSIZE`:     `The size of tx is too big (%d)`,
-		`E_NOTFOUND`:        `Page not found`,
-		`E_NOTINSTALLED`:    `Apla is not installed`,
-		`E_PARAMNOTFOUND`:   `Parameter %s has not been found`,
-		`E_PERMISSION`:      `Permission denied`,
-		`E_QUERY`:           `DB query is wrong`,
-		`E_RECOVERED`:       `API recovered`,
-		`E_REFRESHTOKEN`:    `Refresh token is not valid`,
-		`E_SERVER`:          `Server error`,
-		`E_SIGNATURE`:       `Signature is incorrect`,
-		`E_UNKNOWNSIGN`:     `Unknown signature`,
-		`E_STATELOGIN`:      `%s is not a membership of ecosystem %s`,
-		`E_TABLENOTFOUND`:   `Table %s has not been found`,
-		`E_TOKEN`:           `Token is not valid`,
-		`E_TOKENEXPIRED`:    `Token is expired by %s`,
-		`E_UNAUTHORIZED`:    `Unauthorized`,
-		`E_UNDEFINEVAL`:     `Value %s is undefined`,
-		`E_UNKNOWNUID`:      `Unknown uid`,
-		`E_VDE`:             `Virtual Dedicated Ecosystem %d doesn't exist`,
-		`E_VDECREATED`:      `Virtual Dedicated Ecosystem is already created`,
-		`E_REQUESTNOTFOUND`: `Request %s doesn't exist`,
-		`E_UPDATING`:        `Node is updating blockchain`,
-		`E_STOPPING`:        `Network is stopping`,
+		`E_APPNOTFOUND`:      `Application %d has not been found`,
+		`E_BUNDLE`:           `Bundle is wrong: %s`,
+		`E_CDCDISABLED`:      `Change data capture is disabled`,
+		`E_CONTENTFORMAT`:    `Unknown content format %s`,
+		`E_CONTRACT`:         `There is not %s contract`,
+		`E_DBNIL`:            `DB is nil`,
+		`E_DELETEDKEY`:       `The key is deleted`,
+		`E_ECOSYSTEM`:        `Ecosystem %d doesn't exist`,
+		`E_EMPTYPUBLIC`:      `Public key is undefined`,
+		`E_EMPTYSIGN`:        `Signature is undefined`,
+		`E_EXPORTFORMAT`:     `Unknown export format %s`,
+		`E_HASHWRONG`:        `Hash is incorrect`,
+		`E_HASHNOTFOUND`:     `Hash has not been found`,
+		`E_HEAVYPAGE`:        `This page is heavy`,
+		`E_HISTORYLIMIT`:     `There are more than %[2]d changes after block %[1]d`,
+		`E_HISTORYVDE`:       `History of changes is not supported by VDE`,
+		`E_INSTALLED`:        `Apla is already installed`,
+		`E_INVALIDBLOCK`:     `Block %d is not valid`,
+		`E_INVALIDWALLET`:    `Wallet %s is not valid`,
+		`E_KEYTYPE`:          `Key type %d isn't supported`,
+		`E_LANGFILE`:         `Translation file is wrong: %s`,
+		`E_LANGFORMAT`:       `Unknown translation format %s`,
+		`E_LIMITFORSIGN`:     `Length of forsign is too big (%d)`,
+		`E_LIMITTXSIZE`:      `The size of tx is too big (%d)`,
+		`E_MULTISIGEXISTS`:   `Multisig transaction %s already exists`,
+		`E_MULTISIGKEY`:      `Key %s is not the key of multisig wallet`,
+		`E_MULTISIGNOTFOUND`: `Multisig wallet %s has not been found`,
+		`E_MULTISIGSENT`:     `Multisig transaction has been already sent as %s`,
+		`E_MULTISIGSIGNED`:   `Multisig transaction has been already signed by %s`,
+		`E_NOTFOUND`:         `Page not found`,
+		`E_NOTINSTALLED`:     `Apla is not installed`,
+		`E_PARAMNOTFOUND`:    `Parameter %s has not been found`,
+		`E_PERMISSION`:       `Permission denied`,
+		`E_QUERY`:            `DB query is wrong`,
+		`E_RECOVERED`:        `API recovered`,
+		`E_REFRESHTOKEN`:     `Refresh token is not valid`,
+		`E_SEARCHSOURCE`:     `Unknown search source %s`,
+		`E_SERVER`:           `Server error`,
+		`E_SIGNATURE`:        `Signature is incorrect`,
+		`E_UNKNOWNSIGN`:      `Unknown signature`,
+		`E_STATELOGIN`:       `%s is not a membership of ecosystem %s`,
+		`E_THEME`:            `Theme %s has not been found`,
+		`E_TABLENOTFOUND`:    `Table %s has not been found`,
+		`E_TOKEN`:            `Token is not valid`,
+		`E_TOKENEXPIRED`:     `Token is expired by %s`,
+		`E_UNAUTHORIZED`:     `Unauthorized`,
+		`E_UNDEFINEVAL`:      `Value %s is undefined`,
+		`E_UNKNOWNUID`:       `Unknown uid`,
+		`E_VDE`:              `Virtual Dedicated Ecosystem %d doesn't exist`,
+		`E_VDECREATED`:       `Virtual Dedicated Ecosystem is already created`,
+		`E_REQUESTNOTFOUND`:  `Request %s doesn't exist`,
+		`E_UPDATING`:         `Node is updating blockchain`,
+		`E_STOPPING`:         `Network is stopping`,
 	}
 )
@@ -0,0 +1,238 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	xl "github.com/360EntSecGroup-Skylar/excelize"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	exportCSV   = `csv`
+	exportXLSX  = `xlsx`
+	exportJSONL = `jsonl`
+
+	exportSheet = `Sheet1`
+)
+
+// exportWriter writes rows of the exported table in some format. Header is called once before
+// the rows, also for the empty table.
+type exportWriter interface {
+	Header(columns []string) error
+	Write(columns []string, row map[string]string) error
+	Close() error
+}
+
+type csvExport struct {
+	w *csv.Writer
+}
+
+func (e *csvExport) Header(columns []string) error {
+	return e.w.Write(columns)
+}
+
+func (e *csvExport) Write(columns []string, row map[string]string) error {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = row[col]
+	}
+	return e.w.Write(values)
+}
+
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExport struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExport) Header(columns []string) error {
+	return nil
+}
+
+func (e *jsonlExport) Write(columns []string, row map[string]string) error {
+	return e.enc.Encode(row)
+}
+
+func (e *jsonlExport) Close() error {
+	return nil
+}
+
+// xlsxExport keeps the workbook in memory because XLSX can't be written as a stream
+type xlsxExport struct {
+	w    io.Writer
+	book *xl.File
+	line int
+}
+
+func (e *xlsxExport) Header(columns []string) error {
+	e.line++
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col
+	}
+	e.book.SetSheetRow(exportSheet, `A1`, &header)
+	return nil
+}
+
+func (e *xlsxExport) Write(columns []string, row map[string]string) error {
+	e.line++
+	values := make([]interface{}, len(columns))
+	for i, col := range columns {
+		values[i] = row[col]
+	}
+	e.book.SetSheetRow(exportSheet, fmt.Sprintf(`A%d`, e.line), &values)
+	return nil
+}
+
+func (e *xlsxExport) Close() error {
+	return e.book.Write(e.w)
+}
+
+func newExportWriter(format string, w http.ResponseWriter) exportWriter {
+	switch format {
+	case exportCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		return &csvExport{w: csv.NewWriter(w)}
+	case exportXLSX:
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		return &xlsxExport{w: w, book: xl.NewFile()}
+	case exportJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		return &jsonlExport{enc: json.NewEncoder(w)}
+	}
+	return nil
+}
+
+// exportDisposition returns Content-Disposition header of the exported file with the escaped name
+func exportDisposition(name, format string) string {
+	disposition := mime.FormatMediaType(`attachment`, map[string]string{`filename`: name + `.` + format})
+	if len(disposition) == 0 {
+		return `attachment`
+	}
+	return disposition
+}
+
+// exportTable streams the rows of the table which can be read by the caller.
+// The X-Block-Id header contains the block which can be passed as the block parameter
+// to get the same data later.
+func exportTable(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	format := data.ParamString(`format`)
+	if len(format) == 0 {
+		format = exportCSV
+	}
+	if format != exportCSV && format != exportXLSX && format != exportJSONL {
+		return errorAPI(w, `E_EXPORTFORMAT`, http.StatusBadRequest, format)
+	}
+	name := data.ParamString(`name`)
+	tblname := getPrefix(data) + `_` + strings.Trim(converter.EscapeName(name), `"`)
+	if !model.IsTable(tblname) {
+		return errorAPI(w, `E_TABLENOTFOUND`, http.StatusBadRequest, name)
+	}
+
+	sc := getSmartContract(data)
+	columns := []string{`*`}
+	if len(data.ParamString(`columns`)) > 0 {
+		columns = append([]string{`id`}, strings.Split(data.ParamString(`columns`), `,`)...)
+		for i, col := range columns {
+			columns[i] = strings.Trim(converter.EscapeName(strings.TrimSpace(col)), `"`)
+		}
+	}
+	if _, err := sc.AccessTablePerm(tblname, `read`); err != nil {
+		return errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+	}
+	if err := sc.AccessColumns(tblname, &columns, false); err != nil {
+		return errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+	}
+	cols := `*`
+	if columns[0] != `*` {
+		cols = `"` + strings.Join(columns, `","`) + `"`
+	}
+
+	// The rows, the rollback records and the block are read from the same snapshot of the database
+	transaction, err := model.StartSnapshotTransaction()
+	if err != nil {
+		return errorAPI(w, `E_SERVER`, http.StatusInternalServerError)
+	}
+	defer transaction.Rollback()
+
+	blockID := data.ParamInt64(`block`)
+	if blockID == 0 {
+		info := &model.InfoBlock{}
+		if _, err := info.GetTransaction(transaction); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+			return errorAPI(w, `E_SERVER`, http.StatusInternalServerError)
+		}
+		blockID = info.BlockID
+	}
+	where := ``
+	var asOf *tableAsOf
+	if data.ParamInt64(`block`) != 0 {
+		if asOf, err = getTableAsOfBlock(w, data, transaction, tblname, ``, logger); err != nil {
+			return err
+		}
+		where = asOf.exclude()
+	}
+	query := `select ` + cols + ` from "` + tblname + `"` + where + ` order by id` +
+		fmt.Sprintf(` offset %d`, data.ParamInt64(`offset`))
+	if limit := data.ParamInt64(`limit`); limit > 0 {
+		query += fmt.Sprintf(` limit %d`, limit)
+	}
+
+	// The headers are written when the query has been executed successfully,
+	// so the wrong query is reported as the error
+	var writer exportWriter
+	err = model.ProcessRowsHeader(transaction, query, func(columns []string) error {
+		w.Header().Set("Content-Disposition", exportDisposition(name, format))
+		w.Header().Set("X-Block-Id", converter.Int64ToStr(blockID))
+		writer = newExportWriter(format, w)
+		data.raw = true
+		return writer.Header(columns)
+	}, func(columns []string, row map[string]string) error {
+		if asOf != nil {
+			if row = asOf.revert(row); row == nil {
+				return nil
+			}
+		}
+		return writer.Write(columns, row)
+	})
+	if err != nil && writer == nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": tblname}).Error("exporting table")
+		return errorAPI(w, `E_QUERY`, http.StatusBadRequest)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": tblname}).Error("exporting table")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	if err := keyLogin(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	out, err := sendRawRequest(`GET`, `export/contracts?columns=name&limit=5`, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(rows) != 6 || strings.Join(rows[0], `,`) != `id,name` {
+		t.Errorf(`wrong csv export %s`, out)
+	}
+
+	out, err = sendRawRequest(`GET`, `export/contracts?columns=name&offset=1000000`, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.TrimSpace(string(out)) != `id,name` {
+		t.Errorf(`wrong csv export of empty result %s`, out)
+	}
+
+	out, err = sendRawRequest(`GET`, `export/contracts?columns=name&limit=5&format=jsonl`, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 5 {
+		t.Errorf(`wrong jsonl export %s`, out)
+	}
+	for _, line := range lines {
+		var row map[string]string
+		if err = json.Unmarshal([]byte(line), &row); err != nil || len(row[`name`]) == 0 {
+			t.Errorf(`wrong jsonl row %s`, line)
+		}
+	}
+
+	_, err = sendRawRequest(`GET`, `export/contracts?format=qwerty`, nil)
+	if err == nil || err.Error() != `400 {"error": "E_EXPORTFORMAT", "msg": "Unknown export format qwerty" , "params": ["qwerty"]}` {
+		t.Error(err)
+	}
+
+	_, err = sendRawRequest(`GET`, `export/contracts?columns=qwerty`, nil)
+	if err == nil || err.Error() != `400 {"error": "E_QUERY", "msg": "DB query is wrong" }` {
+		t.Error(err)
+	}
+}
+
+func TestExportWriterHeader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writer := newExportWriter(exportCSV, rec)
+	if err := writer.Header([]string{`id`, `name`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Body.String() != "id,name\n" {
+		t.Errorf(`wrong header of empty csv %q`, rec.Body.String())
+	}
+}
+
+func TestExportDisposition(t *testing.T) {
+	if out := exportDisposition(`orders`, exportCSV); out != `attachment; filename=orders.csv` {
+		t.Errorf(`wrong disposition %s`, out)
+	}
+	if out := exportDisposition(`a"b;c`, exportCSV); out != `attachment; filename="a\"b;c.csv"` {
+		t.Errorf(`wrong escaped disposition %s`, out)
+	}
+}
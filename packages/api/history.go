@@ -17,15 +17,27 @@
 package api
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	log "github.com/sirupsen/logrus"
 )
 
-const rollbackHistoryLimit = 100
+const (
+	rollbackHistoryLimit = 100
+	// rollbackAsOfLimit is the maximum number of rollback records which can be applied
+	// to reconstruct the state of a table at the specified block. The cost of the request
+	// is O(rollbackAsOfLimit) regardless of the size of the table.
+	rollbackAsOfLimit = 10000
+)
+
+var errHistoryLimit = errors.New("too many changes after block")
 
 type historyResult struct {
 	List []map[string]string `json:"list"`
@@ -55,3 +67,104 @@ func getHistory(w http.ResponseWriter, r *http.Request, data *apiData, logger *l
 	data.result = &historyResult{rollbackList}
 	return nil
 }
+
+// tableAsOf contains the changes of the table which have been made after the block
+type tableAsOf struct {
+	table    string
+	blockID  int64
+	inserted map[string]bool
+	// updates contains the previous values of the rows sorted from the newest to the oldest
+	updates map[string][]map[string]string
+}
+
+// getTableAsOf loads the rollback records of the table (or of the one row if id is not empty)
+// which have been written after blockID
+func getTableAsOf(transaction *model.DbTransaction, table, id string, blockID int64, logger *log.Entry) (*tableAsOf, error) {
+	rollbackTx := &model.RollbackTx{}
+	txs, err := rollbackTx.GetRollbackTxsAfterBlock(transaction, table, id, blockID, rollbackAsOfLimit+1)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("getting rollback records after block")
+		return nil, err
+	}
+	if len(txs) > rollbackAsOfLimit {
+		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "table": table, "block_id": blockID}).Error("too many changes after block")
+		return nil, errHistoryLimit
+	}
+	asOf := &tableAsOf{
+		table:    table,
+		blockID:  blockID,
+		inserted: make(map[string]bool),
+		updates:  make(map[string][]map[string]string),
+	}
+	for _, tx := range txs {
+		if tx.Data == "" {
+			asOf.inserted[tx.TableID] = true
+			continue
+		}
+		rollback := map[string]string{}
+		if err := json.Unmarshal([]byte(tx.Data), &rollback); err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollbackTx.Data from JSON")
+			return nil, err
+		}
+		asOf.updates[tx.TableID] = append(asOf.updates[tx.TableID], rollback)
+	}
+	return asOf, nil
+}
+
+// insertedIDs returns the identifiers of rows which didn't exist at the block
+func (t *tableAsOf) insertedIDs() []string {
+	ids := make([]string, 0, len(t.inserted))
+	for id := range t.inserted {
+		ids = append(ids, converter.Int64ToStr(converter.StrToInt64(id)))
+	}
+	return ids
+}
+
+// exclude returns SQL condition which excludes rows inserted after the block
+func (t *tableAsOf) exclude() string {
+	if len(t.inserted) == 0 {
+		return ``
+	}
+	return ` where id not in (` + strings.Join(t.insertedIDs(), `,`) + `)`
+}
+
+// revert returns the row as it was at the block. It returns nil if the row didn't exist.
+func (t *tableAsOf) revert(row map[string]string) map[string]string {
+	id := row[`id`]
+	if t.inserted[id] {
+		return nil
+	}
+	for _, rollback := range t.updates[id] {
+		for k, v := range rollback {
+			if _, ok := row[k]; !ok {
+				continue
+			}
+			if converter.IsByteColumn(t.table, k) && len(v) > 0 {
+				if bin, err := hex.DecodeString(v); err == nil {
+					v = string(bin)
+				}
+			}
+			row[k] = v
+		}
+	}
+	return row
+}
+
+// getTableAsOfBlock checks the block parameter and returns the changes of the table after this block
+func getTableAsOfBlock(w http.ResponseWriter, data *apiData, transaction *model.DbTransaction, table, id string,
+	logger *log.Entry) (*tableAsOf, error) {
+	blockID := data.ParamInt64(`block`)
+	if data.vde {
+		return nil, errorAPI(w, `E_HISTORYVDE`, http.StatusBadRequest)
+	}
+	if blockID < 0 {
+		return nil, errorAPI(w, `E_INVALIDBLOCK`, http.StatusBadRequest, blockID)
+	}
+	asOf, err := getTableAsOf(transaction, table, id, blockID, logger)
+	if err == errHistoryLimit {
+		return nil, errorAPI(w, `E_HISTORYLIMIT`, http.StatusBadRequest, blockID, rollbackAsOfLimit)
+	} else if err != nil {
+		return nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return asOf, nil
+}
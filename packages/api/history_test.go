@@ -19,6 +19,8 @@ package api
 import (
 	stdErrors "errors"
 	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
 )
 
 func TestHistory(t *testing.T) {
@@ -46,3 +48,34 @@ func TestHistory(t *testing.T) {
 		t.Error(stdErrors.New("History should be empty"))
 	}
 }
+
+func TestHistoryAsOfBlock(t *testing.T) {
+	if err := keyLogin(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var ret rowResult
+	if err := sendGet("row/pages/1?block=1", nil, &ret); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var cur, past listResult
+	if err := sendGet("list/pages", nil, &cur); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := sendGet("list/pages?block=1", nil, &past); err != nil {
+		t.Error(err)
+		return
+	}
+	if converter.StrToInt64(past.Count) > converter.StrToInt64(cur.Count) {
+		t.Errorf("wrong count of pages at block 1: %s > %s", past.Count, cur.Count)
+	}
+
+	err := sendGet("list/pages?block=-1", nil, &past)
+	if err == nil || err.Error() != `400 {"error": "E_INVALIDBLOCK", "msg": "Block -1 is not valid" , "params": ["-1"]}` {
+		t.Error(err)
+	}
+}
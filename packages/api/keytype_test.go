@@ -0,0 +1,52 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/url"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginEd25519(t *testing.T) {
+	priv, pub, err := crypto.KeyEd25519.GenerateKeys()
+	require.NoError(t, err)
+
+	var ret getUIDResult
+	require.NoError(t, sendGet(`getuid`, nil, &ret))
+	gAuth = ret.Token
+
+	sign, err := crypto.KeyEd25519.Sign(hex.EncodeToString(priv), nonceSalt+ret.UID)
+	require.NoError(t, err)
+
+	form := url.Values{"pubkey": {hex.EncodeToString(pub)}, "signature": {hex.EncodeToString(sign)},
+		`ecosystem`: {`1`}, `key_type`: {converter.Int64ToStr(int64(crypto.KeyEd25519))}}
+	var logret loginResult
+	require.NoError(t, sendPost(`login`, &form, &logret))
+	assert.Equal(t, converter.AddressToString(crypto.KeyEd25519.Address(pub)), logret.Address)
+
+	form[`key_type`] = []string{`100`}
+	require.NoError(t, sendGet(`getuid`, nil, &ret))
+	gAuth = ret.Token
+	assert.EqualError(t, sendPost(`login`, &form, &logret), `400 {"error": "E_KEYTYPE", "msg": "Key type 100 isn't supported" , "params": ["100"]}`)
+}
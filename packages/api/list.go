@@ -48,17 +48,32 @@ func list(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Ent
 		return errorAPI(w, `E_TABLENOTFOUND`, http.StatusBadRequest, data.params[`name`].(string))
 	}
 
+	var asOf *tableAsOf
+	where := ``
+	if data.ParamInt64(`block`) != 0 {
+		if asOf, err = getTableAsOfBlock(w, data, nil, strings.Trim(table, `"`), ``, logger); err != nil {
+			return err
+		}
+		where = asOf.exclude()
+		count -= int64(len(asOf.inserted))
+	}
+
 	if data.params[`limit`].(int64) > 0 {
 		limit = int(data.params[`limit`].(int64))
 	} else {
 		limit = 25
 	}
-	list, err := model.GetAll(`select `+cols+` from `+table+` order by id desc`+
+	list, err := model.GetAll(`select `+cols+` from `+table+where+` order by id desc`+
 		fmt.Sprintf(` offset %d `, data.params[`offset`].(int64)), limit)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting rows from table")
 		return errorAPI(w, err.Error(), http.StatusInternalServerError)
 	}
+	if asOf != nil {
+		for i := range list {
+			list[i] = asOf.revert(list[i])
+		}
+	}
 	data.result = &listResult{
 		Count: converter.Int64ToStr(count), List: list,
 	}
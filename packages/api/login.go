@@ -35,8 +35,8 @@ import (
 	"encoding/json"
 
 	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 	"github.com/dgrijalva/jwt-go"
 	log "github.com/sirupsen/logrus"
@@ -92,10 +92,15 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		ecosystemID = 1
 	}
 
+	keyType := crypto.KeyType(data.params[`key_type`].(int64))
+	if _, err = keyType.Scheme(); err != nil {
+		return errorAPI(w, `E_KEYTYPE`, http.StatusBadRequest, int64(keyType))
+	}
+
 	if len(data.params[`key_id`].(string)) > 0 {
 		wallet = converter.StringToAddress(data.params[`key_id`].(string))
 	} else if len(data.params[`pubkey`].([]byte)) > 0 {
-		wallet = crypto.Address(data.params[`pubkey`].([]byte))
+		wallet = keyType.Address(data.params[`pubkey`].([]byte))
 	}
 
 	account := &model.Key{}
@@ -108,6 +113,7 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 
 	if isAccount {
 		pubkey = account.PublicKey
+		keyType = crypto.KeyType(account.KeyType)
 		if account.Deleted == 1 {
 			return errorAPI(w, `E_DELETEDKEY`, http.StatusForbidden)
 		}
@@ -117,11 +123,9 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 			logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("public key is empty")
 			return errorAPI(w, `E_EMPTYPUBLIC`, http.StatusBadRequest)
 		}
-		NodePrivateKey, NodePublicKey, err := utils.GetNodeKeys()
-		if err != nil || len(NodePrivateKey) < 1 {
-			if err == nil {
-				log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-			}
+		NodePublicKey, err := signer.Node().PublicKey()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
 			return err
 		}
 
@@ -129,8 +133,13 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		hexPubKey := hex.EncodeToString(pubkey)
 		params := converter.EncodeLength(int64(len(hexPubKey)))
 		params = append(params, hexPubKey...)
+		signParams := []string{hexPubKey}
 
 		contract := smart.GetContract("NewUser", 1)
+		if hasTxField(contract, `KeyType`) {
+			converter.EncodeLenInt64(&params, int64(keyType))
+			signParams = append(signParams, converter.Int64ToStr(int64(keyType)))
+		}
 
 		sc := tx.SmartContract{
 			Header: tx.Header{
@@ -148,9 +157,9 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		if conf.Config.IsSupportingVDE() {
 
 			signPrms := []string{sc.ForSign()}
-			signPrms = append(signPrms, hexPubKey)
+			signPrms = append(signPrms, signParams...)
 			signData := strings.Join(signPrms, ",")
-			signature, err := crypto.Sign(NodePrivateKey, signData)
+			signature, err := signer.Node().Sign(signData)
 			if err != nil {
 				log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing by node private key")
 				return err
@@ -174,7 +183,7 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 			}
 			data.result = ret
 		} else {
-			err = tx.BuildTransaction(sc, NodePrivateKey, NodePublicKey, hexPubKey)
+			err = tx.BuildTransaction(sc, signer.Node(), signParams...)
 			if err != nil {
 				log.WithFields(log.Fields{"type": consts.ContractError}).Error("Executing contract")
 			}
@@ -209,7 +218,7 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		}
 	}
 
-	verify, err := crypto.CheckSign(pubkey, nonceSalt+msg, data.params[`signature`].([]byte))
+	verify, err := keyType.CheckSign(pubkey, nonceSalt+msg, data.params[`signature`].([]byte))
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.CryptoError, "pubkey": pubkey, "msg": msg, "signature": string(data.params["signature"].([]byte))}).Error("checking signature")
 		return errorAPI(w, err, http.StatusBadRequest)
@@ -220,7 +229,7 @@ func login(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.En
 		return errorAPI(w, `E_SIGNATURE`, http.StatusBadRequest)
 	}
 
-	address := crypto.KeyToAddress(pubkey)
+	address := converter.AddressToString(keyType.Address(pubkey))
 
 	var (
 		sp      model.StateParameter
@@ -334,3 +343,16 @@ func checkRoleFromParam(role, ecosystemID, wallet int64) (int64, error) {
 	}
 	return role, nil
 }
+
+// hasTxField returns whether the contract has the data field with the specified name
+func hasTxField(contract *smart.Contract, name string) bool {
+	if contract == nil || contract.Block.Info.(*script.ContractInfo).Tx == nil {
+		return false
+	}
+	for _, fitem := range *contract.Block.Info.(*script.ContractInfo).Tx {
+		if fitem.Name == name {
+			return true
+		}
+	}
+	return false
+}
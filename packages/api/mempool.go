@@ -0,0 +1,131 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/mempool"
+	"github.com/GenesisKernel/go-genesis/packages/transaction"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	mempoolDefaultLimit = 100
+	mempoolMaxLimit     = 1000
+)
+
+type mempoolTxResult struct {
+	Hash      string `json:"hash"`
+	KeyID     string `json:"key_id"`
+	RequestID string `json:"request_id,omitempty"`
+	Type      int64  `json:"type"`
+	Seq       int64  `json:"seq"`
+	Nonce     int64  `json:"nonce,omitempty"`
+	PayOver   string `json:"payover"`
+	MaxSum    string `json:"max_sum"`
+	Size      int    `json:"size"`
+	Arrival   int64  `json:"arrival"`
+	// Position is the place of the transaction in the queue for the block
+	Position int `json:"position"`
+}
+
+type mempoolResult struct {
+	mempool.Stats
+	List []*mempoolTxResult `json:"list"`
+}
+
+func newMempoolTxResult(tx *mempool.Tx, position int) *mempoolTxResult {
+	return &mempoolTxResult{
+		Hash:      hex.EncodeToString(tx.Hash),
+		KeyID:     converter.Int64ToStr(tx.KeyID),
+		RequestID: tx.RequestID,
+		Type:      tx.Type,
+		Seq:       tx.Seq,
+		Nonce:     tx.Nonce,
+		PayOver:   tx.Fee.PayOver.String(),
+		MaxSum:    tx.Fee.MaxSum.String(),
+		Size:      len(tx.Data),
+		Arrival:   tx.Arrival.Unix(),
+		Position:  position,
+	}
+}
+
+// mempoolPositions returns the places of transactions in the queue for the block
+func mempoolPositions(list []*mempool.Tx) map[string]int {
+	positions := make(map[string]int, len(list))
+	for i, tx := range list {
+		positions[string(tx.Hash)] = i + 1
+	}
+	return positions
+}
+
+func getMempool(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	limit := int(data.ParamInt64(`limit`))
+	if limit <= 0 {
+		limit = mempoolDefaultLimit
+	} else if limit > mempoolMaxLimit {
+		limit = mempoolMaxLimit
+	}
+	offset := int(data.ParamInt64(`offset`))
+	if offset < 0 {
+		offset = 0
+	}
+
+	pool := mempool.GetPool()
+	queue := pool.Select(0, transaction.NextNonce)
+	positions := mempoolPositions(queue)
+
+	list := queue
+	if keyID := data.ParamInt64(`key_id`); keyID != 0 {
+		list = pool.Account(keyID)
+	}
+	if offset > len(list) {
+		offset = len(list)
+	}
+	list = list[offset:]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	result := &mempoolResult{Stats: pool.Stats(), List: make([]*mempoolTxResult, 0, len(list))}
+	for _, tx := range list {
+		result.List = append(result.List, newMempoolTxResult(tx, positions[string(tx.Hash)]))
+	}
+	data.result = result
+	return nil
+}
+
+func getMempoolTx(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	hash, err := hex.DecodeString(data.params[`hash`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding tx hash from hex")
+		return errorAPI(w, `E_HASHWRONG`, http.StatusBadRequest)
+	}
+
+	pool := mempool.GetPool()
+	tx, ok := pool.Get(hash)
+	if !ok {
+		return errorAPI(w, `E_HASHNOTFOUND`, http.StatusNotFound)
+	}
+	data.result = newMempoolTxResult(tx, mempoolPositions(pool.Select(0, transaction.NextNonce))[string(hash)])
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMempool(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	var ret mempoolResult
+	require.NoError(t, sendGet(`mempool`, &url.Values{"limit": {"10"}}, &ret))
+	assert.True(t, ret.MaxCount > 0)
+	assert.True(t, ret.MaxSize > 0)
+	assert.True(t, len(ret.List) <= 10)
+	for i, tx := range ret.List {
+		assert.Equal(t, i+1, tx.Position)
+	}
+
+	keyID := converter.Int64ToStr(converter.StringToAddress(gAddress))
+	require.NoError(t, sendGet(`mempool`, &url.Values{"key_id": {keyID}}, &ret))
+	for _, tx := range ret.List {
+		assert.Equal(t, keyID, tx.KeyID)
+	}
+
+	var tx mempoolTxResult
+	err := sendGet(`mempool/`+strings.Repeat(`00`, 32), nil, &tx)
+	assert.EqualError(t, err, `404 {"error": "E_HASHNOTFOUND", "msg": "Hash has not been found" }`)
+
+	err = sendGet(`mempool/wrong`, nil, &tx)
+	assert.EqualError(t, err, `400 {"error": "E_HASHWRONG", "msg": "Hash is incorrect" }`)
+}
@@ -0,0 +1,313 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+type multisigWalletResult struct {
+	ID        string   `json:"id"`
+	Address   string   `json:"address"`
+	Threshold int64    `json:"threshold"`
+	Keys      []string `json:"keys"`
+}
+
+type multisigTxResult struct {
+	Hash       string   `json:"hash"`
+	Wallet     string   `json:"wallet"`
+	Contract   string   `json:"contract"`
+	ForSign    string   `json:"forsign"`
+	Signers    []string `json:"signers"`
+	Threshold  int64    `json:"threshold"`
+	Time       string   `json:"time"`
+	Expiration string   `json:"expiration"`
+	TxHash     string   `json:"tx_hash,omitempty"`
+}
+
+type multisigTxsResult struct {
+	List []*multisigTxResult `json:"list"`
+}
+
+func getMultisigWallet(w http.ResponseWriter, data *apiData, logger *log.Entry, walletID int64) (*model.MultisigWallet, error) {
+	wallet := &model.MultisigWallet{}
+	wallet.SetTablePrefix(data.ecosystemId)
+	if !model.IsTable(wallet.TableName()) {
+		return nil, errorAPI(w, `E_MULTISIGNOTFOUND`, http.StatusNotFound, converter.AddressToString(walletID))
+	}
+	found, err := wallet.Get(nil, walletID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multisig wallet")
+		return nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found {
+		return nil, errorAPI(w, `E_MULTISIGNOTFOUND`, http.StatusNotFound, converter.AddressToString(walletID))
+	}
+	return wallet, nil
+}
+
+func newMultisigTxResult(mtx *model.MultisigTx, wallet *model.MultisigWallet, smartTx *tx.SmartContract) (*multisigTxResult, error) {
+	forsign, err := smart.MultisigForSign(smartTx)
+	if err != nil {
+		return nil, err
+	}
+	result := &multisigTxResult{
+		Hash:       hex.EncodeToString(mtx.Hash),
+		Wallet:     converter.AddressToString(wallet.ID),
+		ForSign:    forsign,
+		Signers:    make([]string, len(smartTx.Signers)),
+		Threshold:  wallet.Threshold,
+		Time:       converter.Int64ToStr(smartTx.Time),
+		Expiration: converter.Int64ToStr(smartTx.Time + consts.MAX_TX_BACK),
+	}
+	if contract := smart.GetContractByID(int32(smartTx.Type)); contract != nil {
+		result.Contract = contract.Name
+	}
+	for i, keyID := range smartTx.Signers {
+		result.Signers[i] = converter.AddressToString(keyID)
+	}
+	if len(mtx.TxHash) > 0 {
+		result.TxHash = hex.EncodeToString(mtx.TxHash)
+	}
+	return result, nil
+}
+
+// addMultisigSign appends the signature of the current key to the transaction of the multisig wallet.
+// The transaction is sent to the blockchain when it has the threshold of signatures
+func addMultisigSign(w http.ResponseWriter, data *apiData, logger *log.Entry, mtx *model.MultisigTx,
+	wallet *model.MultisigWallet, smartTx *tx.SmartContract, signature []byte) error {
+
+	if !wallet.HasKey(data.keyId) {
+		return errorAPI(w, `E_MULTISIGKEY`, http.StatusForbidden, converter.AddressToString(data.keyId))
+	}
+	for _, keyID := range smartTx.Signers {
+		if keyID == data.keyId {
+			return errorAPI(w, `E_MULTISIGSIGNED`, http.StatusBadRequest, converter.AddressToString(data.keyId))
+		}
+	}
+	if len(mtx.TxHash) > 0 {
+		return errorAPI(w, `E_MULTISIGSENT`, http.StatusBadRequest, hex.EncodeToString(mtx.TxHash))
+	}
+
+	key := &model.Key{}
+	key.SetTablePrefix(data.ecosystemId)
+	if _, err := key.Get(data.keyId); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting public key from keys")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if len(key.PublicKey) == 0 {
+		return errorAPI(w, `E_EMPTYPUBLIC`, http.StatusBadRequest)
+	}
+	forsign, err := smart.MultisigForSign(smartTx)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if ok, err := crypto.KeyType(key.KeyType).CheckSign(key.PublicKey, forsign, signature); err != nil || !ok {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("incorrect signature of multisig transaction")
+		return errorAPI(w, `E_SIGNATURE`, http.StatusBadRequest)
+	}
+
+	signs, err := smart.SplitSignatures(smartTx.BinSignatures)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	smartTx.Signers = append(smartTx.Signers, data.keyId)
+	smartTx.BinSignatures = smart.JoinSignatures(append(signs, signature))
+
+	serializedData, err := msgpack.Marshal(smartTx)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	mtx.Data = serializedData
+	mtx.Signers = int64(len(smartTx.Signers))
+	if mtx.Signers >= wallet.Threshold {
+		if mtx.TxHash, err = model.SendTx(int64(smartTx.Type), wallet.ID,
+			append([]byte{128}, serializedData...)); err != nil {
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+	}
+	if err = mtx.Save(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving multisig transaction")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+
+	result, err := newMultisigTxResult(mtx, wallet, smartTx)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = result
+	return nil
+}
+
+// proposeMultisig creates the transaction of the multisig wallet which waits for the signatures of co-signers
+func proposeMultisig(w http.ResponseWriter, data *apiData, logger *log.Entry, smartTx *tx.SmartContract, signature []byte) error {
+	wallet, err := getMultisigWallet(w, data, logger, smartTx.KeyID)
+	if err != nil {
+		return err
+	}
+	if err = model.DeleteExpiredMultisigTxs(time.Now().Unix() - consts.MAX_TX_BACK); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting expired multisig transactions")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+
+	forsign, err := smart.MultisigForSign(smartTx)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	hash, err := crypto.Hash([]byte(forsign))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing multisig transaction")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	mtx := &model.MultisigTx{}
+	found, err := mtx.Get(hash)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multisig transaction")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if found {
+		return errorAPI(w, `E_MULTISIGEXISTS`, http.StatusBadRequest, hex.EncodeToString(hash))
+	}
+	mtx.Hash = hash
+	mtx.WalletID = wallet.ID
+	mtx.EcosystemID = data.ecosystemId
+	mtx.Time = smartTx.Time
+	return addMultisigSign(w, data, logger, mtx, wallet, smartTx, signature)
+}
+
+// getMultisigTxData returns the pending transaction of the multisig wallet by the hash from URL
+func getMultisigTxData(w http.ResponseWriter, data *apiData, logger *log.Entry) (*model.MultisigTx, *model.MultisigWallet, *tx.SmartContract, error) {
+	hash, err := hex.DecodeString(data.params[`hash`].(string))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding multisig tx hash from hex")
+		return nil, nil, nil, errorAPI(w, `E_HASHWRONG`, http.StatusBadRequest)
+	}
+	mtx := &model.MultisigTx{}
+	found, err := mtx.Get(hash)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multisig transaction")
+		return nil, nil, nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if !found || mtx.EcosystemID != data.ecosystemId {
+		return nil, nil, nil, errorAPI(w, `E_HASHNOTFOUND`, http.StatusNotFound)
+	}
+	wallet, err := getMultisigWallet(w, data, logger, mtx.WalletID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	smartTx := &tx.SmartContract{}
+	if err = msgpack.Unmarshal(mtx.Data, smartTx); err != nil {
+		logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("unmarshalling multisig transaction")
+		return nil, nil, nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return mtx, wallet, smartTx, nil
+}
+
+func getMultisigWalletInfo(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	walletID := converter.StringToAddress(data.params[`wallet`].(string))
+	if walletID == 0 {
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, data.params[`wallet`].(string))
+	}
+	wallet, err := getMultisigWallet(w, data, logger, walletID)
+	if err != nil {
+		return err
+	}
+	result := &multisigWalletResult{
+		ID:        converter.Int64ToStr(wallet.ID),
+		Address:   converter.AddressToString(wallet.ID),
+		Threshold: wallet.Threshold,
+		Keys:      make([]string, 0),
+	}
+	for _, keyID := range wallet.KeyIDs() {
+		result.Keys = append(result.Keys, converter.AddressToString(keyID))
+	}
+	data.result = result
+	return nil
+}
+
+func getMultisigTxs(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var walletID int64
+	if wallet := data.ParamString(`wallet`); len(wallet) > 0 {
+		if walletID = converter.StringToAddress(wallet); walletID == 0 {
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, wallet)
+		}
+	}
+	txs, err := model.GetMultisigTxs(data.ecosystemId, walletID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multisig transactions")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+
+	result := &multisigTxsResult{List: make([]*multisigTxResult, 0, len(txs))}
+	wallets := make(map[int64]*model.MultisigWallet)
+	for i := range txs {
+		mtx := &txs[i]
+		wallet, ok := wallets[mtx.WalletID]
+		if !ok {
+			if wallet, err = getMultisigWallet(w, data, logger, mtx.WalletID); err != nil {
+				return err
+			}
+			wallets[mtx.WalletID] = wallet
+		}
+		smartTx := &tx.SmartContract{}
+		if err = msgpack.Unmarshal(mtx.Data, smartTx); err != nil {
+			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err}).Error("unmarshalling multisig transaction")
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		item, err := newMultisigTxResult(mtx, wallet, smartTx)
+		if err != nil {
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+		result.List = append(result.List, item)
+	}
+	data.result = result
+	return nil
+}
+
+func getMultisigTx(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	mtx, wallet, smartTx, err := getMultisigTxData(w, data, logger)
+	if err != nil {
+		return err
+	}
+	result, err := newMultisigTxResult(mtx, wallet, smartTx)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = result
+	return nil
+}
+
+func signMultisigTx(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	mtx, wallet, smartTx, err := getMultisigTxData(w, data, logger)
+	if err != nil {
+		return err
+	}
+	return addMultisigSign(w, data, logger, mtx, wallet, smartTx, data.params[`signature`].([]byte))
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisig(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	keyID := converter.StringToAddress(gAddress)
+	wallet := converter.AddressToString(smart.MultisigWalletID([]int64{keyID}, 1))
+
+	_, msg, err := postTxResult(`NewMultisigWallet`, &url.Values{`Keys[]`: {`1`},
+		`Keys[0]`: {gAddress}, `Threshold`: {`1`}})
+	if err != nil {
+		require.Contains(t, err.Error(), `already exists`)
+	} else {
+		assert.Equal(t, wallet, msg)
+	}
+
+	var info multisigWalletResult
+	require.NoError(t, sendGet(`multisig/wallet/`+wallet, nil, &info))
+	assert.Equal(t, int64(1), info.Threshold)
+	assert.Equal(t, []string{gAddress}, info.Keys)
+
+	ret := make(map[string]interface{})
+	require.NoError(t, sendPost(`prepare/NewParameter`, &url.Values{`Name`: {randName(`multisig`)},
+		`Value`: {`1`}, `Conditions`: {`true`}, `multisig`: {wallet}}, &ret))
+	form := url.Values{`multisig`: {wallet}}
+	require.NoError(t, appendSign(ret, &form))
+
+	var mtx multisigTxResult
+	require.NoError(t, sendPost(`contract/`+ret[`request_id`].(string), &form, &mtx))
+	assert.Equal(t, wallet, mtx.Wallet)
+	assert.Equal(t, []string{gAddress}, mtx.Signers)
+	assert.NotEmpty(t, mtx.TxHash)
+
+	sign, err := getSign(mtx.ForSign)
+	require.NoError(t, err)
+	err = sendPost(`multisig/sign/`+mtx.Hash, &url.Values{`signature`: {sign}}, &mtx)
+	assert.EqualError(t, err, `400 {"error": "E_MULTISIGSIGNED", "msg": "Multisig transaction has been already signed by `+gAddress+`" }`)
+
+	err = sendGet(`multisig/tx/`+strings.Repeat(`00`, 32), nil, &mtx)
+	assert.EqualError(t, err, `404 {"error": "E_HASHNOTFOUND", "msg": "Hash has not been found" }`)
+
+	err = sendGet(`multisig/wallet/`+gAddress, nil, &info)
+	assert.EqualError(t, err, `404 {"error": "E_MULTISIGNOTFOUND", "msg": "Multisig wallet `+gAddress+` has not been found" }`)
+}
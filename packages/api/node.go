@@ -18,13 +18,11 @@ package api
 
 import (
 	"encoding/hex"
-	"errors"
 	"net/http"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
-	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -32,14 +30,11 @@ import (
 func (h *contractHandlers) nodeContract(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
 	var err error
 
-	NodePrivateKey, NodePublicKey, err := utils.GetNodeKeys()
+	NodePublicKey, err := signer.Node().PublicKey()
 	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
 		return err
 	}
-	if len(NodePrivateKey) == 0 {
-		logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		return errors.New(`empty node private key`)
-	}
 	pubkey, err := hex.DecodeString(NodePublicKey)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
@@ -52,7 +47,7 @@ func (h *contractHandlers) nodeContract(w http.ResponseWriter, r *http.Request,
 	}
 	result := prepareData.result.(prepareResult)
 
-	signature, err := crypto.Sign(NodePrivateKey, result.ForSign)
+	signature, err := signer.Node().Sign(result.ForSign)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing by node private key")
 		return err
@@ -0,0 +1,55 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/mempool"
+	"github.com/GenesisKernel/go-genesis/packages/transaction"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type nonceResult struct {
+	KeyID string `json:"key_id"`
+	// Nonce is the last nonce of the key which has been used in the blockchain
+	Nonce string `json:"nonce"`
+	// Next is the nonce for the new transaction, it follows the transactions of the mempool
+	Next string `json:"next"`
+}
+
+func getNonce(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	keyID := converter.StringToAddress(data.params[`wallet`].(string))
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": data.params["wallet"].(string)}).Error("converting wallet to address")
+		return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, data.params[`wallet`].(string))
+	}
+
+	nonce, err := transaction.GetNonce(nil, keyID)
+	if err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &nonceResult{
+		KeyID: converter.Int64ToStr(keyID),
+		Nonce: converter.Int64ToStr(nonce),
+		Next:  converter.Int64ToStr(mempool.GetPool().PendingNonce(keyID, nonce+1)),
+	}
+	return nil
+}
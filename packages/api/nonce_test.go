@@ -0,0 +1,54 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonce(t *testing.T) {
+	require.NoError(t, keyLogin(1))
+
+	var ret nonceResult
+	require.NoError(t, sendGet(`nonce/`+gAddress, nil, &ret))
+	assert.Equal(t, converter.Int64ToStr(converter.StringToAddress(gAddress)), ret.KeyID)
+	nonce := converter.StrToInt64(ret.Nonce)
+	require.Equal(t, nonce+1, converter.StrToInt64(ret.Next))
+
+	name := randName(`nonce`)
+	form := url.Values{`Name`: {name}, `Value`: {`1`}, `Conditions`: {`true`},
+		`nonce`: {converter.Int64ToStr(nonce + 1)}}
+	require.NoError(t, postTx(`NewParameter`, &form))
+
+	require.NoError(t, sendGet(`nonce/`+gAddress, nil, &ret))
+	assert.Equal(t, converter.Int64ToStr(nonce+1), ret.Nonce)
+	assert.Equal(t, converter.Int64ToStr(nonce+2), ret.Next)
+
+	// the transaction with the used nonce is refused
+	form = url.Values{`Name`: {name + `_replay`}, `Value`: {`1`}, `Conditions`: {`true`},
+		`nonce`: {converter.Int64ToStr(nonce + 1)}}
+	assert.Error(t, postTx(`NewParameter`, &form))
+
+	err := sendGet(`nonce/wrong`, nil, &ret)
+	assert.EqualError(t, err, `400 {"error": "E_INVALIDWALLET", "msg": "Wallet wrong is not valid" }`)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 
 	"github.com/shopspring/decimal"
@@ -53,6 +54,8 @@ type multiPrepareRequest struct {
 	MaxSum         string `json:"max_sum"`
 	Payover        string `json:"payover"`
 	SignedBy       string `json:"signed_by"`
+	// Nonce is the nonce of the first contract, the next contracts get the following nonces
+	Nonce string `json:"nonce"`
 
 	Contracts []multiPrepareRequestItem `json:"contracts"`
 }
@@ -80,11 +83,20 @@ func (h *contractHandlers) prepareMultipleContract(w http.ResponseWriter, r *htt
 	if requests.SignedBy != "" {
 		signedBy = converter.StrToInt64(requests.SignedBy)
 	}
+	nonce := converter.StrToInt64(requests.Nonce)
+	signID := data.keyId
+	if signedBy != 0 {
+		signID = signedBy
+	}
+	keyType, err := getKeyType(signID, data.ecosystemId, w, logger)
+	if err != nil {
+		return err
+	}
 
 	req := h.multiRequests.NewMultiRequest()
 	forSigns := []string{}
 	limitForsign := syspar.GetMaxForsignSize()
-	for _, c := range requests.Contracts {
+	for i, c := range requests.Contracts {
 		var smartTx tx.SmartContract
 		contract, parerr, err := validateSmartContractJSON(r, data, c.Contract, c.Params)
 		if err != nil {
@@ -109,6 +121,10 @@ func (h *contractHandlers) prepareMultipleContract(w http.ResponseWriter, r *htt
 			KeyID:       data.keyId,
 			RoleID:      data.roleId,
 			NetworkID:   consts.NETWORK_ID,
+			KeyType:     keyType,
+		}
+		if nonce != 0 {
+			smartTx.Header.Nonce = nonce + int64(i)
 		}
 		forsign := []string{smartTx.ForSign()}
 		if info.Tx != nil {
@@ -159,6 +175,23 @@ func (h *contractHandlers) prepareContract(w http.ResponseWriter, r *http.Reques
 		smartTx.SignedBy = data.params[`signed_by`].(int64)
 	}
 
+	keyID := data.keyId
+	var keyType int64
+	multisig := data.ParamString(`multisig`)
+	if len(multisig) > 0 {
+		if keyID = converter.StringToAddress(multisig); keyID == 0 {
+			return errorAPI(w, `E_INVALIDWALLET`, http.StatusBadRequest, multisig)
+		}
+	} else {
+		signID := keyID
+		if smartTx.SignedBy != 0 {
+			signID = smartTx.SignedBy
+		}
+		if keyType, err = getKeyType(signID, data.ecosystemId, w, logger); err != nil {
+			return err
+		}
+	}
+
 	req := h.requests.NewRequest(contract.Name)
 
 	smartTx.RequestID = req.ID
@@ -166,9 +199,11 @@ func (h *contractHandlers) prepareContract(w http.ResponseWriter, r *http.Reques
 		Type:        int(info.ID),
 		Time:        req.Time.Unix(),
 		EcosystemID: data.ecosystemId,
-		KeyID:       data.keyId,
+		KeyID:       keyID,
 		RoleID:      data.roleId,
 		NetworkID:   consts.NETWORK_ID,
+		Nonce:       data.params[`nonce`].(int64),
+		KeyType:     keyType,
 	}
 
 	forsign := []string{smartTx.ForSign()}
@@ -185,6 +220,17 @@ func (h *contractHandlers) prepareContract(w http.ResponseWriter, r *http.Reques
 	if len(result.ForSign) > int(syspar.GetMaxForsignSize()) {
 		return errorAPI(w, `E_LIMITFORSIGN`, http.StatusBadRequest, len(result.ForSign))
 	}
+	if len(multisig) > 0 {
+		// the keys of multisig wallet sign the hash of the parameters of the contract
+		if info.Tx != nil {
+			if smartTx.Data, err = getData(*info.Tx, req, w, logger); err != nil {
+				return err
+			}
+		}
+		if result.ForSign, err = smart.MultisigForSign(&smartTx); err != nil {
+			return errorAPI(w, err, http.StatusInternalServerError)
+		}
+	}
 	result.Time = converter.Int64ToStr(req.Time.Unix())
 	result.Expiration = converter.Int64ToStr(req.Time.Add(h.requests.ExpireDuration()).Unix())
 	data.result = result
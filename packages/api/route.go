@@ -54,12 +54,14 @@ func Route(route *hr.Router) {
 	get(`contract/:name`, ``, authWallet, getContract)
 	get(`contracts`, `?limit ?offset:int64`, authWallet, getContracts)
 	get(`getuid`, ``, getUID)
-	get(`list/:name`, `?limit ?offset:int64,?columns:string`, authWallet, list)
-	get(`row/:name/:id`, `?columns:string`, authWallet, row)
+	get(`list/:name`, `?limit ?offset ?block:int64,?columns:string`, authWallet, list)
+	get(`row/:name/:id`, `?block:int64,?columns:string`, authWallet, row)
 	get(`interface/page/:name`, ``, authWallet, getPageRow)
 	get(`interface/menu/:name`, ``, authWallet, getMenuRow)
 	get(`interface/block/:name`, ``, authWallet, getBlockInterfaceRow)
 	// get(`systemparams`, `?names:string`, authWallet, systemParams)
+	get(`search`, `query:string,?sources:string,?limit:int64`, authWallet, search)
+	get(`search/usages/:name`, ``, authWallet, contractUsages)
 	get(`table/:name`, ``, authWallet, table)
 	get(`tables`, `?limit ?offset:int64`, authWallet, tables)
 	get(`test/:name`, ``, getTest)
@@ -67,19 +69,21 @@ func Route(route *hr.Router) {
 	get(`avatar/:ecosystem/:member`, ``, getAvatar)
 	get(`config/:option`, ``, getConfigOption)
 	get("ecosystemname", "?id:int64", getEcosystemName)
+	get(`content/page/:name`, `?ecosystem:int64,?lang ?theme:string`, getPublicPage)
 	post(`content/source/:name`, ``, authWallet, getSource)
-	post(`content/page/:name`, `?lang:string`, authWallet, getPage)
+	post(`content/page/:name`, `?lang ?format ?theme:string`, authWallet, getPage)
 	post(`content/menu/:name`, `?lang:string`, authWallet, getMenu)
 	post(`content/hash/:name`, ``, getPageHash)
-	post(`login`, `?pubkey signature:hex,?key_id ?mobile:string,?ecosystem ?expire ?role_id:int64`, login)
-	post(`prepare/:name`, `?token_ecosystem:int64,?max_sum ?payover:string`, authWallet, contractHandlers.prepareContract)
+	post(`login`, `?pubkey signature:hex,?key_id ?mobile:string,?ecosystem ?expire ?role_id ?key_type:int64`, login)
+	post(`prepare/:name`, `?token_ecosystem ?nonce:int64,?max_sum ?payover ?multisig:string`, authWallet, contractHandlers.prepareContract)
 	post(`prepareMultiple`, `data:string`, authWallet, contractHandlers.prepareMultipleContract)
 	post(`txstatusMultiple`, `data:string`, authWallet, txstatusMulti)
-	post(`contract/:request_id`, `?pubkey signature:hex, time:string, ?token_ecosystem:int64,?max_sum ?payover:string`, authWallet, blockchainUpdatingState, contractHandlers.contract)
+	post(`contract/:request_id`, `?pubkey signature:hex, time:string, ?token_ecosystem ?nonce:int64,?max_sum ?payover ?multisig:string`, authWallet, blockchainUpdatingState, contractHandlers.contract)
 	post(`contractMultiple/:request_id`, `data:string`, authWallet, blockchainUpdatingState, contractHandlers.contractMulti)
 	post(`refresh`, `token:string,?expire:int64`, refresh)
 	post(`test/:name`, ``, getTest)
 	post(`content`, `template ?source:string`, jsonContent)
+	post(`content/validate`, `template:string`, authWallet, validateContent)
 	post(`updnotificator`, `ids:string`, updateNotificator)
 	get(`ecosystemparam/:name`, `?ecosystem:int64`, authWallet, ecosystemParam)
 	methodRoute(route, `POST`, `node/:name`, `?token_ecosystem:int64,?max_sum ?payover:string`, contractHandlers.nodeContract)
@@ -90,6 +94,22 @@ func Route(route *hr.Router) {
 		get(`appparam/:appid/:name`, `?ecosystem:int64`, authWallet, appParam)
 		get(`appparams/:appid`, `?ecosystem:int64,?names:string`, authWallet, appParams)
 		get(`history/:table/:id`, ``, authWallet, getHistory)
+		get(`export/:name`, `?limit ?offset ?block:int64,?columns ?format:string`, authWallet, exportTable)
+		get(`translations/export`, `?app_id:int64,?lang ?source ?format:string`, authWallet, exportTranslations)
+		get(`translations/check`, `?app_id:int64`, authWallet, checkTranslations)
+		post(`translations/import`, `data:string,?lang ?format:string,?app_id:int64`, authWallet, importTranslations)
+		get(`bundle/:app_id`, `?version:string`, authWallet, exportBundle)
+		post(`bundle/sign`, `data:string,signature ?pubkey:hex`, authWallet, signBundle)
+		post(`bundle/import`, `data:string,?signer:string,?app_id ?unsigned:int64`, authWallet, importBundle)
+		get(`changes`, `?since ?after ?limit:int64`, authWallet, getChanges)
+		post(`changes/subscribe`, `client channels:string`, authWallet, subscribeChanges)
+		get(`mempool`, `?key_id ?limit ?offset:int64`, authWallet, getMempool)
+		get(`mempool/:hash`, ``, authWallet, getMempoolTx)
+		get(`nonce/:wallet`, ``, authWallet, getNonce)
+		get(`multisig/wallet/:wallet`, ``, authWallet, getMultisigWalletInfo)
+		get(`multisig/txs`, `?wallet:string`, authWallet, getMultisigTxs)
+		get(`multisig/tx/:hash`, ``, authWallet, getMultisigTx)
+		post(`multisig/sign/:hash`, `signature:hex`, authWallet, blockchainUpdatingState, signMultisigTx)
 		get(`balance/:wallet`, `?ecosystem:int64`, authWallet, balance)
 		get(`block/:id`, ``, getBlockInfo)
 		get(`maxblockid`, ``, getMaxBlockID)
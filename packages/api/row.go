@@ -18,6 +18,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
@@ -41,6 +42,15 @@ func row(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entr
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": data.params["name"].(string), "id": data.params["id"].(string)}).Error("getting one row")
 		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
 	}
+	if data.ParamInt64(`block`) != 0 && len(row) > 0 {
+		asOf, err := getTableAsOfBlock(w, data, nil, strings.Trim(table, `"`), row[`id`], logger)
+		if err != nil {
+			return err
+		}
+		if row = asOf.revert(row); row == nil {
+			row = map[string]string{}
+		}
+	}
 
 	data.result = &rowResult{Value: row}
 	return
@@ -0,0 +1,167 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	searchDefaultLimit = 25
+	searchMaxLimit     = 250
+)
+
+type searchResult struct {
+	List []model.SearchResult `json:"list"`
+}
+
+type usagesResult struct {
+	Contracts []string             `json:"contracts"`
+	List      []model.SearchResult `json:"list"`
+}
+
+// getSmartContract returns the contract context of the caller which is used for checking permissions
+func getSmartContract(data *apiData) *smart.SmartContract {
+	return &smart.SmartContract{
+		VDE: data.vde,
+		VM:  data.vm,
+		TxSmart: tx.SmartContract{
+			Header: tx.Header{
+				EcosystemID: data.ecosystemId,
+				KeyID:       data.keyId,
+				RoleID:      data.roleId,
+				NetworkID:   consts.NETWORK_ID,
+			},
+		},
+	}
+}
+
+// searchSources searches the sources which can be read by the caller
+func searchSources(data *apiData, sources []string, query string, limit int, logger *log.Entry) ([]model.SearchResult, error) {
+	sc := getSmartContract(data)
+	prefix := getPrefix(data)
+	list := make([]model.SearchResult, 0)
+	for _, source := range sources {
+		if _, err := sc.AccessTablePerm(prefix+`_`+source, `read`); err != nil {
+			continue
+		}
+		found, err := model.SearchTable(prefix, source, query, limit)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "source": source}).Error("searching source")
+			return nil, err
+		}
+		list = append(list, found...)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Rank > list[j].Rank
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func search(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	var sources []string
+	if len(data.ParamString(`sources`)) == 0 {
+		for source := range model.SearchSources {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+	} else {
+		for _, source := range strings.Split(data.ParamString(`sources`), `,`) {
+			source = strings.TrimSpace(source)
+			if _, ok := model.SearchSources[source]; !ok {
+				return errorAPI(w, `E_SEARCHSOURCE`, http.StatusBadRequest, source)
+			}
+			sources = append(sources, source)
+		}
+	}
+	limit := int(data.ParamInt64(`limit`))
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	} else if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+	list, err := searchSources(data, sources, data.ParamString(`query`), limit, logger)
+	if err != nil {
+		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
+	}
+	data.result = &searchResult{List: list}
+	return nil
+}
+
+// contractCallers returns the contracts which call the target contract. The contracts are read
+// with the same permission as the contracts source of the search
+func contractCallers(data *apiData, target string, logger *log.Entry) ([]string, error) {
+	contracts := make([]string, 0)
+	prefix := getPrefix(data)
+	if _, err := getSmartContract(data).AccessTablePerm(prefix+`_contracts`, `read`); err != nil {
+		return contracts, nil
+	}
+	rows, err := model.GetAll(`select value from "`+prefix+`_contracts" order by id`, -1)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contracts")
+		return nil, err
+	}
+	state := uint32(data.ecosystemId)
+	for _, row := range rows {
+		names, err := script.ContractsList(row[`value`])
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.ContractError, "error": err}).Error("getting contract list")
+			continue
+		}
+		for _, item := range names {
+			for _, used := range smart.GetUsedContracts(item, state, false) {
+				if used == target {
+					contracts = append(contracts, item)
+					break
+				}
+			}
+		}
+	}
+	return contracts, nil
+}
+
+// contractUsages returns the contracts which call the contract and the sources which mention it
+func contractUsages(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	name := data.ParamString(`name`)
+	state := uint32(data.ecosystemId)
+	if smart.GetContract(name, state) == nil {
+		return errorAPI(w, `E_CONTRACT`, http.StatusBadRequest, name)
+	}
+	contracts, err := contractCallers(data, script.StateName(state, name), logger)
+	if err != nil {
+		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
+	}
+	list, err := searchSources(data, []string{`pages`, `blocks`, `menu`}, name, searchMaxLimit, logger)
+	if err != nil {
+		return errorAPI(w, `E_QUERY`, http.StatusInternalServerError)
+	}
+	data.result = &usagesResult{Contracts: contracts, List: list}
+	return nil
+}
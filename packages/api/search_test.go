@@ -0,0 +1,40 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	if err := keyLogin(1); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var ret searchResult
+	if err := sendGet(`search?query=MainCondition&sources=contracts`, nil, &ret); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ret.List) == 0 {
+		t.Error(`MainCondition has not been found`)
+	}
+	for _, item := range ret.List {
+		if item.Source != `contracts` {
+			t.Errorf(`wrong source %s`, item.Source)
+		}
+	}
+
+	err := sendGet(`search?query=MainCondition&sources=qwerty`, nil, &ret)
+	if err == nil || err.Error() != `400 {"error": "E_SEARCHSOURCE", "msg": "Unknown search source qwerty" , "params": ["qwerty"]}` {
+		t.Error(err)
+	}
+
+	var usages usagesResult
+	if err := sendGet(`search/usages/MainCondition`, nil, &usages); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(usages.Contracts) == 0 {
+		t.Error(`MainCondition usages have not been found`)
+	}
+}
@@ -978,3 +978,85 @@ func TestPageHistory(t *testing.T) {
 		t.Error(fmt.Errorf(`wrong tree %s`, RawToString(retTemp.Tree)))
 	}
 }
+
+func TestMigrateTable(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	name := randName(`mgr`)
+	form := url.Values{"Name": {name}, "ApplicationId": {"1"}, "Columns": {`[{"name":"title","type":"varchar",
+		"conditions":"true"}, {"name":"price","type":"varchar", "conditions":"true"}]`},
+		"Permissions": {`{"insert": "true", "update" : "true", "new_column": "true"}`}}
+	require.NoError(t, postTx(`NewTable`, &form))
+	require.NoError(t, postTx(`NewContract`, &url.Values{`Value`: {`contract ins` + name + ` {
+		action {
+			DBInsert("` + name + `", "title,price", "first", "12")
+		}
+	}`}, `Conditions`: {`true`}, "ApplicationId": {"1"}}))
+	require.NoError(t, postTx(`ins`+name, &url.Values{}))
+
+	migrate := func(version, action, column string, params ...string) error {
+		form := url.Values{"TableName": {name}, "Version": {version}, "Action": {action}, "Column": {column}}
+		for i := 0; i+1 < len(params); i += 2 {
+			form[params[i]] = []string{params[i+1]}
+		}
+		return postTx(`MigrateTable`, &form)
+	}
+	assert.NoError(t, migrate(`1`, `rename`, `title`, `NewName`, `caption`))
+	assert.Error(t, migrate(`1`, `drop`, `caption`))
+	assert.NoError(t, migrate(`2`, `type`, `price`, `Type`, `number`, `Conversion`, `to_number`, `Format`, `9999`))
+	assert.Error(t, migrate(`3`, `type`, `caption`, `Type`, `number`, `Conversion`, `(SELECT 1)`))
+	assert.Error(t, migrate(`3`, `type`, `caption`, `Type`, `number`, `Conversion`, `to_number`))
+	assert.NoError(t, migrate(`3`, `index`, `caption`))
+	assert.NoError(t, migrate(`4`, `drop`, `caption`))
+
+	var ret tableResult
+	require.NoError(t, sendGet(`table/`+name, nil, &ret))
+	require.Len(t, ret.Columns, 1)
+	assert.Equal(t, `price`, ret.Columns[0].Name)
+	assert.Equal(t, `number`, ret.Columns[0].Type)
+
+	var row rowResult
+	require.NoError(t, sendGet(`row/`+name+`/1`, nil, &row))
+	assert.Equal(t, `12`, row.Value[`price`])
+}
+
+func TestTableConstraints(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	parent := randName(`par`)
+	form := url.Values{"Name": {parent}, "ApplicationId": {"1"}, "Columns": {`[{"name":"title","type":"varchar",
+		"conditions":"true", "unique": true}]`},
+		"Permissions": {`{"insert": "true", "update" : "true", "new_column": "true"}`}}
+	require.NoError(t, postTx(`NewTable`, &form))
+
+	child := randName(`chl`)
+	form = url.Values{"Name": {child}, "ApplicationId": {"1"}, "Columns": {`[{"name":"parent","type":"number",
+		"conditions":"true", "references": "` + parent + `.id"}]`},
+		"Permissions": {`{"insert": "true", "update" : "true", "new_column": "true"}`}}
+	require.NoError(t, postTx(`NewTable`, &form))
+	assert.Error(t, postTx(`NewColumn`, &url.Values{"TableName": {child}, "Name": {`other`},
+		"Type": {"varchar"}, "Permissions": {"true"}, "References": {parent}}))
+	assert.NoError(t, postTx(`NewColumn`, &url.Values{"TableName": {child}, "Name": {`code`},
+		"Type": {"varchar"}, "Permissions": {"true"}, "Unique": {"true"}}))
+
+	require.NoError(t, postTx(`NewContract`, &url.Values{`Value`: {`contract ins` + child + ` {
+		data {
+			Title string
+			Parent int
+		}
+		action {
+			var id int
+			id = DBFind("` + parent + `").Columns("id").Where("title = ?", $Title).One("id")
+			if !id {
+				id = DBInsert("` + parent + `", "title", $Title)
+			}
+			DBInsert("` + child + `", "parent,code", $Parent, $Title)
+		}
+	}`}, `Conditions`: {`true`}, "ApplicationId": {"1"}}))
+
+	assert.NoError(t, postTx(`ins`+child, &url.Values{"Title": {"first"}, "Parent": {"1"}}))
+	assert.EqualError(t, postTx(`ins`+child, &url.Values{"Title": {"first"}, "Parent": {"1"}}),
+		`{"type":"panic","error":"Value first of column code already exists"}`)
+	assert.EqualError(t, postTx(`ins`+child, &url.Values{"Title": {"second"}, "Parent": {"100"}}),
+		fmt.Sprintf(`{"type":"panic","error":"Record 100 of table %s referenced by column parent has not been found"}`, parent))
+}
@@ -0,0 +1,166 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/language"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const editLangsContract = `EditLangs`
+
+type translationsCheckResult struct {
+	Missing  []string `json:"missing"`
+	Obsolete []string `json:"obsolete"`
+}
+
+type translationsImportResult struct {
+	Lang     string                   `json:"lang"`
+	Changes  []language.Change        `json:"changes"`
+	Unknown  []string                 `json:"unknown"`
+	Missing  []string                 `json:"missing"`
+	Obsolete []string                 `json:"obsolete"`
+	Contract *multiPrepareRequestItem `json:"contract,omitempty"`
+}
+
+func translationFormat(w http.ResponseWriter, data *apiData) (string, error) {
+	format := data.ParamString(`format`)
+	if len(format) == 0 {
+		format = language.FormatPO
+	}
+	if format != language.FormatPO && format != language.FormatXLIFF {
+		return ``, errorAPI(w, `E_LANGFORMAT`, http.StatusBadRequest, format)
+	}
+	return format, nil
+}
+
+func loadTranslations(w http.ResponseWriter, data *apiData, logger *log.Entry) ([]language.Translation, error) {
+	prefix := getPrefix(data)
+	if _, err := getSmartContract(data).AccessTablePerm(prefix+`_languages`, `read`); err != nil {
+		return nil, errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+	}
+	list, err := language.LoadTranslations(prefix, data.ParamInt64(`app_id`))
+	if err != nil {
+		return nil, errorAPI(w, err, http.StatusInternalServerError)
+	}
+	return list, nil
+}
+
+// exportTranslations returns the language resources as PO or XLIFF file for translation
+// from the source language to the target language
+func exportTranslations(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	format, err := translationFormat(w, data)
+	if err != nil {
+		return err
+	}
+	lang := strings.ToLower(data.ParamString(`lang`))
+	if len(lang) == 0 {
+		return errorAPI(w, `E_PARAMNOTFOUND`, http.StatusBadRequest, `lang`)
+	}
+	source := strings.ToLower(data.ParamString(`source`))
+	if len(source) == 0 {
+		source = language.DefLang()
+	}
+	list, err := loadTranslations(w, data, logger)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = language.Export(&buf, format, language.Units(list, source, lang), source, lang); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("exporting translations")
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if format == language.FormatXLIFF {
+		w.Header().Set("Content-Type", "application/x-xliff+xml; charset=utf-8")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, lang, format))
+	data.raw = true
+	if _, err = buf.WriteTo(w); err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing translations")
+	}
+	return nil
+}
+
+// importTranslations parses the translated file and returns the preview of changes with
+// EditLangs contract which applies all changes in one transaction
+func importTranslations(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	format, err := translationFormat(w, data)
+	if err != nil {
+		return err
+	}
+	units, lang, err := language.Import(strings.NewReader(data.ParamString(`data`)), format)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("importing translations")
+		return errorAPI(w, `E_LANGFILE`, http.StatusBadRequest, err.Error())
+	}
+	if len(data.ParamString(`lang`)) > 0 {
+		lang = data.ParamString(`lang`)
+	}
+	lang = strings.ToLower(lang)
+	if len(lang) == 0 {
+		return errorAPI(w, `E_PARAMNOTFOUND`, http.StatusBadRequest, `lang`)
+	}
+	list, err := loadTranslations(w, data, logger)
+	if err != nil {
+		return err
+	}
+	result := translationsImportResult{Lang: lang}
+	if result.Changes, result.Unknown, err = language.Diff(list, lang, units); err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if result.Missing, result.Obsolete, err = language.CheckUsage(getPrefix(data), data.ParamInt64(`app_id`)); err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	if len(result.Changes) > 0 {
+		params := map[string]string{
+			`IdList[]`:    fmt.Sprint(len(result.Changes)),
+			`TransList[]`: fmt.Sprint(len(result.Changes)),
+		}
+		for i, change := range result.Changes {
+			params[fmt.Sprintf(`IdList[%d]`, i)] = fmt.Sprint(change.ID)
+			params[fmt.Sprintf(`TransList[%d]`, i)] = change.Trans
+		}
+		result.Contract = &multiPrepareRequestItem{Contract: editLangsContract, Params: params}
+	}
+	data.result = &result
+	return nil
+}
+
+// checkTranslations returns the names of language resources which are used in pages and contracts
+// but don't exist and the resources which are not used
+func checkTranslations(w http.ResponseWriter, r *http.Request, data *apiData, logger *log.Entry) error {
+	if _, err := getSmartContract(data).AccessTablePerm(getPrefix(data)+`_languages`, `read`); err != nil {
+		return errorAPI(w, `E_PERMISSION`, http.StatusForbidden)
+	}
+	var (
+		result translationsCheckResult
+		err    error
+	)
+	if result.Missing, result.Obsolete, err = language.CheckUsage(getPrefix(data), data.ParamInt64(`app_id`)); err != nil {
+		return errorAPI(w, err, http.StatusInternalServerError)
+	}
+	data.result = &result
+	return nil
+}
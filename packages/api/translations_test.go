@@ -0,0 +1,67 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslations(t *testing.T) {
+	assert.NoError(t, keyLogin(1))
+
+	name := randName("tr")
+	assert.NoError(t, postTx("NewLang", &url.Values{
+		"Name":          {name},
+		"Trans":         {`{"en": "Source text"}`},
+		"ApplicationId": {"1"},
+	}))
+
+	out, err := sendRawRequest("GET", "translations/export?lang=de&format=xliff", nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), fmt.Sprintf(`<trans-unit id="%s">`, name))
+
+	po, err := sendRawRequest("GET", "translations/export?lang=de", nil)
+	assert.NoError(t, err)
+	translated := strings.Replace(string(po), fmt.Sprintf("msgctxt \"%s\"\nmsgid \"Source text\"\nmsgstr \"\"", name),
+		fmt.Sprintf("msgctxt \"%s\"\nmsgid \"Source text\"\nmsgstr \"Quelltext\"", name), 1)
+
+	var ret translationsImportResult
+	assert.NoError(t, sendPost("translations/import", &url.Values{"data": {translated}}, &ret))
+	assert.Equal(t, "de", ret.Lang)
+	if assert.NotNil(t, ret.Contract) {
+		assert.Equal(t, "EditLangs", ret.Contract.Contract)
+		assert.Equal(t, "1", ret.Contract.Params["TransList[]"])
+		assert.Equal(t, `{"de":"Quelltext","en":"Source text"}`, ret.Contract.Params["TransList[0]"])
+
+		form := url.Values{}
+		for key, value := range ret.Contract.Params {
+			form.Set(key, value)
+		}
+		assert.NoError(t, postTx(ret.Contract.Contract, &form))
+		out, err := sendRawRequest("GET", "translations/export?lang=de", nil)
+		assert.NoError(t, err)
+		assert.Contains(t, string(out), "msgstr \"Quelltext\"")
+	}
+	assert.Contains(t, ret.Obsolete, name)
+
+	assert.Error(t, sendPost("translations/import", &url.Values{"data": {translated}, "format": {"csv"}}, &ret))
+}
@@ -37,6 +37,7 @@ type txstatusResult struct {
 	BlockID string         `json:"blockid"`
 	Message *txstatusError `json:"errmsg,omitempty"`
 	Result  string         `json:"result"`
+	Final   bool           `json:"final"`
 }
 
 func getTxStatus(hash string, w http.ResponseWriter, logger *log.Entry) (*txstatusResult, error) {
@@ -58,6 +59,12 @@ func getTxStatus(hash string, w http.ResponseWriter, logger *log.Entry) (*txstat
 	if ts.BlockID > 0 {
 		status.BlockID = converter.Int64ToStr(ts.BlockID)
 		status.Result = ts.Error
+		block := &model.Block{}
+		if _, err = block.Get(ts.BlockID); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": ts.BlockID}).Error("getting block of transaction")
+			return nil, errorAPI(w, err, http.StatusInternalServerError)
+		}
+		status.Final = block.Final
 	} else if len(ts.Error) > 0 {
 		if err := json.Unmarshal([]byte(ts.Error), &status.Message); err != nil {
 			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "text": ts.Error, "error": err}).Warn("unmarshalling txstatus error")
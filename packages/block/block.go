@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/transaction"
 	"github.com/GenesisKernel/go-genesis/packages/transaction/custom"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -48,21 +50,21 @@ func (b *Block) PlaySafe() error {
 	}
 
 	err = b.Play(dbTransaction)
-	if b.GenBlock && b.StopCount > 0 {
-		doneTx := b.Transactions[:b.StopCount]
-		trData := make([][]byte, 0, b.StopCount)
+	if b.GenBlock && (err == nil || b.StopCount > 0) {
+		// the generated block is signed once, after the bad transactions have been dropped
+		doneTx := b.Transactions
+		if b.StopCount > 0 {
+			doneTx = b.Transactions[:b.StopCount]
+		}
+		trData := make([][]byte, 0, len(doneTx))
 		for _, tr := range doneTx {
 			trData = append(trData, tr.TxFullData)
 		}
-		NodePrivateKey, _, err := utils.GetNodeKeys()
-		if err != nil || len(NodePrivateKey) < 1 {
-			log.WithFields(log.Fields{"type": consts.NodePrivateKeyFilename, "error": err}).Error("reading node private key")
-			return err
-		}
 
-		newBlockData, err := MarshallBlock(&b.Header, trData, b.PrevHeader.Hash, NodePrivateKey)
+		newBlockData, err := MarshallBlock(&b.Header, trData, b.PrevHeader.Hash, signer.Node())
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marshalling new block")
+			dbTransaction.Rollback()
 			return err
 		}
 
@@ -70,9 +72,11 @@ func (b *Block) PlaySafe() error {
 		nb, err := UnmarshallBlock(bytes.NewBuffer(newBlockData), isFirstBlock)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("parsing new block")
+			dbTransaction.Rollback()
 			return err
 		}
 		b.BinData = newBlockData
+		b.Header.Sign = nb.Header.Sign
 		b.Transactions = nb.Transactions
 		b.MrklRoot = nb.MrklRoot
 		b.SysUpdate = nb.SysUpdate
@@ -100,6 +104,10 @@ func (b *Block) PlaySafe() error {
 			return err
 		}
 	}
+	if b.GenBlock {
+		// the synced blocks are voted by the collection of blocks when they reach the tip
+		consensus.VoteBlock(&b.Header)
+	}
 	return nil
 }
 
@@ -244,21 +252,15 @@ func (b *Block) Check() error {
 
 		// skip time validation for first block
 		if b.Header.BlockID > 1 {
-			blockTimeCalculator, err := utils.BuildBlockTimeCalculator(nil)
-			if err != nil {
-				logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("building block time calculator")
-				return err
-			}
-
-			validBlockTime, err := blockTimeCalculator.ValidateBlock(b.Header.NodePosition, time.Unix(b.Header.Time, 0))
+			cons, err := consensus.Build(nil)
 			if err != nil {
-				logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("calculating block time")
+				logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("building consensus")
 				return err
 			}
 
-			if !validBlockTime {
+			if err = cons.ValidateBlock(&b.Header, b.PrevHeader); err != nil {
 				logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("incorrect block time")
-				return utils.ErrInfo(fmt.Errorf("incorrect block time %d", b.PrevHeader.Time))
+				return utils.ErrInfo(fmt.Errorf("incorrect block time %d: %s", b.Header.Time, err))
 			}
 		}
 	}
@@ -287,6 +289,11 @@ func (b *Block) Check() error {
 
 	}
 
+	// the generated block is signed after playing its transactions
+	if b.GenBlock {
+		return nil
+	}
+
 	result, err := b.CheckHash()
 	if err != nil {
 		return utils.ErrInfo(err)
@@ -306,19 +313,11 @@ func (b *Block) CheckHash() (bool, error) {
 	}
 	// check block signature
 	if b.PrevHeader != nil {
-		nodePublicKey, err := syspar.GetNodePublicKeyByPosition(b.Header.NodePosition)
-		if err != nil {
-			return false, utils.ErrInfo(err)
-		}
-		if len(nodePublicKey) == 0 {
-			logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node public key is empty")
-			return false, utils.ErrInfo(fmt.Errorf("empty nodePublicKey"))
-		}
-		// check the signature
+		// check the signature by the keys of the node which are valid at the height of the block
 		forSign := fmt.Sprintf("0,%d,%x,%d,%d,%d,%d,%s", b.Header.BlockID, b.PrevHeader.Hash,
 			b.Header.Time, b.Header.EcosystemID, b.Header.KeyID, b.Header.NodePosition, b.MrklRoot)
 
-		resultCheckSign, err := utils.CheckSign([][]byte{nodePublicKey}, forSign, b.Header.Sign, true)
+		resultCheckSign, err := syspar.CheckNodeSign(b.Header.NodePosition, b.Header.BlockID, forSign, b.Header.Sign)
 		if err != nil {
 			logger.WithFields(log.Fields{"error": err, "type": consts.CryptoError}).Error("checking block header sign")
 			return false, utils.ErrInfo(fmt.Errorf("err: %v / block.PrevHeader.BlockID: %d /  block.PrevHeader.Hash: %x / ", err, b.PrevHeader.BlockID, b.PrevHeader.Hash))
@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
@@ -106,28 +106,20 @@ func InsertIntoBlockchain(transaction *model.DbTransaction, block *Block) error
 		RollbacksHash: rollbackTxsHash,
 		Tx:            int32(len(block.Transactions)),
 	}
-	blockTimeCalculator, err := utils.BuildBlockTimeCalculator(nil)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating block")
-		return err
-	}
-	validBlockTime := true
 	if blockID > 1 {
-		validBlockTime, err = blockTimeCalculator.ValidateBlock(b.NodePosition, time.Unix(b.Time, 0))
-		if err != nil {
-			log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("block validation")
-			return err
-		}
-	}
-	if validBlockTime {
-		err = b.Create(transaction)
+		cons, err := consensus.Build(transaction)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating block")
 			return err
 		}
-	} else {
-		err := fmt.Errorf("Invalid block time: %d", block.Header.Time)
-		log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("invalid block time")
+		if err = cons.ValidateBlock(&block.Header, block.PrevHeader); err != nil {
+			log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("block validation")
+			return fmt.Errorf("Invalid block time: %d", block.Header.Time)
+		}
+	}
+	err = b.Create(transaction)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating block")
 		return err
 	}
 
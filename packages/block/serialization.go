@@ -7,13 +7,14 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/transaction"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 	log "github.com/sirupsen/logrus"
 )
 
 // MarshallBlock is marshalling block
-func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, key string) ([]byte, error) {
+func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, s signer.Signer) ([]byte, error) {
 	var mrklArray [][]byte
 	var blockDataTx []byte
 	var signed []byte
@@ -29,7 +30,7 @@ func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, ke
 		blockDataTx = append(blockDataTx, converter.EncodeLengthPlusData(tr)...)
 	}
 
-	if key != "" {
+	if s != nil {
 		if len(mrklArray) == 0 {
 			mrklArray = append(mrklArray, []byte("0"))
 		}
@@ -39,7 +40,7 @@ func MarshallBlock(header *utils.BlockData, trData [][]byte, prevHash []byte, ke
 			header.BlockID, prevHash, header.Time, header.EcosystemID, header.KeyID, header.NodePosition, mrklRoot)
 
 		var err error
-		signed, err = crypto.Sign(key, forSign)
+		signed, err = s.SignBlock(header.BlockID, forSign)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing blocko")
 			return nil, err
@@ -0,0 +1,258 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatVersion is the version of the bundle format
+	FormatVersion = 1
+
+	// Types of the application resources
+	TypeTable     = `tables`
+	TypeParameter = `parameters`
+	TypeAppParam  = `app_params`
+	TypeLanguage  = `languages`
+	TypeContract  = `contracts`
+	TypeComponent = `components`
+	TypeBlock     = `blocks`
+	TypeMenu      = `menu`
+	TypePage      = `pages`
+)
+
+var (
+	// InstallOrder is the order of installing the resources, every type can depend only on the previous ones
+	InstallOrder = []string{TypeTable, TypeParameter, TypeAppParam, TypeLanguage, TypeContract,
+		TypeComponent, TypeBlock, TypeMenu, TypePage}
+
+	// ErrFormat is returned if the bundle has the unsupported format or unknown resources
+	ErrFormat = errors.New(`unsupported bundle format`)
+	// ErrHash is returned if the content of the bundle doesn't match its hash
+	ErrHash = errors.New(`bundle hash is wrong`)
+	// ErrSignature is returned if the bundle is not signed or the signature is wrong
+	ErrSignature = errors.New(`bundle signature is wrong`)
+	// ErrSigner is returned if the bundle is signed by the key which is not trusted
+	ErrSigner = errors.New(`bundle is signed by the unknown key`)
+
+	words = regexp.MustCompile(`\w+`)
+)
+
+// Item is the resource of the application. The fields have the same names as the data
+// of Import contract.
+type Item struct {
+	Type        string `json:"Type"`
+	Name        string `json:"Name"`
+	Value       string `json:"Value"`
+	Conditions  string `json:"Conditions"`
+	Menu        string `json:"Menu,omitempty"`
+	Title       string `json:"Title,omitempty"`
+	Trans       string `json:"Trans,omitempty"`
+	Columns     string `json:"Columns,omitempty"`
+	Permissions string `json:"Permissions,omitempty"`
+	Params      string `json:"Params,omitempty"`
+}
+
+// Bundle is the versioned and signed package of the application
+type Bundle struct {
+	Format     int    `json:"format"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Conditions string `json:"conditions"`
+	Ecosystem  int64  `json:"ecosystem"`
+	Created    int64  `json:"created"`
+	Data       []Item `json:"data"`
+	Hash       string `json:"hash"`
+	PublicKey  string `json:"public_key,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+}
+
+// Parse decodes the bundle from JSON
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling bundle")
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (b *Bundle) hash() (string, error) {
+	content := *b
+	content.Hash, content.PublicKey, content.Signature = ``, ``, ``
+	data, err := json.Marshal(content)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling bundle")
+		return ``, err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Seal sorts the resources in the install order and calculates the hash of the bundle
+func (b *Bundle) Seal() (err error) {
+	b.Format = FormatVersion
+	b.Data = sortItems(b.Data)
+	b.Hash, err = b.hash()
+	return
+}
+
+// SetSignature checks the signature of the bundle hash which has been made on the client side
+// with the private key of publicKey and adds it to the bundle
+func (b *Bundle) SetSignature(publicKey string, sign []byte) error {
+	public, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return ErrSignature
+	}
+	if ok, err := crypto.CheckSign(public, b.Hash, sign); err != nil || !ok {
+		return ErrSignature
+	}
+	b.PublicKey = strings.ToLower(publicKey)
+	b.Signature = hex.EncodeToString(sign)
+	return nil
+}
+
+// Verify checks the format, the hash and the signature of the bundle. The bundle must be signed
+// unless unsigned is true. If signer is not empty then the bundle must be signed with this public key.
+func (b *Bundle) Verify(signer string, unsigned bool) error {
+	if b.Format != FormatVersion || len(b.Name) == 0 {
+		return ErrFormat
+	}
+	for _, item := range b.Data {
+		if typeIndex(item.Type) < 0 || len(item.Name) == 0 {
+			return ErrFormat
+		}
+	}
+	hash, err := b.hash()
+	if err != nil {
+		return err
+	}
+	if hash != b.Hash {
+		return ErrHash
+	}
+	if len(b.Signature) == 0 {
+		if unsigned && len(signer) == 0 {
+			return nil
+		}
+		return ErrSignature
+	}
+	public, err := hex.DecodeString(b.PublicKey)
+	if err != nil {
+		return ErrSignature
+	}
+	sign, err := hex.DecodeString(b.Signature)
+	if err != nil {
+		return ErrSignature
+	}
+	if ok, err := crypto.CheckSign(public, b.Hash, sign); err != nil || !ok {
+		return ErrSignature
+	}
+	if len(signer) > 0 && !strings.EqualFold(signer, b.PublicKey) {
+		return ErrSigner
+	}
+	return nil
+}
+
+func typeIndex(itype string) int {
+	for i, val := range InstallOrder {
+		if val == itype {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortItems orders the resources by InstallOrder. Contracts, components and blocks which refer
+// to other resources of the same type are placed after them.
+func sortItems(items []Item) []Item {
+	byType := make(map[string][]Item)
+	for _, item := range items {
+		byType[item.Type] = append(byType[item.Type], item)
+	}
+	result := make([]Item, 0, len(items))
+	for _, itype := range InstallOrder {
+		list := byType[itype]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		switch itype {
+		case TypeContract, TypeComponent, TypeBlock:
+			list = sortDependencies(list)
+		}
+		result = append(result, list...)
+	}
+	return result
+}
+
+// sortDependencies places every item after the items whose names it contains.
+// The items with cyclic dependencies keep the order by name.
+func sortDependencies(list []Item) []Item {
+	names := make(map[string]bool, len(list))
+	for _, item := range list {
+		names[item.Name] = true
+	}
+	deps := make([]map[string]bool, len(list))
+	for i, item := range list {
+		deps[i] = make(map[string]bool)
+		for _, word := range words.FindAllString(item.Value, -1) {
+			for _, name := range []string{word, strings.TrimLeft(word, `0123456789`)} {
+				if name != item.Name && names[name] {
+					deps[i][name] = true
+				}
+			}
+		}
+	}
+	result := make([]Item, 0, len(list))
+	placed := make(map[string]bool, len(list))
+	done := make([]bool, len(list))
+	for len(result) < len(list) {
+		next := -1
+		for i := range list {
+			if done[i] {
+				continue
+			}
+			if next < 0 {
+				next = i
+			}
+			ready := true
+			for name := range deps[i] {
+				if !placed[name] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		done[next] = true
+		placed[list[next].Name] = true
+		result = append(result, list[next])
+	}
+	return result
+}
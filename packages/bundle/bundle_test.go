@@ -0,0 +1,206 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+func testBundle() *Bundle {
+	return &Bundle{Name: `shop`, Version: `1.0.0`, Conditions: `true`, Data: []Item{
+		{Type: TypePage, Name: `orders`, Value: `Include(header)`, Menu: `shop_menu`, Conditions: `true`},
+		{Type: TypeContract, Name: `Buy`, Value: `contract Buy { action { @1Pay() } }`, Conditions: `true`},
+		{Type: TypeContract, Name: `Pay`, Value: `contract Pay { action { } }`, Conditions: `true`},
+		{Type: TypeMenu, Name: `shop_menu`, Value: `MenuItem(Title: Orders, Page: orders)`, Title: `Shop`},
+		{Type: TypeTable, Name: `orders`, Columns: `[{"name":"amount","type":"money","conditions":"true"}]`,
+			Permissions: `{"insert":"true","update":"true","new_column":"true"}`},
+		{Type: TypeLanguage, Name: `order`, Trans: `{"en":"Order","fr":"Commande"}`},
+		{Type: TypeParameter, Name: `shop_fee`, Value: `10`, Conditions: `true`},
+	}}
+}
+
+func TestSeal(t *testing.T) {
+	b := testBundle()
+	if err := b.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	order := []string{`orders`, `shop_fee`, `order`, `Pay`, `Buy`, `shop_menu`, `orders`}
+	for i, item := range b.Data {
+		if item.Name != order[i] {
+			t.Fatalf(`wrong install order %v`, b.Data)
+		}
+	}
+	if err := b.Verify(``, true); err != nil {
+		t.Error(err)
+	}
+	b.Data[0].Columns = `[]`
+	if err := b.Verify(``, true); err != ErrHash {
+		t.Errorf(`changed bundle must be rejected: %v`, err)
+	}
+	b.Data[0].Type = `unknown`
+	if err := b.Verify(``, true); err != ErrFormat {
+		t.Errorf(`unknown resource must be rejected: %v`, err)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public := hex.EncodeToString(append(converter.FillLeft(private.X.Bytes()), converter.FillLeft(private.Y.Bytes())...))
+	b := testBundle()
+	if err = b.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = parsed.Verify(``, true); err != nil {
+		t.Error(err)
+	}
+	if err = parsed.Verify(``, false); err != ErrSignature {
+		t.Errorf(`unsigned bundle must be rejected: %v`, err)
+	}
+	if err = parsed.Verify(public, true); err != ErrSignature {
+		t.Errorf(`unsigned bundle must be rejected for the signer: %v`, err)
+	}
+	hash, err := crypto.Hash([]byte(parsed.Hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, private, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := append(converter.FillLeft(r.Bytes()), converter.FillLeft(s.Bytes())...)
+	if err = parsed.SetSignature(public, sign[1:]); err != ErrSignature {
+		t.Errorf(`wrong signature must not be added: %v`, err)
+	}
+	if err = parsed.SetSignature(public, sign); err != nil {
+		t.Fatal(err)
+	}
+	if err = parsed.Verify(public, false); err != nil {
+		t.Error(err)
+	}
+	parsed.Signature = strings.Repeat(`0`, 128)
+	if err = parsed.Verify(``, false); err != ErrSignature {
+		t.Errorf(`wrong signature must be rejected: %v`, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	b := testBundle()
+	if err := b.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	plan := Diff(b, 5, Existing{
+		TypeTable: {`orders`: {`id`: `3`, `columns`: `{"amount":"false"}`,
+			`permissions`: `{"insert": "true", "update": "true", "new_column": "true"}`}},
+		TypeParameter: {`shop_fee`: {`id`: `1`, `value`: `20`}},
+		TypeLanguage:  {`order`: {`id`: `7`, `res`: `{"fr": "Commande", "en": "Order"}`}},
+		TypeContract:  {`Pay`: {`id`: `9`, `value`: `contract Pay { action { } }`, `conditions`: `false`}},
+	})
+	actions := map[string]string{}
+	for _, change := range plan.Changes {
+		actions[change.Type+`.`+change.Name] = change.Action
+	}
+	want := map[string]string{`tables.orders`: ActionUpdate, `parameters.shop_fee`: ActionSkip,
+		`languages.order`: ActionSkip, `contracts.Pay`: ActionUpdate, `contracts.Buy`: ActionCreate,
+		`menu.shop_menu`: ActionCreate, `pages.orders`: ActionCreate}
+	for key, action := range want {
+		if actions[key] != action {
+			t.Errorf(`wrong action of %s: %s != %s`, key, actions[key], action)
+		}
+	}
+	contracts := []string{`EditColumn`, `EditContract`, `NewContract`, `NewMenu`, `NewPage`}
+	if len(plan.Contracts) != len(contracts) {
+		t.Fatalf(`wrong contracts %v`, plan.Contracts)
+	}
+	for i, name := range contracts {
+		if plan.Contracts[i].Contract != name {
+			t.Errorf(`wrong contract %d: %s != %s`, i, plan.Contracts[i].Contract, name)
+		}
+	}
+	if plan.Contracts[1].Params[`Id`] != `9` || plan.Contracts[2].Params[`ApplicationId`] != `5` {
+		t.Errorf(`wrong contract params %v`, plan.Contracts)
+	}
+	if _, ok := plan.Contracts[3].Params[`ApplicationId`]; ok {
+		t.Error(`menu must be created without application`)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	names := func(b *Bundle) map[string]bool {
+		list := make(map[string]bool)
+		for _, item := range b.Data {
+			if item.Type != TypeContract || len(item.Value) == 0 {
+				t.Errorf(`wrong item of upgrade bundle %v`, item)
+			}
+			list[item.Name] = true
+		}
+		return list
+	}
+	apps := names(Upgrade(1))
+	params := names(Upgrade(2))
+	for _, name := range []string{`ImportTableData`, `NewComponent`, `EditComponent`, `MigrateTable`,
+		`NewMultisigWallet`, `RotateNodeKey`, `EditLangs`} {
+		if !apps[name] {
+			t.Errorf(`contract %s is missing in upgrade bundle`, name)
+		}
+	}
+	if !params[`consensus`] || !params[`node_key_overlap`] || len(params) != 2 {
+		t.Errorf(`wrong contracts of system parameters %v`, params)
+	}
+	b := Upgrade(1)
+	if err := b.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Verify(``, true); err != nil {
+		t.Error(err)
+	}
+	var value string
+	for _, item := range b.Data {
+		if item.Name == `EditLangs` {
+			value = item.Value
+		}
+	}
+	plan := Diff(b, 1, Existing{TypeContract: {`EditLangs`: {`id`: `122`, `value`: value,
+		`conditions`: `ContractConditions("MainCondition")`}}})
+	for _, contract := range plan.Contracts {
+		if contract.Contract != `NewContract` || contract.Params[`ApplicationId`] != `1` {
+			t.Errorf(`wrong contract of upgrade %v`, contract)
+		}
+	}
+	if len(plan.Contracts) != len(apps)-1 {
+		t.Errorf(`installed contracts must be skipped %d`, len(plan.Contracts))
+	}
+}
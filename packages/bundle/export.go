@@ -0,0 +1,194 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrApplication is returned if the application has not been found
+	ErrApplication = errors.New(`application has not been found`)
+
+	ecosysParams = regexp.MustCompile(`EcosysParam\(\s*"?(\w+)`)
+)
+
+// tableColumn is the column of the table in the format of NewTable contract
+type tableColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Conditions string `json:"conditions"`
+	Unique     bool   `json:"unique,omitempty"`
+	References string `json:"references,omitempty"`
+}
+
+func getRows(query string, args ...interface{}) ([]map[string]string, error) {
+	rows, err := model.GetAll(query, -1, args...)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "query": query}).Error("getting application resources")
+	}
+	return rows, err
+}
+
+func appItems(prefix, itype, columns string, appID int64) ([]map[string]string, error) {
+	return getRows(`select `+columns+` from "`+prefix+`_`+itype+`" where app_id=? order by name`, appID)
+}
+
+// jsonString returns the JSON value as a string, the strings are returned without quotes
+func jsonString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	out, _ := json.Marshal(value)
+	return string(out)
+}
+
+func tableColumns(prefix, table, columns string) (string, error) {
+	var cols map[string]interface{}
+	if len(columns) > 0 {
+		if err := json.Unmarshal([]byte(columns), &cols); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling table columns")
+			return ``, err
+		}
+	}
+	list := make([]tableColumn, 0, len(cols))
+	for name, cond := range cols {
+		ctype, err := model.GetColumnType(prefix+`_`+table, name)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column type")
+			return ``, err
+		}
+		column := tableColumn{Name: name, Type: ctype, Conditions: jsonString(cond)}
+		if column.Unique, column.References, err = model.GetColumnConstraints(prefix+`_`+table, name); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column constraints")
+			return ``, err
+		}
+		column.References = strings.TrimPrefix(column.References, prefix+`_`)
+		list = append(list, column)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	out, err := json.Marshal(list)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling table columns")
+	}
+	return string(out), err
+}
+
+// Export collects the resources of the application into the sealed bundle. Menus are taken from
+// the pages of the application and ecosystem parameters are taken if they are used by EcosysParam.
+func Export(prefix string, appID int64, version string) (*Bundle, error) {
+	app, err := model.GetOneRow(`select name, conditions from "`+prefix+`_applications" where id=?`, appID).String()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting application")
+		return nil, err
+	}
+	if len(app) == 0 {
+		return nil, ErrApplication
+	}
+	b := &Bundle{Name: app[`name`], Version: version, Conditions: app[`conditions`], Created: time.Now().Unix()}
+	b.Ecosystem, _ = strconv.ParseInt(strings.TrimSuffix(prefix, `_vde`), 10, 64)
+
+	add := func(itype, columns string, fill func(item *Item, row map[string]string) error) error {
+		rows, err := appItems(prefix, itype, columns, appID)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			item := Item{Type: itype, Name: row[`name`], Value: row[`value`], Conditions: row[`conditions`]}
+			if fill != nil {
+				if err = fill(&item, row); err != nil {
+					return err
+				}
+			}
+			b.Data = append(b.Data, item)
+		}
+		return nil
+	}
+	menus := make(map[string]bool)
+	steps := []struct {
+		itype, columns string
+		fill           func(item *Item, row map[string]string) error
+	}{
+		{TypeTable, `name, permissions, columns, conditions`, func(item *Item, row map[string]string) (err error) {
+			item.Permissions = row[`permissions`]
+			item.Columns, err = tableColumns(prefix, item.Name, row[`columns`])
+			return
+		}},
+		{TypeAppParam, `name, value, conditions`, nil},
+		{TypeLanguage, `name, res, conditions`, func(item *Item, row map[string]string) error {
+			item.Trans = row[`res`]
+			return nil
+		}},
+		{TypeContract, `name, value, conditions`, nil},
+		{TypeComponent, `name, value, params, conditions`, func(item *Item, row map[string]string) error {
+			item.Params = row[`params`]
+			return nil
+		}},
+		{TypeBlock, `name, value, conditions`, nil},
+		{TypePage, `name, value, menu, conditions`, func(item *Item, row map[string]string) error {
+			item.Menu = row[`menu`]
+			menus[item.Menu] = true
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		if err = add(step.itype, step.columns, step.fill); err != nil {
+			return nil, err
+		}
+	}
+	for name := range menus {
+		row, err := getRows(`select name, value, title, conditions from "`+prefix+`_menu" where name=?`, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range row {
+			b.Data = append(b.Data, Item{Type: TypeMenu, Name: item[`name`], Value: item[`value`],
+				Title: item[`title`], Conditions: item[`conditions`]})
+		}
+	}
+	params := make(map[string]bool)
+	for _, item := range b.Data {
+		for _, match := range ecosysParams.FindAllStringSubmatch(item.Value, -1) {
+			params[match[1]] = true
+		}
+	}
+	for name := range params {
+		row, err := getRows(`select name, value, conditions from "`+prefix+`_parameters" where name=?`, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range row {
+			b.Data = append(b.Data, Item{Type: TypeParameter, Name: item[`name`], Value: item[`value`],
+				Conditions: item[`conditions`]})
+		}
+	}
+	if err = b.Seal(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Actions of the install plan
+const (
+	ActionCreate = `create`
+	ActionUpdate = `update`
+	ActionSkip   = `skip`
+
+	typeApplication = `applications`
+)
+
+// Change describes what happens with the resource during the installation
+type Change struct {
+	Type   string   `json:"type"`
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Contract is the contract call of the install plan
+type Contract struct {
+	Contract string            `json:"contract"`
+	Params   map[string]string `json:"params"`
+}
+
+// Plan is the diff between the bundle and the ecosystem and the contracts which install the bundle.
+// If the application doesn't exist then the plan contains only NewApplication contract and the plan
+// must be prepared again after the application has been created.
+type Plan struct {
+	Application int64      `json:"application"`
+	Changes     []Change   `json:"changes"`
+	Contracts   []Contract `json:"contracts"`
+}
+
+// Existing is the resources of the ecosystem, the first key is the type and the second is the name
+type Existing map[string]map[string]map[string]string
+
+var (
+	creators = map[string]string{
+		TypeTable: `NewTable`, TypeParameter: `NewParameter`, TypeAppParam: `NewAppParam`,
+		TypeLanguage: `NewLang`, TypeContract: `NewContract`, TypeComponent: `NewComponent`,
+		TypeBlock: `NewBlock`, TypeMenu: `NewMenu`, TypePage: `NewPage`,
+	}
+	editors = map[string]string{
+		TypeAppParam: `EditAppParam`, TypeLanguage: `EditLang`, TypeContract: `EditContract`,
+		TypeComponent: `EditComponent`, TypeBlock: `EditBlock`, TypeMenu: `EditMenu`, TypePage: `EditPage`,
+	}
+	// withApplication are the types which are created with ApplicationId
+	withApplication = map[string]bool{TypeTable: true, TypeAppParam: true, TypeLanguage: true,
+		TypeContract: true, TypeComponent: true, TypeBlock: true, TypePage: true}
+)
+
+// field is the field of the item which is compared with the column of the existing resource
+type field struct {
+	param  string
+	column string
+	value  func(item *Item) string
+	json   bool
+}
+
+var (
+	valueField      = field{param: `Value`, column: `value`, value: func(item *Item) string { return item.Value }}
+	conditionsField = field{param: `Conditions`, column: `conditions`, value: func(item *Item) string { return item.Conditions }}
+)
+
+var fields = map[string][]field{
+	TypeAppParam:  {valueField, conditionsField},
+	TypeLanguage:  {{param: `Trans`, column: `res`, value: func(item *Item) string { return item.Trans }, json: true}},
+	TypeContract:  {valueField, conditionsField},
+	TypeComponent: {valueField, {param: `Params`, column: `params`, value: func(item *Item) string { return item.Params }}, conditionsField},
+	TypeBlock:     {valueField, conditionsField},
+	TypeMenu:      {valueField, {param: `Title`, column: `title`, value: func(item *Item) string { return item.Title }}, conditionsField},
+	TypePage:      {valueField, {param: `Menu`, column: `menu`, value: func(item *Item) string { return item.Menu }}, conditionsField},
+}
+
+func jsonEqual(left, right string) bool {
+	if left == right {
+		return true
+	}
+	var l, r interface{}
+	if json.Unmarshal([]byte(left), &l) != nil || json.Unmarshal([]byte(right), &r) != nil {
+		return false
+	}
+	return reflect.DeepEqual(l, r)
+}
+
+func jsonMap(value string) map[string]string {
+	var raw map[string]interface{}
+	json.Unmarshal([]byte(value), &raw)
+	out := make(map[string]string, len(raw))
+	for key, val := range raw {
+		out[key] = jsonString(val)
+	}
+	return out
+}
+
+// newParams returns the parameters of the contract which creates the item
+func newParams(item *Item, appID string) map[string]string {
+	params := map[string]string{`Name`: item.Name, `Conditions`: item.Conditions}
+	if withApplication[item.Type] {
+		params[`ApplicationId`] = appID
+	}
+	switch item.Type {
+	case TypeTable:
+		params = map[string]string{`ApplicationId`: appID, `Name`: item.Name, `Columns`: item.Columns,
+			`Permissions`: item.Permissions}
+	case TypeContract:
+		delete(params, `Name`)
+		params[`Value`] = item.Value
+	case TypeLanguage:
+		delete(params, `Conditions`)
+		params[`Trans`] = item.Trans
+	default:
+		for _, f := range fields[item.Type] {
+			params[f.param] = f.value(item)
+		}
+		if item.Type == TypeParameter {
+			params[`Value`] = item.Value
+		}
+	}
+	return params
+}
+
+// diffTable returns the contracts which add new columns and change permissions of the existing table
+func diffTable(item *Item, row map[string]string) ([]string, []Contract) {
+	var (
+		changed   []string
+		contracts []Contract
+		columns   []tableColumn
+	)
+	json.Unmarshal([]byte(item.Columns), &columns)
+	existing := jsonMap(row[`columns`])
+	for _, col := range columns {
+		cond, ok := existing[col.Name]
+		switch {
+		case !ok:
+			changed = append(changed, col.Name)
+			params := map[string]string{`TableName`: item.Name, `Name`: col.Name, `Type`: col.Type,
+				`Permissions`: col.Conditions}
+			if col.Unique {
+				params[`Unique`] = `true`
+			}
+			if len(col.References) > 0 {
+				params[`References`] = col.References
+			}
+			contracts = append(contracts, Contract{Contract: `NewColumn`, Params: params})
+		case !jsonEqual(cond, col.Conditions):
+			changed = append(changed, col.Name)
+			contracts = append(contracts, Contract{Contract: `EditColumn`, Params: map[string]string{
+				`TableName`: item.Name, `Name`: col.Name, `Permissions`: col.Conditions}})
+		}
+	}
+	if !jsonEqual(row[`permissions`], item.Permissions) {
+		perm := jsonMap(item.Permissions)
+		changed = append(changed, `Permissions`)
+		contracts = append(contracts, Contract{Contract: `EditTable`, Params: map[string]string{
+			`Name`: item.Name, `InsertPerm`: perm[`insert`], `UpdatePerm`: perm[`update`],
+			`NewColumnPerm`: perm[`new_column`], `ReadPerm`: perm[`read`]}})
+	}
+	return changed, contracts
+}
+
+// Diff compares the bundle with the existing resources and returns the install plan for the application.
+// Ecosystem parameters are only created because their values are specific for the ecosystem.
+func Diff(b *Bundle, appID int64, existing Existing) *Plan {
+	plan := &Plan{Application: appID, Changes: make([]Change, 0), Contracts: make([]Contract, 0)}
+	app := strconv.FormatInt(appID, 10)
+	for i := range b.Data {
+		item := &b.Data[i]
+		change := Change{Type: item.Type, Name: item.Name, Action: ActionSkip}
+		row, ok := existing[item.Type][item.Name]
+		switch {
+		case !ok:
+			change.Action = ActionCreate
+			plan.Contracts = append(plan.Contracts, Contract{Contract: creators[item.Type],
+				Params: newParams(item, app)})
+		case item.Type == TypeTable:
+			var contracts []Contract
+			if change.Fields, contracts = diffTable(item, row); len(contracts) > 0 {
+				change.Action = ActionUpdate
+				plan.Contracts = append(plan.Contracts, contracts...)
+			}
+		case item.Type != TypeParameter:
+			params := map[string]string{`Id`: row[`id`]}
+			for _, f := range fields[item.Type] {
+				val := f.value(item)
+				if (f.json && !jsonEqual(row[f.column], val)) || (!f.json && row[f.column] != val) {
+					change.Fields = append(change.Fields, f.param)
+				}
+				params[f.param] = val
+			}
+			if len(change.Fields) > 0 {
+				change.Action = ActionUpdate
+				plan.Contracts = append(plan.Contracts, Contract{Contract: editors[item.Type], Params: params})
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan
+}
+
+// Prepare loads the resources of the bundle from the ecosystem and returns the install plan.
+// If appID is zero then the application is searched by the name of the bundle.
+func Prepare(b *Bundle, prefix string, appID int64) (*Plan, error) {
+	if appID == 0 {
+		id, err := model.Single(`select id from "`+prefix+`_applications" where name=?`, b.Name).Int64()
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting application")
+			return nil, err
+		}
+		if id == 0 {
+			return &Plan{
+				Changes: []Change{{Type: typeApplication, Name: b.Name, Action: ActionCreate}},
+				Contracts: []Contract{{Contract: `NewApplication`, Params: map[string]string{
+					`Name`: b.Name, `Conditions`: b.Conditions}}},
+			}, nil
+		}
+		appID = id
+	}
+	existing := make(Existing)
+	for _, item := range b.Data {
+		rows, err := getRows(`select * from "`+prefix+`_`+item.Type+`" where name=?`, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			if existing[item.Type] == nil {
+				existing[item.Type] = make(map[string]map[string]string)
+			}
+			existing[item.Type][item.Name] = rows[0]
+		}
+	}
+	return Diff(b, appID, existing), nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundle
+
+import (
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/migration"
+)
+
+// Upgrade returns the bundle of the contracts of the application of the first ecosystem which
+// have been added to the genesis data after 0.1.6b9. Networks started before that get these
+// contracts only by importing the bundle, the migrations of nodes don't change the contracts.
+func Upgrade(appID int64) *Bundle {
+	b := &Bundle{Name: `upgrade`, Version: consts.VERSION, Conditions: `ContractConditions("MainCondition")`,
+		Ecosystem: 1, Created: time.Now().Unix()}
+	for _, contract := range migration.UpgradeContracts(appID) {
+		b.Data = append(b.Data, Item{Type: TypeContract, Name: contract.Name, Value: contract.Value,
+			Conditions: contract.Conditions})
+	}
+	return b
+}
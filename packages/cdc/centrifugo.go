@@ -0,0 +1,65 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package cdc
+
+import (
+	"encoding/json"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/publisher"
+)
+
+// ChannelPrefix is the prefix of the private centrifugo channels of the table changes.
+// The channel of the table is ChannelPrefix followed by the name of the table with the ecosystem prefix,
+// the subscription to it is signed by the changes/subscribe API only if the table can be read.
+const ChannelPrefix = "$changes_"
+
+// Channel returns the centrifugo channel of the changes of the table
+func Channel(table string) string {
+	return ChannelPrefix + table
+}
+
+func init() {
+	RegisterSink("centrifugo", NewCentrifugoSink)
+}
+
+// CentrifugoSink publishes events to the channels of the changed tables
+type CentrifugoSink struct{}
+
+// NewCentrifugoSink returns the sink of centrifugo
+func NewCentrifugoSink(cfg conf.CDCConfig) (Sink, error) {
+	return &CentrifugoSink{}, nil
+}
+
+// Send publishes events
+func (s *CentrifugoSink) Send(events []*Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err = publisher.Publish(Channel(event.Table), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing
+func (s *CentrifugoSink) Close() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package cdc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+)
+
+const defaultJSONLinesFile = "changes.jsonl"
+
+func init() {
+	RegisterSink("jsonl", NewJSONLinesSink)
+}
+
+// JSONLinesSink appends events to the file, one JSON object per line
+type JSONLinesSink struct {
+	file *os.File
+}
+
+// NewJSONLinesSink opens the file of the sink
+func NewJSONLinesSink(cfg conf.CDCConfig) (Sink, error) {
+	path := cfg.Path
+	if len(path) == 0 {
+		path = filepath.Join(conf.Config.DataDir, defaultJSONLinesFile)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesSink{file: file}, nil
+}
+
+// Send writes events to the file
+func (s *JSONLinesSink) Send(events []*Event) error {
+	enc := json.NewEncoder(s.file)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *JSONLinesSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,90 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package cdc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+const (
+	cursorFile   = "changes.cursor"
+	publishLimit = 1000
+)
+
+// Publisher sends the captured changes to the sink.
+// The identifier of the last sent change is stored in the cursor file, so the delivery
+// is at-least-once: the events after the stored cursor can be sent again after restart.
+type Publisher struct {
+	sink       Sink
+	cursorPath string
+	lastID     int64
+}
+
+// NewPublisher creates the publisher for the sink from the config
+func NewPublisher(cfg conf.CDCConfig) (*Publisher, error) {
+	sink, err := NewSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p := &Publisher{
+		sink:       sink,
+		cursorPath: filepath.Join(conf.Config.DataDir, cursorFile),
+	}
+	data, err := ioutil.ReadFile(p.cursorPath)
+	if err != nil && !os.IsNotExist(err) {
+		sink.Close()
+		return nil, err
+	}
+	p.lastID = converter.StrToInt64(strings.TrimSpace(string(data)))
+	return p, nil
+}
+
+// Publish sends the next portion of changes to the sink and returns the count of sent events
+func (p *Publisher) Publish() (int, error) {
+	changes, err := model.GetChangesAfterID(p.lastID, publishLimit)
+	if err != nil || len(changes) == 0 {
+		return 0, err
+	}
+	events := make([]*Event, 0, len(changes))
+	for i := range changes {
+		event, err := NewEvent(&changes[i])
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	if err = p.sink.Send(events); err != nil {
+		return 0, err
+	}
+	p.lastID = changes[len(changes)-1].ID
+	if err = ioutil.WriteFile(p.cursorPath, []byte(converter.Int64ToStr(p.lastID)), 0644); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// Close closes the sink
+func (p *Publisher) Close() error {
+	return p.sink.Close()
+}
@@ -0,0 +1,86 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package cdc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+// Event is the change of the table row which is sent to sinks
+type Event struct {
+	ID      int64             `json:"id"`
+	BlockID int64             `json:"block_id"`
+	TxHash  string            `json:"tx_hash"`
+	Table   string            `json:"table"`
+	TableID string            `json:"table_id"`
+	Event   string            `json:"event"`
+	Old     map[string]string `json:"old,omitempty"`
+	New     map[string]string `json:"new,omitempty"`
+}
+
+// NewEvent converts the captured change to event
+func NewEvent(change *model.Change) (*Event, error) {
+	event := &Event{
+		ID:      change.ID,
+		BlockID: change.BlockID,
+		TxHash:  hex.EncodeToString(change.TxHash),
+		Table:   change.NameTable,
+		TableID: change.TableID,
+		Event:   change.Event,
+	}
+	if len(change.Old) > 0 {
+		if err := json.Unmarshal([]byte(change.Old), &event.Old); err != nil {
+			return nil, err
+		}
+	}
+	if len(change.New) > 0 {
+		if err := json.Unmarshal([]byte(change.New), &event.New); err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
+
+// Sink receives the changes in the order they have been made
+type Sink interface {
+	Send(events []*Event) error
+	Close() error
+}
+
+// SinkCreator creates a sink with the specified config
+type SinkCreator func(cfg conf.CDCConfig) (Sink, error)
+
+var sinks = map[string]SinkCreator{}
+
+// RegisterSink registers the sink under the name which can be used in the config
+func RegisterSink(name string, creator SinkCreator) {
+	sinks[name] = creator
+}
+
+// NewSink returns the sink which is specified in the config
+func NewSink(cfg conf.CDCConfig) (Sink, error) {
+	creator, ok := sinks[cfg.Sink]
+	if !ok {
+		return nil, fmt.Errorf("unknown sink %s", cfg.Sink)
+	}
+	return creator(cfg)
+}
@@ -0,0 +1,50 @@
+package cdc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvent(t *testing.T) {
+	event, err := NewEvent(&model.Change{
+		ID: 10, BlockID: 5, TxHash: []byte{1, 2}, NameTable: "1_pages", TableID: "3",
+		Event: model.ChangeUpdate, Old: `{"value":"old"}`, New: `{"value":"new"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &Event{ID: 10, BlockID: 5, TxHash: "0102", Table: "1_pages", TableID: "3",
+		Event: model.ChangeUpdate, Old: map[string]string{"value": "old"}, New: map[string]string{"value": "new"}}, event)
+
+	_, err = NewEvent(&model.Change{Old: `{`})
+	assert.Error(t, err)
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cdc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "changes.jsonl")
+	sink, err := NewSink(conf.CDCConfig{Sink: "jsonl", Path: path})
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Send([]*Event{
+		{ID: 1, BlockID: 2, Table: "1_keys", TableID: "7", Event: model.ChangeInsert},
+		{ID: 2, BlockID: 2, Table: "1_keys", TableID: "7", Event: model.ChangeRollback},
+	}))
+	assert.NoError(t, sink.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"block_id":2,"tx_hash":"","table":"1_keys","table_id":"7","event":"insert"}
+{"id":2,"block_id":2,"tx_hash":"","table":"1_keys","table_id":"7","event":"rollback"}
+`, string(data))
+
+	_, err = NewSink(conf.CDCConfig{Sink: "unknown"})
+	assert.Error(t, err)
+}
@@ -73,6 +73,19 @@ type TokenMovementConfig struct {
 	Subject  string
 }
 
+// CDCConfig represents parameters of change data capture
+type CDCConfig struct {
+	Enabled bool   // Enabled turns on capturing of table changes
+	Sink    string // Sink is the name of the sink which receives changes: jsonl|centrifugo
+	Path    string // Path is the filepath of the jsonl sink
+}
+
+// MempoolConfig represents the limits of the mempool of transactions
+type MempoolConfig struct {
+	MaxCount int   // MaxCount is the max count of transactions in the mempool
+	MaxSize  int64 // MaxSize is the max size of transactions in the mempool in bytes
+}
+
 // GlobalConfig is storing all startup config as global struct
 type GlobalConfig struct {
 	KeyID        int64  `toml:"-"`
@@ -84,6 +97,7 @@ type GlobalConfig struct {
 	LockFilePath      string
 	DataDir           string // application work dir (cwd by default)
 	KeysDir           string // place for private keys files: NodePrivateKey, PrivateKey
+	SignerSocket      string // unix socket of the remote signer, NodePrivateKey file is used if it's empty
 	TempDir           string // temporary dir
 	FirstBlockPath    string
 	TLS               bool   // TLS is on/off. It is required for https
@@ -92,6 +106,8 @@ type GlobalConfig struct {
 	RunningMode       string
 
 	MaxPageGenerationTime int64 // in milliseconds
+	TemplateCacheSize     int   // max count of DBFind results of templates in the cache, 0 disables the cache
+	MaxReorgDepth         int64 // max count of blocks which can be rolled back on fork, 0 means rb_blocks_1
 
 	TCPServer HostPort
 	HTTP      HostPort
@@ -101,6 +117,8 @@ type GlobalConfig struct {
 	Centrifugo    CentrifugoConfig
 	Log           LogConfig
 	TokenMovement TokenMovementConfig
+	CDC           CDCConfig
+	Mempool       MempoolConfig
 
 	NodesAddr []string
 }
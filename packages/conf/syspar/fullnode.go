@@ -18,8 +18,22 @@ const publicKeyLength = 64
 
 var (
 	errFullNodeInvalidValues = errors.New("Invalid values of the full_node parameter")
+	errNodeKeyInvalidValues  = errors.New("Invalid values of the previous keys of full_node")
 )
 
+type nodeKeyJSON struct {
+	PublicKey string      `json:"public_key"`
+	FromBlock json.Number `json:"from_block"`
+	ToBlock   json.Number `json:"to_block"`
+}
+
+// NodeKey is the previous public key of the node, it's valid from FromBlock to ToBlock inclusive
+type NodeKey struct {
+	PublicKey []byte
+	FromBlock int64
+	ToBlock   int64
+}
+
 //because of PublicKey is byte
 type fullNodeJSON struct {
 	TCPAddress string      `json:"tcp_address"`
@@ -27,6 +41,9 @@ type fullNodeJSON struct {
 	KeyID      json.Number `json:"key_id"`
 	PublicKey  string      `json:"public_key"`
 	UnbanTime  json.Number `json:"unban_time,er"`
+	// KeyFromBlock and Keys are omitted if the node key has never been rotated
+	KeyFromBlock json.Number   `json:"key_from_block,omitempty"`
+	Keys         []nodeKeyJSON `json:"keys,omitempty"`
 }
 
 // FullNode is storing full node data
@@ -36,6 +53,10 @@ type FullNode struct {
 	KeyID      int64
 	PublicKey  []byte
 	UnbanTime  time.Time
+	// KeyFromBlock is the block since which PublicKey is valid
+	KeyFromBlock int64
+	// Keys is the history of the previous public keys of the node
+	Keys []NodeKey
 }
 
 // UnmarshalJSON is custom json unmarshaller
@@ -55,6 +76,20 @@ func (fn *FullNode) UnmarshalJSON(b []byte) (err error) {
 		return err
 	}
 	fn.UnbanTime = time.Unix(converter.StrToInt64(data.UnbanTime.String()), 0)
+	fn.KeyFromBlock = converter.StrToInt64(data.KeyFromBlock.String())
+
+	fn.Keys = make([]NodeKey, 0, len(data.Keys))
+	for _, item := range data.Keys {
+		key := NodeKey{
+			FromBlock: converter.StrToInt64(item.FromBlock.String()),
+			ToBlock:   converter.StrToInt64(item.ToBlock.String()),
+		}
+		if key.PublicKey, err = hex.DecodeString(item.PublicKey); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": item.PublicKey}).Error("converting previous public key of full node from hex")
+			return err
+		}
+		fn.Keys = append(fn.Keys, key)
+	}
 
 	if err = fn.Validate(); err != nil {
 		return err
@@ -71,6 +106,16 @@ func (fn *FullNode) MarshalJSON() ([]byte, error) {
 		PublicKey:  hex.EncodeToString(fn.PublicKey),
 		UnbanTime:  json.Number(strconv.FormatInt(fn.UnbanTime.Unix(), 10)),
 	}
+	if fn.KeyFromBlock > 0 {
+		jfn.KeyFromBlock = json.Number(strconv.FormatInt(fn.KeyFromBlock, 10))
+	}
+	for _, key := range fn.Keys {
+		jfn.Keys = append(jfn.Keys, nodeKeyJSON{
+			PublicKey: hex.EncodeToString(key.PublicKey),
+			FromBlock: json.Number(strconv.FormatInt(key.FromBlock, 10)),
+			ToBlock:   json.Number(strconv.FormatInt(key.ToBlock, 10)),
+		})
+	}
 
 	data, err := json.Marshal(jfn)
 	if err != nil {
@@ -109,5 +154,42 @@ func (fn *FullNode) Validate() error {
 		return err
 	}
 
+	for _, key := range fn.Keys {
+		if len(key.PublicKey) != publicKeyLength || key.ToBlock < key.FromBlock {
+			return errNodeKeyInvalidValues
+		}
+	}
+
+	return nil
+}
+
+// PublicKeysAt returns the public keys of the node which are valid at the block,
+// both the previous and the new keys are valid during the overlap window of the rotation
+func (fn *FullNode) PublicKeysAt(blockID int64) [][]byte {
+	keys := make([][]byte, 0, 2)
+	if blockID >= fn.KeyFromBlock {
+		keys = append(keys, fn.PublicKey)
+	}
+	for i := len(fn.Keys) - 1; i >= 0; i-- {
+		if key := fn.Keys[i]; blockID >= key.FromBlock && blockID <= key.ToBlock {
+			keys = append(keys, key.PublicKey)
+		}
+	}
+	return keys
+}
+
+// RotateKey makes publicKey the key of the node since the block,
+// the current key is kept in the history and it's valid for overlap blocks more
+func (fn *FullNode) RotateKey(publicKey []byte, blockID, overlap int64) error {
+	if len(publicKey) != publicKeyLength {
+		return errFullNodeInvalidValues
+	}
+	fn.Keys = append(fn.Keys, NodeKey{
+		PublicKey: fn.PublicKey,
+		FromBlock: fn.KeyFromBlock,
+		ToBlock:   blockID + overlap,
+	})
+	fn.PublicKey = publicKey
+	fn.KeyFromBlock = blockID
 	return nil
 }
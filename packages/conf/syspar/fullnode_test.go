@@ -1,6 +1,7 @@
 package syspar
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"testing"
 
@@ -44,3 +45,45 @@ func TestFullNode(t *testing.T) {
 		}
 	}
 }
+
+func TestFullNodeKeys(t *testing.T) {
+	oldKey := "c1a9e7b2fb8cea2a272e183c3e27e2d59a3ebe613f51873a46885c9201160bd263ef43b583b631edd1284ab42483712fd2ccc40864fe9368115ceeee47a7c7d0"
+	newKey := "254b0dc4a88c4b5a4e7c4bd0f40f7a4b3d07bfb4e10df5f68b41c8d16fd0e9a7f0ab83f8c5c4a1c13a5b1b6e6e87aac5b98f7c4d6d36bd8f91e2f70d23e8d1c5"
+
+	var fn FullNode
+	err := json.Unmarshal([]byte(`{"tcp_address":"127.0.0.1", "api_address":"https://127.0.0.1", "key_id":"100", "public_key":"`+oldKey+`"}`), &fn)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{fn.PublicKey}, fn.PublicKeysAt(1))
+
+	pub, err := hex.DecodeString(newKey)
+	require.NoError(t, err)
+	require.NoError(t, fn.RotateKey(pub, 10, 5))
+	assert.Error(t, fn.RotateKey(pub[1:], 20, 5))
+
+	cases := []struct {
+		blockID int64
+		keys    []string
+	}{
+		{9, []string{oldKey}},
+		{10, []string{newKey, oldKey}},
+		{15, []string{newKey, oldKey}},
+		{16, []string{newKey}},
+	}
+	for _, v := range cases {
+		keys := make([]string, 0)
+		for _, key := range fn.PublicKeysAt(v.blockID) {
+			keys = append(keys, hex.EncodeToString(key))
+		}
+		assert.Equal(t, v.keys, keys, "block %d", v.blockID)
+	}
+
+	data, err := json.Marshal(&fn)
+	require.NoError(t, err)
+	var unfn FullNode
+	require.NoError(t, json.Unmarshal(data, &unfn))
+	assert.Equal(t, fn, unfn)
+
+	err = json.Unmarshal([]byte(`{"tcp_address":"127.0.0.1", "api_address":"https://127.0.0.1", "key_id":"100", "public_key":"`+newKey+`",
+		"key_from_block":"10", "keys":[{"public_key":"`+oldKey+`", "from_block":"0", "to_block":"-1"}]}`), &unfn)
+	assert.EqualError(t, err, errNodeKeyInvalidValues.Error())
+}
@@ -78,6 +78,10 @@ const (
 	NodeBanTime = `node_ban_time`
 	// LocalNodeBanTime is value of local ban time for bad nodes (in ms)
 	LocalNodeBanTime = `local_node_ban_time`
+	// Consensus is the name of the consensus algorithm of full nodes
+	Consensus = `consensus`
+	// NodeKeyOverlap is the count of blocks while the previous key of the rotated node key is valid
+	NodeKeyOverlap = `node_key_overlap`
 )
 
 var (
@@ -266,6 +270,35 @@ func GetNodePublicKeyByPosition(position int64) ([]byte, error) {
 	return nodeData.PublicKey, nil
 }
 
+// GetNodePublicKeysByPosition is retrieving node public keys which are valid at the block
+func GetNodePublicKeysByPosition(position, blockID int64) ([][]byte, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if int64(len(nodesByPosition)) <= position || position < 0 {
+		return nil, fmt.Errorf("incorrect position")
+	}
+	return nodesByPosition[position].PublicKeysAt(blockID), nil
+}
+
+// CheckNodeSign checks the signature of the node at the position by the keys which are valid at the block
+func CheckNodeSign(position, blockID int64, forSign string, sign []byte) (bool, error) {
+	publicKeys, err := GetNodePublicKeysByPosition(position, blockID)
+	if err != nil {
+		return false, err
+	}
+	if len(publicKeys) == 0 {
+		log.WithFields(log.Fields{"type": consts.EmptyObject, "node_position": position, "block_id": blockID}).Error("node public key is empty")
+		return false, fmt.Errorf("empty nodePublicKey")
+	}
+	var ok bool
+	for _, publicKey := range publicKeys {
+		if ok, err = crypto.CheckSign(publicKey, forSign, sign); ok {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
 // GetSleepTimeByKey is returns sleep time by key
 func GetSleepTimeByKey(myKeyID, prevBlockNodePosition int64) (int64, error) {
 
@@ -443,6 +476,16 @@ func GetRbBlocks1() int64 {
 	return SysInt64(RbBlocks1)
 }
 
+// GetNodeKeyOverlap is returns the count of blocks while the previous node key is valid after the rotation
+func GetNodeKeyOverlap() int64 {
+	return SysInt64(NodeKeyOverlap)
+}
+
+// GetConsensus is returns the name of the consensus algorithm
+func GetConsensus() string {
+	return SysString(Consensus)
+}
+
 // HasSys returns boolean whether this system parameter exists
 func HasSys(name string) bool {
 	mutex.RLock()
@@ -0,0 +1,97 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+// BFT is the consensus with deterministic finality. The block at the height is generated
+// by the leader of the round, where the leader changes every round after the previous block.
+// The leader generates the block only on top of the final block,
+// so the final blocks are never rolled back
+type BFT struct {
+	finality
+}
+
+// NewBFT returns the BFT consensus which keeps the votes in the pool
+func NewBFT(params Params, votes *Votes) *BFT {
+	return &BFT{
+		finality: finality{
+			params: params,
+			votes:  votes,
+		},
+	}
+}
+
+// Name returns the name of the algorithm
+func (b *BFT) Name() string {
+	return NameBFT
+}
+
+func (b *BFT) roundDuration() time.Duration {
+	duration := b.params.BlocksGap + b.params.BlockGenerationTime
+	if duration < time.Second {
+		duration = time.Second
+	}
+	return duration
+}
+
+// Leader returns the position of the node which generates the block after prev at the specified time
+func (b *BFT) Leader(prev *utils.BlockData, at time.Time) (int64, error) {
+	if b.params.NodesCount <= 0 {
+		return 0, ErrUnknownValidator
+	}
+	start := time.Unix(prev.Time, 0).Add(b.params.BlocksGap)
+	if at.Before(start) {
+		return 0, ErrTooEarly
+	}
+	round := int64(at.Sub(start) / b.roundDuration())
+	return (prev.BlockID + 1 + round) % b.params.NodesCount, nil
+}
+
+// IsLeader returns true if the node is the leader of the round and the previous block is final
+func (b *BFT) IsLeader(nodePosition int64, prev *utils.BlockData, at time.Time) (bool, error) {
+	if !b.IsFinal(prev) {
+		return false, nil
+	}
+	leader, err := b.Leader(prev, at)
+	if err == ErrTooEarly {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return leader == nodePosition, nil
+}
+
+// ValidateBlock checks that the block has been generated by the leader of the round
+func (b *BFT) ValidateBlock(header, prev *utils.BlockData) error {
+	if header.NodePosition < 0 || header.NodePosition >= b.params.NodesCount {
+		return ErrUnknownValidator
+	}
+	leader, err := b.Leader(prev, time.Unix(header.Time, 0))
+	if err != nil {
+		return err
+	}
+	if leader != header.NodePosition {
+		return ErrInvalidLeader
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+const (
+	// NameRoundRobin is the name of the round-robin consensus where the nodes generate blocks in turn
+	NameRoundRobin = `roundrobin`
+	// NameBFT is the name of the consensus with the explicit finality votes of the nodes
+	NameBFT = `bft`
+)
+
+var (
+	// ErrUnknownConsensus is returned if the name of consensus is unknown
+	ErrUnknownConsensus = errors.New("Unknown consensus")
+	// ErrInvalidLeader is returned if the block has been generated not by the leader
+	ErrInvalidLeader = errors.New("Block has been generated not by the leader")
+	// ErrTooEarly is returned if the block has been generated before the gap between blocks
+	ErrTooEarly = errors.New("Block has been generated too early")
+	// ErrUnknownValidator is returned if the node position is out of the list of nodes
+	ErrUnknownValidator = errors.New("Unknown validator")
+	// ErrDoubleVote is returned if the validator votes for different blocks at the same height
+	ErrDoubleVote = errors.New("Validator has already voted for another block")
+	// ErrInvalidVote is returned if the sign of the vote is incorrect
+	ErrInvalidVote = errors.New("Incorrect sign of the vote")
+
+	errFirstBlock    = errors.New("First block not found")
+	errBlockNotFound = errors.New("Block not found")
+)
+
+// Params contains the parameters of the consensus
+type Params struct {
+	FirstBlockTime      time.Time
+	BlockGenerationTime time.Duration
+	BlocksGap           time.Duration
+	NodesCount          int64
+}
+
+// Vote is the finality attestation which is signed by the node for the block.
+// Round is the round of votes at the height, the nodes vote in the next round if the votes are split
+type Vote struct {
+	BlockID      int64
+	Hash         []byte
+	NodePosition int64
+	Round        int64
+	Sign         []byte
+}
+
+// ForSign returns the data of the vote which is signed by the node
+func (v *Vote) ForSign() string {
+	return fmt.Sprintf("vote,%d,%x,%d,%d", v.BlockID, v.Hash, v.NodePosition, v.Round)
+}
+
+// Network delivers the votes of the node to other nodes
+type Network interface {
+	Broadcast(vote *Vote) error
+}
+
+// Consensus is the algorithm of agreement between full nodes.
+// It elects the leader which generates the next block, validates the received blocks
+// and decides when the block can't be rolled back
+type Consensus interface {
+	// Name returns the name of the algorithm
+	Name() string
+	// IsLeader returns true if the node has to generate the block after prev at the specified time
+	IsLeader(nodePosition int64, prev *utils.BlockData, at time.Time) (bool, error)
+	// ValidateBlock checks that the block has been generated by the leader
+	ValidateBlock(header, prev *utils.BlockData) error
+	// NewVote returns the unsigned attestation of the node for the block
+	NewVote(header *utils.BlockData, nodePosition int64) (*Vote, error)
+	// AddVote registers the attestation and returns true if the block is final
+	AddVote(vote *Vote) (bool, error)
+	// IsFinal returns true if the block can't be rolled back
+	IsFinal(header *utils.BlockData) bool
+}
+
+// IsValidName returns true if the consensus with the specified name exists
+func IsValidName(name string) bool {
+	return name == NameRoundRobin || name == NameBFT
+}
+
+// New returns the consensus by the name, the empty name means round-robin
+func New(name string, params Params, votes *Votes) (Consensus, error) {
+	switch name {
+	case NameRoundRobin, ``:
+		return NewRoundRobin(params, votes), nil
+	case NameBFT:
+		return NewBFT(params, votes), nil
+	}
+	return nil, ErrUnknownConsensus
+}
@@ -0,0 +1,205 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testParams(nodes int64) Params {
+	return Params{
+		FirstBlockTime:      time.Unix(1000, 0),
+		BlockGenerationTime: time.Second,
+		BlocksGap:           time.Second,
+		NodesCount:          nodes,
+	}
+}
+
+func TestNew(t *testing.T) {
+	for name, expected := range map[string]string{``: NameRoundRobin, NameRoundRobin: NameRoundRobin, NameBFT: NameBFT} {
+		c, err := New(name, testParams(4), NewVotes())
+		require.NoError(t, err)
+		assert.Equal(t, expected, c.Name())
+	}
+	_, err := New(`pow`, testParams(4), NewVotes())
+	assert.Equal(t, ErrUnknownConsensus, err)
+	assert.False(t, IsValidName(``))
+}
+
+func TestBFTLeader(t *testing.T) {
+	bft := NewBFT(testParams(4), NewVotes())
+	prev := &utils.BlockData{BlockID: 5, Time: 1000, Hash: []byte{5}}
+
+	cases := []struct {
+		at     int64
+		leader int64
+		err    error
+	}{
+		{at: 1000, err: ErrTooEarly},
+		{at: 1001, leader: 2},
+		{at: 1002, leader: 2},
+		{at: 1003, leader: 3},
+		{at: 1005, leader: 0},
+		{at: 1009, leader: 2},
+	}
+	for _, v := range cases {
+		leader, err := bft.Leader(prev, time.Unix(v.at, 0))
+		assert.Equal(t, v.err, err, "at %d", v.at)
+		assert.Equal(t, v.leader, leader, "at %d", v.at)
+	}
+
+	assert.NoError(t, bft.ValidateBlock(&utils.BlockData{BlockID: 6, Time: 1003, NodePosition: 3}, prev))
+	assert.Equal(t, ErrInvalidLeader, bft.ValidateBlock(&utils.BlockData{BlockID: 6, Time: 1003, NodePosition: 2}, prev))
+	assert.Equal(t, ErrUnknownValidator, bft.ValidateBlock(&utils.BlockData{BlockID: 6, Time: 1003, NodePosition: 4}, prev))
+	assert.Equal(t, ErrTooEarly, bft.ValidateBlock(&utils.BlockData{BlockID: 6, Time: 1000, NodePosition: 2}, prev))
+
+	// the leader waits for the finality of the previous block
+	ok, err := bft.IsLeader(2, prev, time.Unix(1001, 0))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	for i := int64(0); i < bft.Quorum(); i++ {
+		_, err = bft.AddVote(&Vote{BlockID: prev.BlockID, Hash: prev.Hash, NodePosition: i})
+		require.NoError(t, err)
+	}
+	ok, err = bft.IsLeader(2, prev, time.Unix(1001, 0))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestBFTVotes(t *testing.T) {
+	bft := NewBFT(testParams(4), NewVotes())
+	header := &utils.BlockData{BlockID: 2, Hash: []byte{1}}
+	other := &utils.BlockData{BlockID: 2, Hash: []byte{2}}
+
+	assert.Equal(t, int64(3), bft.Quorum())
+	for i, final := range []bool{false, false, true} {
+		vote, err := bft.NewVote(header, int64(i))
+		require.NoError(t, err)
+		ok, err := bft.AddVote(vote)
+		require.NoError(t, err)
+		assert.Equal(t, final, ok)
+	}
+	assert.True(t, bft.IsFinal(header))
+	assert.False(t, bft.IsFinal(other))
+
+	// the repeated vote is accepted, the vote for another block at the same height is not
+	_, err := bft.AddVote(&Vote{BlockID: 2, Hash: []byte{1}, NodePosition: 0})
+	assert.NoError(t, err)
+	_, err = bft.NewVote(other, 0)
+	assert.Equal(t, ErrDoubleVote, err)
+	_, err = bft.AddVote(&Vote{BlockID: 2, Hash: []byte{2}, NodePosition: 1})
+	assert.Equal(t, ErrDoubleVote, err)
+	_, err = bft.AddVote(&Vote{BlockID: 2, Hash: []byte{2}, NodePosition: 7})
+	assert.Equal(t, ErrUnknownValidator, err)
+
+	bft.votes.SetVerifier(func(vote *Vote) error {
+		return ErrInvalidVote
+	})
+	_, err = bft.AddVote(&Vote{BlockID: 3, Hash: []byte{3}, NodePosition: 3})
+	assert.Equal(t, ErrInvalidVote, err)
+}
+
+func TestBFTSplitVotes(t *testing.T) {
+	bft := NewBFT(testParams(4), NewVotes())
+	blockA := &utils.BlockData{BlockID: 2, Hash: []byte{2}}
+	blockB := &utils.BlockData{BlockID: 2, Hash: []byte{1}}
+
+	for i, header := range []*utils.BlockData{blockA, blockA, blockB} {
+		vote, err := bft.NewVote(header, int64(i))
+		require.NoError(t, err)
+		_, err = bft.AddVote(vote)
+		require.NoError(t, err)
+	}
+	// the node is locked on its vote while the last node can give the quorum to blockA
+	_, err := bft.NewVote(blockB, 0)
+	assert.Equal(t, ErrDoubleVote, err)
+
+	vote, err := bft.NewVote(blockB, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), vote.Round)
+	ok, err := bft.AddVote(vote)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// the round is over, all nodes vote for the block with the smaller hash in the next round
+	for i, header := range []*utils.BlockData{blockA, blockA, blockB} {
+		vote, err = bft.NewVote(header, int64(i))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), vote.Round)
+		assert.Equal(t, blockB.Hash, vote.Hash)
+		ok, err = bft.AddVote(vote)
+		require.NoError(t, err)
+		assert.Equal(t, i == 2, ok)
+	}
+	assert.True(t, bft.IsFinal(blockB))
+	assert.False(t, bft.IsFinal(blockA))
+
+	// the vote in the round is repeated
+	vote, err = bft.NewVote(blockA, 0)
+	require.NoError(t, err)
+	assert.Equal(t, blockB.Hash, vote.Hash)
+	_, err = bft.AddVote(&Vote{BlockID: 2, Hash: blockA.Hash, NodePosition: 0, Round: 1})
+	assert.Equal(t, ErrDoubleVote, err)
+	_, err = bft.AddVote(&Vote{BlockID: 2, Hash: blockA.Hash, NodePosition: 0, Round: -1})
+	assert.Equal(t, ErrInvalidVote, err)
+}
+
+func TestFinalityQuorum(t *testing.T) {
+	for nodes, quorum := range map[int64]int64{1: 1, 2: 2, 3: 2, 4: 3, 6: 4, 7: 5} {
+		rr := NewRoundRobin(testParams(nodes), NewVotes())
+		assert.Equal(t, quorum, rr.Quorum(), "nodes %d", nodes)
+	}
+}
+
+type testStore struct {
+	final map[int64][]byte
+	votes map[int64][]*Vote
+}
+
+func (s *testStore) SaveFinal(blockID int64, hash []byte, votes []*Vote) (bool, error) {
+	s.final[blockID] = hash
+	s.votes[blockID] = votes
+	return true, nil
+}
+
+func (s *testStore) IsFinal(blockID int64, hash []byte) (bool, error) {
+	return string(s.final[blockID]) == string(hash), nil
+}
+
+func TestFinalityStore(t *testing.T) {
+	store := &testStore{final: make(map[int64][]byte), votes: make(map[int64][]*Vote)}
+	rr := NewRoundRobin(testParams(3), NewVotes().SetStore(store))
+	header := &utils.BlockData{BlockID: 2, Hash: []byte{2}}
+
+	for i := int64(0); i < 2; i++ {
+		_, err := rr.AddVote(&Vote{BlockID: 2, Hash: header.Hash, NodePosition: i, Sign: []byte{byte(i)}})
+		require.NoError(t, err)
+	}
+	assert.True(t, rr.IsFinal(header))
+	assert.Len(t, store.votes[2], 2)
+
+	// the final block is found in the store after the restart of the node
+	rr = NewRoundRobin(testParams(3), NewVotes().SetStore(store))
+	assert.True(t, rr.IsFinal(header))
+	assert.False(t, rr.IsFinal(&utils.BlockData{BlockID: 3, Hash: []byte{3}}))
+}
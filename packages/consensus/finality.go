@@ -0,0 +1,139 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"bytes"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+// finality is the rule where the block is final when 2/3 of nodes have signed
+// the attestations for it
+type finality struct {
+	params Params
+	votes  *Votes
+}
+
+// Quorum returns the count of attestations which makes the block final
+func (f *finality) Quorum() int64 {
+	return (f.params.NodesCount*2 + 2) / 3
+}
+
+// NewVote returns the attestation of the node for the block.
+// The node can't attest two different blocks at the same height in one round. It's locked on its vote
+// until the round is over, then it votes in the next round for the block which is preferred by the votes
+// of the previous round, so the split votes don't stop the chain
+func (f *finality) NewVote(header *utils.BlockData, nodePosition int64) (*Vote, error) {
+	if nodePosition < 0 || nodePosition >= f.params.NodesCount {
+		return nil, ErrUnknownValidator
+	}
+	round, prev := f.round(header.BlockID)
+	hash := header.Hash
+	if round > 0 {
+		hash = f.preferred(prev, header.Hash)
+	}
+	if voted := f.votes.Voted(header.BlockID, round, nodePosition); voted != nil {
+		if round == 0 && !bytes.Equal(voted, header.Hash) {
+			return nil, ErrDoubleVote
+		}
+		hash = voted
+	}
+	return &Vote{
+		BlockID:      header.BlockID,
+		Hash:         hash,
+		NodePosition: nodePosition,
+		Round:        round,
+	}, nil
+}
+
+// AddVote registers the attestation and returns true if the block has got the quorum in the round of the vote
+func (f *finality) AddVote(vote *Vote) (bool, error) {
+	if vote.NodePosition < 0 || vote.NodePosition >= f.params.NodesCount {
+		return false, ErrUnknownValidator
+	}
+	if vote.Round < 0 {
+		return false, ErrInvalidVote
+	}
+	count, err := f.votes.add(vote)
+	if err != nil {
+		return false, err
+	}
+	if count < f.Quorum() {
+		return false, nil
+	}
+	return true, f.votes.setFinal(vote.BlockID, vote.Round, vote.Hash)
+}
+
+// isOver returns true if none of the blocks can get the quorum in the round,
+// even if all nodes which haven't voted yet vote for it
+func (f *finality) isOver(t tally) bool {
+	var max int64
+	for _, count := range t.counts {
+		if count > max {
+			max = count
+		}
+	}
+	return max+f.params.NodesCount-t.voters < f.Quorum()
+}
+
+// round returns the current round of votes at the height and the tally of the previous round
+func (f *finality) round(blockID int64) (int64, tally) {
+	var (
+		round int64
+		prev  tally
+	)
+	for {
+		t := f.votes.tally(blockID, round)
+		if !f.isOver(t) {
+			return round, prev
+		}
+		prev = t
+		round++
+	}
+}
+
+// preferred returns the block with the most votes in the round which is over, the smaller hash wins the tie.
+// The block must have more votes than the count of faulty nodes, so at least one of its votes is correct.
+// The node keeps its own block if there is no such block
+func (f *finality) preferred(t tally, own []byte) []byte {
+	var (
+		best  []byte
+		count int64
+	)
+	min := f.params.NodesCount - f.Quorum() + 1
+	for hash, c := range t.counts {
+		if c < min {
+			continue
+		}
+		if c > count || (c == count && bytes.Compare([]byte(hash), best) < 0) {
+			best, count = []byte(hash), c
+		}
+	}
+	if best == nil {
+		return own
+	}
+	return best
+}
+
+// IsFinal returns true if the block has got the quorum of attestations. The first block is always final
+func (f *finality) IsFinal(header *utils.BlockData) bool {
+	if header.BlockID <= 1 {
+		return true
+	}
+	return f.votes.IsFinal(header.BlockID, header.Hash)
+}
@@ -0,0 +1,213 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	nodeVotes = NewVotes().SetVerifier(verifyVote).SetStore(&blockStore{})
+	network   Network
+)
+
+// Attestation is the sign of the node in the aggregated attestations of the final block
+type Attestation struct {
+	NodePosition int64  `json:"node_position"`
+	Round        int64  `json:"round"`
+	Sign         []byte `json:"sign"`
+}
+
+// blockStore saves the aggregated attestations of final blocks in the blockchain table
+type blockStore struct{}
+
+func (s *blockStore) SaveFinal(blockID int64, hash []byte, votes []*Vote) (bool, error) {
+	attestations := make([]Attestation, 0, len(votes))
+	for _, vote := range votes {
+		attestations = append(attestations, Attestation{NodePosition: vote.NodePosition, Round: vote.Round, Sign: vote.Sign})
+	}
+	data, err := json.Marshal(attestations)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling attestations")
+		return false, err
+	}
+	block := &model.Block{}
+	saved, err := block.SetFinal(nil, blockID, hash, data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("saving final block")
+		return false, err
+	}
+	if saved {
+		log.WithFields(log.Fields{"block_id": blockID}).Debug("block is final")
+	}
+	return saved, nil
+}
+
+func (s *blockStore) IsFinal(blockID int64, hash []byte) (bool, error) {
+	block := &model.Block{}
+	final, err := block.IsFinal(blockID, hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("checking final block")
+	}
+	return final, err
+}
+
+// GetAttestations returns the aggregated attestations of the final block
+func GetAttestations(block *model.Block) ([]Attestation, error) {
+	attestations := make([]Attestation, 0)
+	if len(block.Attestations) == 0 {
+		return attestations, nil
+	}
+	if err := json.Unmarshal(block.Attestations, &attestations); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "block_id": block.ID}).Error("unmarshalling attestations")
+		return nil, err
+	}
+	return attestations, nil
+}
+
+// SetNetwork sets the network which delivers the votes of this node
+func SetNetwork(n Network) {
+	network = n
+}
+
+// Build returns the consensus which is selected by the system parameter
+func Build(transaction *model.DbTransaction) (Consensus, error) {
+	firstBlock := model.Block{}
+	found, err := firstBlock.Get(1)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting first block")
+		return nil, err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound}).Error("first block not found")
+		return nil, errFirstBlock
+	}
+
+	params := Params{
+		FirstBlockTime:      time.Unix(firstBlock.Time, 0),
+		BlockGenerationTime: time.Millisecond * time.Duration(syspar.GetMaxBlockGenerationTime()),
+		BlocksGap:           time.Second * time.Duration(syspar.GetGapsBetweenBlocks()),
+		NodesCount:          syspar.GetNumberOfNodesFromDB(transaction),
+	}
+	c, err := New(syspar.GetConsensus(), params, nodeVotes)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err, "consensus": syspar.GetConsensus()}).Error("creating consensus")
+		return nil, err
+	}
+	return c, nil
+}
+
+func verifyVote(vote *Vote) error {
+	nodePublicKeys, err := syspar.GetNodePublicKeysByPosition(vote.NodePosition, vote.BlockID)
+	if err != nil || len(nodePublicKeys) == 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "error": err, "node_position": vote.NodePosition}).Error("getting node public key")
+		return ErrUnknownValidator
+	}
+	ok, err := syspar.CheckNodeSign(vote.NodePosition, vote.BlockID, vote.ForSign(), vote.Sign)
+	if err != nil || !ok {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "block_id": vote.BlockID}).Error("checking sign of vote")
+		return ErrInvalidVote
+	}
+	return nil
+}
+
+// signVote returns the signed attestation of this node for the block
+func signVote(c Consensus, header *utils.BlockData) (*Vote, error) {
+	nodePosition, err := syspar.GetNodePositionByKeyID(conf.Config.KeyID)
+	if err != nil {
+		return nil, ErrUnknownValidator
+	}
+	vote, err := c.NewVote(header, nodePosition)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": header.BlockID}).Error("creating vote")
+		return nil, err
+	}
+
+	if vote.Sign, err = signer.Node().SignVote(vote.BlockID, vote.ForSign()); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing vote")
+		return nil, err
+	}
+	if _, err = c.AddVote(vote); err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+// VoteBlock signs the attestation of this node for the applied block and sends it to other nodes
+func VoteBlock(header *utils.BlockData) error {
+	if _, err := syspar.GetNodePositionByKeyID(conf.Config.KeyID); err != nil {
+		// we are not full node and don't vote
+		return nil
+	}
+	c, err := Build(nil)
+	if err != nil {
+		return err
+	}
+	vote, err := signVote(c, header)
+	if err != nil {
+		return err
+	}
+	if network != nil {
+		go func() {
+			if err := network.Broadcast(vote); err != nil {
+				log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "block_id": vote.BlockID}).Error("broadcasting vote")
+			}
+		}()
+	}
+	return nil
+}
+
+// Attest returns the signed attestation of this node for its block with the specified identifier
+func Attest(blockID int64) (*Vote, error) {
+	block := &model.Block{}
+	found, err := block.Get(blockID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("getting block")
+		return nil, err
+	}
+	if !found {
+		return nil, errBlockNotFound
+	}
+	c, err := Build(nil)
+	if err != nil {
+		return nil, err
+	}
+	return signVote(c, &utils.BlockData{BlockID: block.ID, Hash: block.Hash})
+}
+
+// ReceiveVote registers the attestation of other node
+func ReceiveVote(vote *Vote) error {
+	c, err := Build(nil)
+	if err != nil {
+		return err
+	}
+	if _, err = c.AddVote(vote); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "block_id": vote.BlockID, "node_position": vote.NodePosition}).Error("adding vote")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+)
+
+// RoundRobin is the consensus where the nodes generate blocks in turn in their time intervals
+type RoundRobin struct {
+	finality
+	calculator utils.BlockTimeCalculator
+}
+
+// NewRoundRobin returns the round-robin consensus which keeps the votes in the pool
+func NewRoundRobin(params Params, votes *Votes) *RoundRobin {
+	return &RoundRobin{
+		finality: finality{
+			params: params,
+			votes:  votes,
+		},
+		calculator: utils.NewBlockTimeCalculator(params.FirstBlockTime,
+			params.BlockGenerationTime,
+			params.BlocksGap,
+			params.NodesCount,
+		),
+	}
+}
+
+// SetBlocksCounter replaces the counter of generated blocks which is used to find duplicate blocks
+func (rr *RoundRobin) SetBlocksCounter(counter utils.BlocksCounterFunc) *RoundRobin {
+	rr.calculator.SetBlocksCounter(counter)
+	return rr
+}
+
+// Name returns the name of the algorithm
+func (rr *RoundRobin) Name() string {
+	return NameRoundRobin
+}
+
+// IsLeader returns true if the time belongs to the time interval of the node
+func (rr *RoundRobin) IsLeader(nodePosition int64, prev *utils.BlockData, at time.Time) (bool, error) {
+	return rr.calculator.ValidateBlock(nodePosition, at)
+}
+
+// ValidateBlock checks that the block has been generated in the time interval of the node
+func (rr *RoundRobin) ValidateBlock(header, prev *utils.BlockData) error {
+	valid, err := rr.calculator.ValidateBlock(header.NodePosition, time.Unix(header.Time, 0))
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidLeader
+	}
+	return nil
+}
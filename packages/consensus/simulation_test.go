@@ -0,0 +1,388 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simNode is the full node of the simulation with its own chain and consensus state
+type simNode struct {
+	position int64
+	cons     Consensus
+	chain    []*utils.BlockData
+	down     bool
+	rejected int
+}
+
+func (n *simNode) last() *utils.BlockData {
+	return n.chain[len(n.chain)-1]
+}
+
+func (n *simNode) countBlocks(start time.Time, duration time.Duration, nodePosition int64) (int, error) {
+	var count int
+	for _, b := range n.chain {
+		at := time.Unix(b.Time, 0)
+		if b.NodePosition == nodePosition && !at.Before(start) && at.Before(start.Add(duration)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type simMessage struct {
+	from  int64
+	to    int64
+	block *utils.BlockData
+	vote  *Vote
+}
+
+// simNetwork runs the nodes in the same process and delivers the blocks and votes between them
+type simNetwork struct {
+	nodes []*simNode
+	queue []simMessage
+	now   time.Time
+	// partition returns true if the messages between nodes are lost
+	partition func(from, to int64) bool
+}
+
+func newSimulation(t *testing.T, name string, count int64) *simNetwork {
+	params := testParams(count)
+	genesis := &utils.BlockData{BlockID: 1, Time: params.FirstBlockTime.Unix(), Hash: []byte{1}}
+	network := &simNetwork{now: params.FirstBlockTime}
+	for i := int64(0); i < count; i++ {
+		node := &simNode{position: i, chain: []*utils.BlockData{genesis}}
+		c, err := New(name, params, NewVotes())
+		require.NoError(t, err)
+		if rr, ok := c.(*RoundRobin); ok {
+			rr.SetBlocksCounter(node.countBlocks)
+		}
+		node.cons = c
+		network.nodes = append(network.nodes, node)
+	}
+	return network
+}
+
+func (sn *simNetwork) send(from int64, msg simMessage) {
+	for _, node := range sn.nodes {
+		if node.position == from || (sn.partition != nil && sn.partition(from, node.position)) {
+			continue
+		}
+		msg.from, msg.to = from, node.position
+		sn.queue = append(sn.queue, msg)
+	}
+}
+
+func (sn *simNetwork) accept(node *simNode, header *utils.BlockData, from int64) {
+	prev := node.last()
+	if header.BlockID != prev.BlockID+1 || !bytes.Equal(header.Sign, prev.Hash) {
+		if from == node.position || !sn.sync(node, sn.nodes[from]) {
+			node.rejected++
+		}
+		return
+	}
+	if err := node.cons.ValidateBlock(header, prev); err != nil {
+		node.rejected++
+		return
+	}
+	node.chain = append(node.chain, header)
+	sn.vote(node, header)
+}
+
+// sync is the fork choice of the simulation, the node switches to the longer chain of other node
+// if its blocks which are rolled back aren't final
+func (sn *simNetwork) sync(node, from *simNode) bool {
+	if len(from.chain) <= len(node.chain) {
+		return false
+	}
+	fork := 0
+	for fork < len(node.chain) && bytes.Equal(node.chain[fork].Hash, from.chain[fork].Hash) {
+		fork++
+	}
+	for _, header := range node.chain[fork:] {
+		if node.cons.IsFinal(header) {
+			return false
+		}
+	}
+	node.chain = append(node.chain[:fork:fork], from.chain[fork:]...)
+	return true
+}
+
+func (sn *simNetwork) vote(node *simNode, header *utils.BlockData) {
+	vote, err := node.cons.NewVote(header, node.position)
+	if err != nil || vote == nil {
+		return
+	}
+	if _, err = node.cons.AddVote(vote); err == nil {
+		sn.send(node.position, simMessage{vote: vote})
+	}
+}
+
+func (sn *simNetwork) deliver() {
+	for len(sn.queue) > 0 {
+		msg := sn.queue[0]
+		sn.queue = sn.queue[1:]
+		node := sn.nodes[msg.to]
+		if node.down {
+			continue
+		}
+		if msg.block != nil {
+			sn.accept(node, msg.block, msg.from)
+		} else {
+			node.cons.AddVote(msg.vote)
+		}
+	}
+}
+
+func newSimBlock(prev *utils.BlockData, nodePosition int64, at time.Time) *utils.BlockData {
+	header := &utils.BlockData{
+		BlockID:      prev.BlockID + 1,
+		Time:         at.Unix(),
+		NodePosition: nodePosition,
+		// the sign of the simulated block refers to the previous block
+		Sign: prev.Hash,
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d,%x,%d,%d", header.BlockID, prev.Hash,
+		header.Time, header.NodePosition)))
+	header.Hash = hash[:]
+	return header
+}
+
+// run generates the blocks every second of the specified duration
+func (sn *simNetwork) run(duration time.Duration) {
+	end := sn.now.Add(duration)
+	for ; sn.now.Before(end); sn.now = sn.now.Add(time.Second) {
+		at := sn.now
+		for _, node := range sn.nodes {
+			if node.down {
+				continue
+			}
+			prev := node.last()
+			if !node.cons.IsFinal(prev) {
+				// the node repeats the vote for the last block until it becomes final
+				sn.vote(node, prev)
+			}
+			ok, err := node.cons.IsLeader(node.position, prev, at)
+			if err != nil || !ok {
+				continue
+			}
+			header := newSimBlock(prev, node.position, at)
+			sn.accept(node, header, node.position)
+			sn.send(node.position, simMessage{block: header})
+		}
+		sn.deliver()
+	}
+}
+
+func (sn *simNetwork) liveNodes() (nodes []*simNode) {
+	for _, node := range sn.nodes {
+		if !node.down {
+			nodes = append(nodes, node)
+		}
+	}
+	return
+}
+
+func assertSameChains(t *testing.T, nodes []*simNode) {
+	for _, node := range nodes[1:] {
+		require.Equal(t, len(nodes[0].chain), len(node.chain), "node %d", node.position)
+		for i, header := range node.chain {
+			assert.Equal(t, nodes[0].chain[i].Hash, header.Hash, "node %d block %d", node.position, header.BlockID)
+		}
+	}
+}
+
+func leaders(chain []*utils.BlockData) map[int64]int {
+	ret := make(map[int64]int)
+	for _, header := range chain[1:] {
+		ret[header.NodePosition]++
+	}
+	return ret
+}
+
+func TestSimulationBFT(t *testing.T) {
+	sim := newSimulation(t, NameBFT, 4)
+	sim.run(time.Minute)
+
+	nodes := sim.liveNodes()
+	assertSameChains(t, nodes)
+	chain := nodes[0].chain
+	assert.True(t, len(chain) > 10, "chain length %d", len(chain))
+	assert.Len(t, leaders(chain), 4)
+	for _, node := range nodes {
+		assert.Equal(t, 0, node.rejected)
+		for _, header := range node.chain {
+			assert.True(t, node.cons.IsFinal(header), "node %d block %d", node.position, header.BlockID)
+		}
+	}
+
+	// the block of the node which is not the leader is rejected by all nodes
+	last := chain[len(chain)-1]
+	at := time.Unix(last.Time, 0).Add(time.Second)
+	leader, err := nodes[0].cons.(*BFT).Leader(last, at)
+	require.NoError(t, err)
+	intruder := (leader + 1) % 4
+	sim.send(intruder, simMessage{block: newSimBlock(last, intruder, at)})
+	sim.deliver()
+	for _, node := range nodes {
+		if node.position != intruder {
+			assert.Equal(t, 1, node.rejected)
+			assert.Equal(t, last.BlockID, node.last().BlockID)
+		}
+	}
+}
+
+func TestSimulationBFTCrashedNode(t *testing.T) {
+	sim := newSimulation(t, NameBFT, 4)
+	sim.nodes[3].down = true
+	sim.run(time.Minute)
+
+	nodes := sim.liveNodes()
+	assertSameChains(t, nodes)
+	chain := nodes[0].chain
+	assert.True(t, len(chain) > 5, "chain length %d", len(chain))
+	assert.Equal(t, 0, leaders(chain)[3])
+	for _, node := range nodes {
+		for _, header := range node.chain {
+			assert.True(t, node.cons.IsFinal(header))
+		}
+	}
+}
+
+func TestSimulationBFTNoQuorum(t *testing.T) {
+	sim := newSimulation(t, NameBFT, 4)
+	sim.nodes[2].down = true
+	sim.nodes[3].down = true
+	sim.run(time.Minute)
+
+	// the first block can't get the quorum, so the next blocks are not generated
+	for _, node := range sim.liveNodes() {
+		require.Len(t, node.chain, 2)
+		assert.False(t, node.cons.IsFinal(node.last()))
+	}
+}
+
+func TestSimulationBFTPartition(t *testing.T) {
+	sim := newSimulation(t, NameBFT, 4)
+	sim.run(20 * time.Second)
+	finalID := sim.nodes[0].last().BlockID
+
+	sim.partition = func(from, to int64) bool {
+		return (from < 2) != (to < 2)
+	}
+	sim.run(20 * time.Second)
+
+	// both parts can generate blocks, but none of them can be final
+	for _, node := range sim.nodes {
+		for _, header := range node.chain {
+			if header.BlockID > finalID {
+				assert.False(t, node.cons.IsFinal(header))
+			}
+		}
+	}
+	for _, node := range sim.nodes {
+		assert.True(t, node.last().BlockID <= finalID+1)
+	}
+}
+
+// leaderAt returns the first time from at when one of the nodes is the leader after prev
+func leaderAt(t *testing.T, bft *BFT, prev *utils.BlockData, at time.Time, nodes ...int64) (time.Time, int64) {
+	for i := 0; i < 100; i++ {
+		leader, err := bft.Leader(prev, at)
+		if err == nil {
+			for _, node := range nodes {
+				if leader == node {
+					return at, leader
+				}
+			}
+		}
+		at = at.Add(time.Second)
+	}
+	t.Fatal("leader not found")
+	return at, 0
+}
+
+func TestSimulationBFTSplitVotes(t *testing.T) {
+	sim := newSimulation(t, NameBFT, 4)
+	sim.run(20 * time.Second)
+	last := sim.nodes[0].last()
+	require.True(t, sim.nodes[0].cons.IsFinal(last))
+
+	// the leaders of two rounds generate the competing blocks, each of them is got by a half of nodes
+	bft := sim.nodes[0].cons.(*BFT)
+	atA, leaderA := leaderAt(t, bft, last, sim.now, 0, 1)
+	atB, leaderB := leaderAt(t, bft, last, atA, 2, 3)
+	blockA := newSimBlock(last, leaderA, atA)
+	blockB := newSimBlock(last, leaderB, atB)
+	for _, node := range sim.nodes {
+		if node.position < 2 {
+			sim.accept(node, blockA, leaderA)
+		} else {
+			sim.accept(node, blockB, leaderB)
+		}
+	}
+	sim.deliver()
+	for _, node := range sim.nodes {
+		assert.False(t, node.cons.IsFinal(node.last()), "node %d", node.position)
+	}
+
+	// the round of split votes is over, the nodes vote for the same block in the next round
+	sim.now = atB.Add(time.Second)
+	sim.run(30 * time.Second)
+
+	assertSameChains(t, sim.nodes)
+	chain := sim.nodes[0].chain
+	require.True(t, len(chain) > int(last.BlockID)+3, "chain length %d", len(chain))
+	winner := blockA
+	if bytes.Compare(blockB.Hash, blockA.Hash) < 0 {
+		winner = blockB
+	}
+	assert.Equal(t, winner.Hash, chain[last.BlockID].Hash)
+	for _, node := range sim.nodes {
+		for _, header := range node.chain {
+			assert.True(t, node.cons.IsFinal(header), "node %d block %d", node.position, header.BlockID)
+		}
+	}
+}
+
+func TestSimulationRoundRobin(t *testing.T) {
+	sim := newSimulation(t, NameRoundRobin, 3)
+	sim.run(time.Minute)
+
+	nodes := sim.liveNodes()
+	assertSameChains(t, nodes)
+	chain := nodes[0].chain
+	assert.True(t, len(chain) > 10, "chain length %d", len(chain))
+	assert.Len(t, leaders(chain), 3)
+	for i, header := range chain[1:] {
+		assert.Equal(t, (chain[i].NodePosition+1)%3, header.NodePosition)
+	}
+	for _, node := range nodes {
+		for _, header := range node.chain {
+			assert.True(t, node.cons.IsFinal(header), "node %d block %d", node.position, header.BlockID)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"bytes"
+	"sync"
+)
+
+// votesDepth is the number of blocks below the last final block for which the votes are kept
+const votesDepth = 1000
+
+// Store keeps the attestations of final blocks
+type Store interface {
+	// SaveFinal saves the attestations which have made the block final
+	// and returns false if there is no such block yet
+	SaveFinal(blockID int64, hash []byte, votes []*Vote) (bool, error)
+	// IsFinal returns true if the block has been saved as final
+	IsFinal(blockID int64, hash []byte) (bool, error)
+}
+
+type blockVotes struct {
+	// rounds contains the votes by the rounds and the positions of the nodes
+	rounds map[int64]map[int64]*Vote
+	final  []byte
+	saved  bool
+}
+
+// tally is the count of the votes in the round
+type tally struct {
+	// counts contains the count of votes by the hashes of blocks
+	counts map[string]int64
+	// voters is the count of nodes which have voted in the round
+	voters int64
+}
+
+// Votes is the pool of the finality votes received by the node
+type Votes struct {
+	mutex       sync.RWMutex
+	blocks      map[int64]*blockVotes
+	lastFinal   int64
+	prunedBelow int64
+	verify      func(vote *Vote) error
+	store       Store
+}
+
+// NewVotes returns the empty pool of votes
+func NewVotes() *Votes {
+	return &Votes{
+		blocks: make(map[int64]*blockVotes),
+	}
+}
+
+// SetVerifier sets the function which checks the sign of the vote
+func (v *Votes) SetVerifier(verify func(vote *Vote) error) *Votes {
+	v.verify = verify
+	return v
+}
+
+// SetStore sets the storage of the attestations of final blocks
+func (v *Votes) SetStore(store Store) *Votes {
+	v.store = store
+	return v
+}
+
+// Voted returns the hash of the block which the node has voted for at the height in the round
+func (v *Votes) Voted(blockID, round, nodePosition int64) []byte {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	if item, ok := v.blocks[blockID]; ok {
+		if vote, ok := item.rounds[round][nodePosition]; ok {
+			return vote.Hash
+		}
+	}
+	return nil
+}
+
+// tally returns the count of votes at the height in the round
+func (v *Votes) tally(blockID, round int64) tally {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	t := tally{counts: make(map[string]int64)}
+	if item, ok := v.blocks[blockID]; ok {
+		for _, vote := range item.rounds[round] {
+			t.counts[string(vote.Hash)]++
+			t.voters++
+		}
+	}
+	return t
+}
+
+// add registers the vote and returns the count of votes for the block in the round of the vote
+func (v *Votes) add(vote *Vote) (int64, error) {
+	if v.verify != nil {
+		if err := v.verify(vote); err != nil {
+			return 0, err
+		}
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if vote.BlockID < v.prunedBelow {
+		return 0, nil
+	}
+	item, ok := v.blocks[vote.BlockID]
+	if !ok {
+		item = &blockVotes{rounds: make(map[int64]map[int64]*Vote)}
+		v.blocks[vote.BlockID] = item
+	}
+	voters, ok := item.rounds[vote.Round]
+	if !ok {
+		voters = make(map[int64]*Vote)
+		item.rounds[vote.Round] = voters
+	}
+	if prev, ok := voters[vote.NodePosition]; ok {
+		if !bytes.Equal(prev.Hash, vote.Hash) {
+			return 0, ErrDoubleVote
+		}
+	} else {
+		voters[vote.NodePosition] = vote
+	}
+	var count int64
+	for _, voter := range voters {
+		if bytes.Equal(voter.Hash, vote.Hash) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// setFinal marks the block as final, saves its attestations of the round and removes old votes
+func (v *Votes) setFinal(blockID, round int64, hash []byte) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	item, ok := v.blocks[blockID]
+	if !ok {
+		return nil
+	}
+	item.final = hash
+	if v.store != nil && !item.saved {
+		votes := make([]*Vote, 0, len(item.rounds[round]))
+		for _, vote := range item.rounds[round] {
+			if bytes.Equal(vote.Hash, hash) {
+				votes = append(votes, vote)
+			}
+		}
+		saved, err := v.store.SaveFinal(blockID, hash, votes)
+		if err != nil {
+			return err
+		}
+		item.saved = saved
+	}
+	if blockID <= v.lastFinal {
+		return nil
+	}
+	v.lastFinal = blockID
+	if blockID-votesDepth > v.prunedBelow {
+		v.prunedBelow = blockID - votesDepth
+		for id := range v.blocks {
+			if id < v.prunedBelow {
+				delete(v.blocks, id)
+			}
+		}
+	}
+	return nil
+}
+
+// IsFinal returns true if the block has got the quorum of votes
+func (v *Votes) IsFinal(blockID int64, hash []byte) bool {
+	v.mutex.RLock()
+	item, ok := v.blocks[blockID]
+	final := ok && item.final != nil && bytes.Equal(item.final, hash)
+	pruned := blockID < v.prunedBelow
+	v.mutex.RUnlock()
+	if final {
+		return true
+	}
+	if v.store != nil {
+		saved, err := v.store.IsFinal(blockID, hash)
+		return err == nil && saved
+	}
+	// the blocks below the kept votes are the ancestors of final blocks
+	return pruned
+}
+
+// LastFinal returns the identifier of the last final block
+func (v *Votes) LastFinal() int64 {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.lastFinal
+}
@@ -0,0 +1,250 @@
+package crypto
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+)
+
+// KeyType is the type of keys and signatures
+type KeyType int64
+
+const (
+	// KeyECDSA is ECDSA key over P-256 curve, it's default key type
+	KeyECDSA KeyType = iota
+	// KeyEd25519 is Ed25519 key
+	KeyEd25519
+	// KeySecp256k1 is ECDSA key over secp256k1 curve, its scheme must be registered with RegisterScheme
+	KeySecp256k1
+)
+
+var keyTypeNames = map[KeyType]string{
+	KeyECDSA:     `ecdsa`,
+	KeyEd25519:   `ed25519`,
+	KeySecp256k1: `secp256k1`,
+}
+
+var (
+	// ErrUnknownKeyType is returned if the scheme of the key type isn't registered
+	ErrUnknownKeyType = errors.New("Unknown key type")
+)
+
+// SignScheme is the scheme of signatures of the key type
+type SignScheme interface {
+	// Sign signs the data with the private key
+	Sign(privateKey []byte, data string) ([]byte, error)
+	// Verify checks the signature of the data
+	Verify(public []byte, data string, signature []byte) (bool, error)
+	// PublicKey returns the public key for the private key
+	PublicKey(privateKey []byte) ([]byte, error)
+	// GenerateKey generates a random pair of private and public keys
+	GenerateKey() (private []byte, public []byte, err error)
+	// PublicKeySize returns the length of the public key
+	PublicKeySize() int
+}
+
+var (
+	schemesMutex sync.RWMutex
+	schemes      = map[KeyType]SignScheme{
+		KeyECDSA:   ecdsaScheme{},
+		KeyEd25519: ed25519Scheme{},
+	}
+)
+
+// RegisterScheme registers the scheme of signatures for the key type
+func RegisterScheme(keyType KeyType, scheme SignScheme) {
+	schemesMutex.Lock()
+	defer schemesMutex.Unlock()
+	schemes[keyType] = scheme
+}
+
+// ParseKeyType returns the key type by the name
+func ParseKeyType(name string) (KeyType, error) {
+	if len(name) == 0 {
+		return KeyECDSA, nil
+	}
+	for keyType, keyName := range keyTypeNames {
+		if keyName == name {
+			return keyType, nil
+		}
+	}
+	return 0, ErrUnknownKeyType
+}
+
+// String returns the name of the key type
+func (t KeyType) String() string {
+	if name, ok := keyTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf(`keytype%d`, int64(t))
+}
+
+// Scheme returns the scheme of signatures of the key type
+func (t KeyType) Scheme() (SignScheme, error) {
+	schemesMutex.RLock()
+	defer schemesMutex.RUnlock()
+	scheme, ok := schemes[t]
+	if !ok {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "key_type": int64(t)}).Error(ErrUnknownKeyType.Error())
+		return nil, ErrUnknownKeyType
+	}
+	return scheme, nil
+}
+
+// Sign signs the data with the hex private key of the key type
+func (t KeyType) Sign(privateKey string, data string) ([]byte, error) {
+	scheme, err := t.Scheme()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug(ErrSigningEmpty.Error())
+	}
+	key, err := hex.DecodeString(privateKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
+		return nil, err
+	}
+	return scheme.Sign(key, data)
+}
+
+// CheckSign checks the signature of the data with the public key of the key type
+func (t KeyType) CheckSign(public []byte, data string, signature []byte) (bool, error) {
+	scheme, err := t.Scheme()
+	if err != nil {
+		return false, err
+	}
+	if len(public) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Debug(ErrCheckingSignEmpty.Error())
+	}
+	return scheme.Verify(public, data, signature)
+}
+
+// PrivateToPublic returns the public key for the private key of the key type
+func (t KeyType) PrivateToPublic(key []byte) ([]byte, error) {
+	scheme, err := t.Scheme()
+	if err != nil {
+		return nil, err
+	}
+	return scheme.PublicKey(key)
+}
+
+// GenerateKeys generates a random pair of private and public binary keys of the key type
+func (t KeyType) GenerateKeys() ([]byte, []byte, error) {
+	scheme, err := t.Scheme()
+	if err != nil {
+		return nil, nil, err
+	}
+	return scheme.GenerateKey()
+}
+
+// PublicKeySize returns the length of the public key of the key type
+func (t KeyType) PublicKeySize() int {
+	scheme, err := t.Scheme()
+	if err != nil {
+		return 0
+	}
+	return scheme.PublicKeySize()
+}
+
+// Address returns the address of the public key of the key type.
+// The addresses of ECDSA keys don't depend on the key type for the compatibility
+func (t KeyType) Address(pubKey []byte) int64 {
+	if t == KeyECDSA {
+		return Address(pubKey)
+	}
+	return Address(append([]byte{byte(t)}, pubKey...))
+}
+
+type ecdsaScheme struct{}
+
+func (ecdsaScheme) Sign(privateKey []byte, data string) ([]byte, error) {
+	return signECDSA(hex.EncodeToString(privateKey), data)
+}
+
+func (ecdsaScheme) Verify(public []byte, data string, signature []byte) (bool, error) {
+	return checkECDSA(public, data, signature)
+}
+
+func (ecdsaScheme) PublicKey(privateKey []byte) ([]byte, error) {
+	return PrivateToPublic(privateKey)
+}
+
+func (ecdsaScheme) GenerateKey() ([]byte, []byte, error) {
+	return GenBytesKeys()
+}
+
+func (ecdsaScheme) PublicKeySize() int {
+	return consts.PubkeySizeLength
+}
+
+// ed25519SeedSize is the size of the seed of Ed25519 key
+const ed25519SeedSize = 32
+
+// ed25519Scheme uses the seed of Ed25519 key as the private key
+type ed25519Scheme struct{}
+
+func (ed25519Scheme) privateKey(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519SeedSize {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "size": len(seed)}).Error(ErrIncorrectPrivKeyLength.Error())
+		return nil, ErrIncorrectPrivKeyLength
+	}
+	// the key is generated from the seed which is read as the entropy
+	_, key, err := ed25519.GenerateKey(bytes.NewReader(seed))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating key from seed")
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s ed25519Scheme) Sign(privateKey []byte, data string) ([]byte, error) {
+	key, err := s.privateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key, []byte(data)), nil
+}
+
+func (ed25519Scheme) Verify(public []byte, data string, signature []byte) (bool, error) {
+	if len(data) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError}).Error("data is empty")
+		return false, fmt.Errorf("invalid parameters len(data) == 0")
+	}
+	if len(public) != ed25519.PublicKeySize {
+		log.WithFields(log.Fields{"size": len(public), "size_match": ed25519.PublicKeySize, "type": consts.SizeDoesNotMatch}).Error("invalid public key")
+		return false, ErrIncorrectPubKeyLength
+	}
+	if !ed25519.Verify(ed25519.PublicKey(public), []byte(data), signature) {
+		return false, ErrIncorrectSign
+	}
+	return true, nil
+}
+
+func (s ed25519Scheme) PublicKey(privateKey []byte) ([]byte, error) {
+	key, err := s.privateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(key.Public().(ed25519.PublicKey)), nil
+}
+
+func (ed25519Scheme) GenerateKey() ([]byte, []byte, error) {
+	public, private, err := ed25519.GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(private[:ed25519SeedSize]), []byte(public), nil
+}
+
+func (ed25519Scheme) PublicKeySize() int {
+	return ed25519.PublicKeySize
+}
@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEd25519(t *testing.T) {
+	priv, pub, err := KeyEd25519.GenerateKeys()
+	require.NoError(t, err)
+	assert.Len(t, pub, KeyEd25519.PublicKeySize())
+
+	public, err := KeyEd25519.PrivateToPublic(priv)
+	require.NoError(t, err)
+	assert.Equal(t, pub, public)
+
+	sign, err := KeyEd25519.Sign(hex.EncodeToString(priv), "data")
+	require.NoError(t, err)
+
+	ok, err := KeyEd25519.CheckSign(pub, "data", sign)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = KeyEd25519.CheckSign(pub, "other data", sign)
+	assert.Equal(t, ErrIncorrectSign, err)
+	assert.False(t, ok)
+
+	assert.NotEqual(t, Address(pub), KeyEd25519.Address(pub))
+	assert.Equal(t, Address(pub), KeyECDSA.Address(pub))
+}
+
+func TestEd25519Vector(t *testing.T) {
+	// test vector 2 of RFC 8032
+	priv, _ := hex.DecodeString("4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb")
+	public, err := KeyEd25519.PrivateToPublic(priv)
+	require.NoError(t, err)
+	assert.Equal(t, "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c", hex.EncodeToString(public))
+
+	sign, err := KeyEd25519.Sign(hex.EncodeToString(priv), "\x72")
+	require.NoError(t, err)
+	assert.Equal(t, "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da"+
+		"085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00", hex.EncodeToString(sign))
+}
+
+func TestKeyType(t *testing.T) {
+	for _, keyType := range []KeyType{KeyECDSA, KeyEd25519, KeySecp256k1} {
+		parsed, err := ParseKeyType(keyType.String())
+		require.NoError(t, err)
+		assert.Equal(t, keyType, parsed)
+	}
+	_, err := ParseKeyType("rsa")
+	assert.Equal(t, ErrUnknownKeyType, err)
+
+	_, _, err = KeySecp256k1.GenerateKeys()
+	assert.Equal(t, ErrUnknownKeyType, err)
+}
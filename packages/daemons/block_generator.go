@@ -24,10 +24,14 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/block"
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/mempool"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/notificator"
 	"github.com/GenesisKernel/go-genesis/packages/service"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/transaction"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 
@@ -61,13 +65,37 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	blockTimeCalculator, err := utils.BuildBlockTimeCalculator(nil)
+	prevBlock := &model.InfoBlock{}
+	_, err = prevBlock.Get()
 	if err != nil {
-		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("building block time calculator")
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
 		return err
 	}
+	prevHeader := &utils.BlockData{
+		BlockID:      prevBlock.BlockID,
+		Time:         prevBlock.Time,
+		EcosystemID:  prevBlock.EcosystemID,
+		KeyID:        prevBlock.KeyID,
+		NodePosition: converter.StrToInt64(prevBlock.NodePosition),
+		Hash:         prevBlock.Hash,
+	}
+
+	cons, err := consensus.Build(nil)
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("building consensus")
+		return err
+	}
+
+	// the node which is behind other hosts is syncing and doesn't vote for its old blocks
+	best, behind := chainTips.Best()
+	behind = behind && best.BlockID > prevHeader.BlockID
+	if !behind && !cons.IsFinal(prevHeader) {
+		// repeat the vote for the last block at the tip until it becomes final,
+		// the node votes in the next round if the votes for the block are split
+		consensus.VoteBlock(prevHeader)
+	}
 
-	timeToGenerate, err := blockTimeCalculator.SetClock(&utils.ClockWrapper{}).TimeToGenerate(nodePosition)
+	timeToGenerate, err := cons.IsLeader(nodePosition, prevHeader, time.Now())
 	if err != nil {
 		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("calculating block time")
 		return err
@@ -78,25 +106,16 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return nil
 	}
 
-	prevBlock := &model.InfoBlock{}
-	_, err = prevBlock.Get()
+	NodePublicKey, err := signer.Node().PublicKey()
 	if err != nil {
-		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
-		return err
-	}
-
-	NodePrivateKey, NodePublicKey, err := utils.GetNodeKeys()
-	if err != nil || len(NodePrivateKey) < 1 {
-		if err == nil {
-			d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		}
+		d.logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
 		return err
 	}
 
 	dtx := DelayedTx{
-		privateKey: NodePrivateKey,
-		publicKey:  NodePublicKey,
-		logger:     d.logger,
+		signer:    signer.Node(),
+		publicKey: NodePublicKey,
+		logger:    d.logger,
 	}
 	dtx.RunForBlockID(prevBlock.BlockID + 1)
 
@@ -119,7 +138,7 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		Version:      consts.BLOCK_VERSION,
 	}
 
-	timeToGenerate, err = blockTimeCalculator.SetClock(&utils.ClockWrapper{}).TimeToGenerate(nodePosition)
+	timeToGenerate, err = cons.IsLeader(nodePosition, prevHeader, time.Now())
 	if err != nil {
 		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("calculating block time")
 		return err
@@ -130,7 +149,7 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return nil
 	}
 
-	blockBin, err := generateNextBlock(header, trs, NodePrivateKey, prevBlock.Hash)
+	blockBin, err := generateNextBlock(header, trs, prevBlock.Hash)
 	if err != nil {
 		return err
 	}
@@ -145,13 +164,14 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 	return nil
 }
 
-func generateNextBlock(blockHeader *utils.BlockData, trs []*model.Transaction, key string, prevBlockHash []byte) ([]byte, error) {
+// generateNextBlock returns the unsigned block, it's signed after playing the transactions
+func generateNextBlock(blockHeader *utils.BlockData, trs []*model.Transaction, prevBlockHash []byte) ([]byte, error) {
 	trData := make([][]byte, 0, len(trs))
 	for _, tr := range trs {
 		trData = append(trData, tr.Data)
 	}
 
-	return block.MarshallBlock(blockHeader, trData, prevBlockHash, key)
+	return block.MarshallBlock(blockHeader, trData, prevBlockHash, nil)
 }
 
 func processTransactions(logger *log.Entry) ([]*model.Transaction, error) {
@@ -163,11 +183,11 @@ func processTransactions(logger *log.Entry) ([]*model.Transaction, error) {
 		return nil, err
 	}
 
-	trs, err := model.GetAllUnusedTransactions(syspar.GetMaxTxCount())
-	if err != nil {
-		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all unused transactions")
+	if err = transaction.SyncMempool(); err != nil {
 		return nil, err
 	}
+	pool := mempool.GetPool()
+	trs := pool.Select(syspar.GetMaxTxCount(), transaction.NextNonce)
 
 	limits := block.NewLimits(nil)
 	// Checks preprocessing count limits
@@ -179,11 +199,13 @@ func processTransactions(logger *log.Entry) ([]*model.Transaction, error) {
 			if p != nil {
 				transaction.MarkTransactionBad(p.DbTransaction, p.TxHash, err.Error())
 			}
+			pool.Remove(txItem.Hash)
 			continue
 		}
 
 		if err := p.Check(time.Now().Unix(), false); err != nil {
 			transaction.MarkTransactionBad(p.DbTransaction, p.TxHash, err.Error())
+			pool.Remove(txItem.Hash)
 			continue
 		}
 
@@ -197,11 +219,12 @@ func processTransactions(logger *log.Entry) ([]*model.Transaction, error) {
 					model.IncrementTxAttemptCount(nil, p.TxHash)
 				} else {
 					transaction.MarkTransactionBad(p.DbTransaction, p.TxHash, err.Error())
+					pool.Remove(txItem.Hash)
 				}
 				continue
 			}
 		}
-		txList = append(txList, trs[i])
+		txList = append(txList, &model.Transaction{Hash: txItem.Hash, Data: txItem.Data})
 	}
 
 	return txList, nil
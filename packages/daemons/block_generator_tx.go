@@ -10,6 +10,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 
@@ -24,9 +25,9 @@ const (
 
 // DelayedTx represents struct which works with delayed contracts
 type DelayedTx struct {
-	logger     *log.Entry
-	privateKey string
-	publicKey  string
+	logger    *log.Entry
+	signer    signer.Signer
+	publicKey string
 }
 
 // RunForBlockID creates the transactions that need to be run for blockID
@@ -64,10 +65,7 @@ func (dtx *DelayedTx) createTx(delayedContactID, keyID int64) error {
 		Data:     params,
 	}
 
-	signature, err := crypto.Sign(
-		dtx.privateKey,
-		fmt.Sprintf("%s,%d", smartTx.ForSign(), delayedContactID),
-	)
+	signature, err := dtx.signer.Sign(fmt.Sprintf("%s,%d", smartTx.ForSign(), delayedContactID))
 	if err != nil {
 		dtx.logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing by node private key")
 		return err
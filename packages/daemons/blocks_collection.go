@@ -26,9 +26,11 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/block"
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/forkchoice"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/rollback"
 	"github.com/GenesisKernel/go-genesis/packages/service"
@@ -42,6 +44,9 @@ import (
 // ErrNodesUnavailable is returned when all nodes is unavailable
 var ErrNodesUnavailable = errors.New("All nodes unavailable")
 
+// chainTips tracks the tips of the chains of hosts
+var chainTips = forkchoice.NewTips()
+
 // BlocksCollection collects and parses blocks
 func BlocksCollection(ctx context.Context, d *daemon) error {
 	if ctx.Err() != nil {
@@ -82,8 +87,8 @@ func blocksCollection(ctx context.Context, d *daemon) (err error) {
 		maxBlockID       int64
 	)
 	if len(hosts) > 0 {
-		// get a host with the biggest block id from system parameters
-		host, maxBlockID, err = utils.ChooseBestHost(ctx, hosts, d.logger)
+		// get a host with the heaviest chain from system parameters
+		host, maxBlockID, err = chooseBestTip(ctx, hosts, d.logger)
 		if err != nil {
 			if err == utils.ErrNodesUnavailable {
 				chooseFromConfig = true
@@ -96,11 +101,11 @@ func blocksCollection(ctx context.Context, d *daemon) (err error) {
 	}
 
 	if chooseFromConfig {
-		// get a host with the biggest block id from config
-		log.Debug("Getting a host with biggest block from config")
+		// get a host with the heaviest chain from config
+		log.Debug("Getting a host with heaviest chain from config")
 		hosts = conf.GetNodesAddr()
 		if len(hosts) > 0 {
-			host, maxBlockID, err = utils.ChooseBestHost(ctx, hosts, d.logger)
+			host, maxBlockID, err = chooseBestTip(ctx, hosts, d.logger)
 			if err != nil {
 				return err
 			}
@@ -133,6 +138,18 @@ func blocksCollection(ctx context.Context, d *daemon) (err error) {
 	return UpdateChain(ctx, d, host, maxBlockID)
 }
 
+// chooseBestTip updates the tips of hosts and returns the host with the heaviest chain
+func chooseBestTip(ctx context.Context, hosts []string, logger *log.Entry) (string, int64, error) {
+	blockIDs, err := utils.GetHostsBlockIDs(ctx, hosts, logger)
+	if err != nil {
+		return "", 0, err
+	}
+
+	chainTips.Reset(blockIDs)
+	best, _ := chainTips.Best()
+	return best.Host, best.BlockID, nil
+}
+
 // UpdateChain load from host all blocks from our last block to maxBlockID
 func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64) error {
 
@@ -170,7 +187,10 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 				err := GetBlocks(b.Header.BlockID-1, host)
 				if err != nil {
 					d.logger.WithFields(log.Fields{"error": err, "type": consts.ParserError}).Error("processing block")
-					banNode(host, b, err)
+					// the refused reorg doesn't mean that the host is malicious
+					if err != forkchoice.ErrNotHeavier && err != forkchoice.ErrReorgTooDeep {
+						banNode(host, b, err)
+					}
 					return err
 				}
 			}
@@ -188,6 +208,11 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 				banNode(host, b, err)
 				return err
 			}
+			if b.Header.BlockID == maxBlockID {
+				chainTips.Update(host, b.Header.BlockID, b.Header.Hash)
+				// only the live block at the tip is voted, not the old blocks which are synced
+				consensus.VoteBlock(&b.Header)
+			}
 		}
 		return nil
 	}
@@ -279,6 +304,7 @@ func banNode(host string, block *block.Block, err error) {
 	}
 
 	log.WithFields(log.Fields{"reason": reason, "host": host, "block_id": blockId, "block_time": blockTime}).Debug("ban node")
+	chainTips.Remove(host)
 
 	n, err := syspar.GetNodeByHost(host)
 	if err != nil {
@@ -308,10 +334,42 @@ func filterBannedHosts(hosts []string) ([]string, error) {
 	return goodHosts, nil
 }
 
-// GetBlocks is returning blocks
+// GetBlocks switches our chain to the chain of the host if the fork choice allows it.
+// Our blocks from blockID down to the fork point are replaced with the blocks of the host atomically
 func GetBlocks(blockID int64, host string) error {
-	blocks, err := getBlocks(blockID, host)
+	infoBlock := &model.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("getting info block")
+		return utils.ErrInfo(err)
+	}
+	lastFinal := &model.Block{}
+	if _, err := lastFinal.GetLastFinal(); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("getting last final block")
+		return utils.ErrInfo(err)
+	}
+
+	local := forkchoice.Tip{BlockID: infoBlock.BlockID, Hash: infoBlock.Hash}
+	candidate, ok := chainTips.Get(host)
+	if !ok || candidate.BlockID <= blockID {
+		// the host has the block next to blockID at least
+		candidate = forkchoice.Tip{Host: host, BlockID: blockID + 1}
+	}
+	choice := &forkchoice.Choice{MaxDepth: maxReorgDepth()}
+
+	blocks, err := getBlocks(blockID, host, choice.MaxDepth)
 	if err != nil {
+		if err == forkchoice.ErrReorgTooDeep {
+			recordReorg(&forkchoice.Reorg{Old: local, New: candidate}, err)
+		}
+		return err
+	}
+
+	reorg, err := choice.Plan(local, candidate, blocks[len(blocks)-1].Header.BlockID-1, lastFinal.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.BlockError, "host": host}).Error("choosing fork")
+		if reorg != nil {
+			recordReorg(reorg, err)
+		}
 		return err
 	}
 
@@ -325,32 +383,85 @@ func GetBlocks(blockID int64, host string) error {
 		return utils.ErrInfo(err)
 	}
 
-	// get starting blockID from slice of blocks
-	if len(blocks) > 0 {
-		blockID = blocks[len(blocks)-1].Header.BlockID
+	err = applyReorg(reorg, blocks)
+	recordReorg(reorg, err)
+	return err
+}
+
+// maxReorgDepth returns the max count of our blocks which can be rolled back on fork
+func maxReorgDepth() int64 {
+	if conf.Config.MaxReorgDepth > 0 {
+		return conf.Config.MaxReorgDepth
 	}
+	return syspar.GetRbBlocks1()
+}
 
-	// we have the slice of blocks for applying
-	// first of all we should rollback old blocks
-	block := &model.Block{}
-	myRollbackBlocks, err := block.GetBlocksFrom(blockID-1, "desc", 0)
+// applyReorg rolls back our blocks above the fork point and plays the new blocks in one db transaction
+func applyReorg(reorg *forkchoice.Reorg, blocks []*block.Block) error {
+	bl := &model.Block{}
+	myRollbackBlocks, err := bl.GetBlocksFrom(reorg.ForkBlockID, "desc", 0)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("getting rollback blocks from blockID")
 		return utils.ErrInfo(err)
 	}
-	for _, block := range myRollbackBlocks {
-		err := rollback.RollbackBlock(block.Data, false)
-		if err != nil {
-			return utils.ErrInfo(err)
+
+	dbTransaction, err := model.StartTransaction()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("starting transaction")
+		return utils.ErrInfo(err)
+	}
+
+	for _, bl := range myRollbackBlocks {
+		if err = rollback.RollbackBlockTx(dbTransaction, bl.Data, false); err != nil {
+			break
 		}
 	}
+	if err == nil {
+		err = processBlocks(dbTransaction, blocks)
+	}
+	if err == nil {
+		err = dbTransaction.Commit()
+	} else {
+		dbTransaction.Rollback()
+	}
 
-	return processBlocks(blocks)
+	if err != nil {
+		// the system parameters could be changed by the rolled back blocks
+		if err := syspar.SysUpdate(nil); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+		}
+		return utils.ErrInfo(err)
+	}
+	// the event records the last replaced block
+	reorg.New.BlockID, reorg.New.Hash = blocks[0].Header.BlockID, blocks[0].Header.Hash
+	return nil
 }
 
-func getBlocks(blockID int64, host string) ([]*block.Block, error) {
-	rollback := syspar.GetRbBlocks1()
+// recordReorg saves the reorg for the monitoring
+func recordReorg(reorg *forkchoice.Reorg, reorgErr error) {
+	r := &model.Reorg{
+		Time:        time.Now().Unix(),
+		Host:        reorg.New.Host,
+		ForkBlockID: reorg.ForkBlockID,
+		OldBlockID:  reorg.Old.BlockID,
+		OldHash:     reorg.Old.Hash,
+		NewBlockID:  reorg.New.BlockID,
+		NewHash:     reorg.New.Hash,
+	}
+	if reorg.ForkBlockID > 0 {
+		r.Depth = reorg.Depth()
+	}
+	if reorgErr != nil {
+		r.Error = reorgErr.Error()
+	}
+	if err := r.Create(nil); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving reorg")
+	}
+}
 
+// getBlocks loads the blocks of the host from blockID down to the fork point,
+// no more than maxDepth blocks are loaded
+func getBlocks(blockID int64, host string, maxDepth int64) ([]*block.Block, error) {
 	badBlocks := make(map[int64]string)
 
 	blocks := make([]*block.Block, 0)
@@ -368,8 +479,9 @@ func getBlocks(blockID int64, host string) ([]*block.Block, error) {
 		}
 
 		// if the limit of blocks received from the node was exaggerated
-		if count > int64(rollback) {
-			break
+		if maxDepth > 0 && count >= maxDepth {
+			log.WithFields(log.Fields{"block_id": blockID, "max_depth": maxDepth, "type": consts.BlockError}).Error("fork point is not found")
+			return nil, forkchoice.ErrReorgTooDeep
 		}
 
 		block, err := block.ProcessBlockWherePrevFromBlockchainTable(binaryBlock, true)
@@ -388,9 +500,8 @@ func getBlocks(blockID int64, host string) ([]*block.Block, error) {
 
 		// TODO: add checking for MAX_BLOCK_SIZE
 
-		// the public key of the one who has generated this block
-		nodePublicKey, err := syspar.GetNodePublicKeyByPosition(block.Header.NodePosition)
-		if err != nil {
+		// the node who has generated this block
+		if _, err := syspar.GetNodeByPosition(block.Header.NodePosition); err != nil {
 			log.WithFields(log.Fields{"header_block_id": block.Header.BlockID, "block_id": blockID, "type": consts.InvalidObject}).Error("block ids does not match")
 			return nil, utils.ErrInfo(err)
 		}
@@ -407,23 +518,18 @@ func getBlocks(blockID int64, host string) ([]*block.Block, error) {
 		blockID--
 		count++
 
-		// check the signature
-		_, okSignErr := utils.CheckSign([][]byte{nodePublicKey}, forSign, block.Header.Sign, true)
-		if okSignErr == nil {
-			break
+		// check the signature, the block signed with our previous hash is next to the fork point
+		if ok, _ := syspar.CheckNodeSign(block.Header.NodePosition, block.Header.BlockID, forSign, block.Header.Sign); ok {
+			return blocks, nil
 		}
 	}
 
-	return blocks, nil
+	log.WithFields(log.Fields{"block_id": blockID, "type": consts.BlockError}).Error("fork point is not found")
+	return nil, forkchoice.ErrInvalidFork
 }
 
-func processBlocks(blocks []*block.Block) error {
-	dbTransaction, err := model.StartTransaction()
-	if err != nil {
-		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("starting transaction")
-		return utils.ErrInfo(err)
-	}
-
+// processBlocks plays the blocks and replaces our blocks with them within dbTransaction
+func processBlocks(dbTransaction *model.DbTransaction, blocks []*block.Block) error {
 	// go through new blocks from the smallest block_id to the largest block_id
 	prevBlocks := make(map[int64]*block.Block, 0)
 
@@ -446,13 +552,15 @@ func processBlocks(blocks []*block.Block) error {
 		}
 		b.Header.Hash = hash
 
+		// the transactions are checked against the state of the rolled back chain
+		for _, t := range b.Transactions {
+			t.DbTransaction = dbTransaction
+		}
 		if err := b.Check(); err != nil {
-			dbTransaction.Rollback()
 			return err
 		}
 
 		if err := b.Play(dbTransaction); err != nil {
-			dbTransaction.Rollback()
 			return utils.ErrInfo(err)
 		}
 		prevBlocks[b.Header.BlockID] = b
@@ -461,7 +569,6 @@ func processBlocks(blocks []*block.Block) error {
 		if i == 0 {
 			err := block.UpdBlockInfo(dbTransaction, b)
 			if err != nil {
-				dbTransaction.Rollback()
 				return utils.ErrInfo(err)
 			}
 		}
@@ -478,17 +585,14 @@ func processBlocks(blocks []*block.Block) error {
 		b := blocks[i]
 		// Delete old blocks from blockchain
 		bl := &model.Block{}
-		err = bl.DeleteById(dbTransaction, b.Header.BlockID)
-		if err != nil {
-			dbTransaction.Rollback()
+		if err := bl.DeleteById(dbTransaction, b.Header.BlockID); err != nil {
 			return err
 		}
 		// insert new blocks into blockchain
 		if err := block.InsertIntoBlockchain(dbTransaction, b); err != nil {
-			dbTransaction.Rollback()
 			return err
 		}
 	}
 
-	return dbTransaction.Commit()
+	return nil
 }
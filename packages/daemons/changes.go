@@ -0,0 +1,44 @@
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/cdc"
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var changesPublisher *cdc.Publisher
+
+// ChangesPublisher is sending captured changes of tables to the sink
+func ChangesPublisher(ctx context.Context, d *daemon) error {
+	cfg := conf.Config.CDC
+	if !cfg.Enabled || len(cfg.Sink) == 0 {
+		d.sleepTime = time.Hour
+		return nil
+	}
+	if changesPublisher == nil {
+		publisher, err := cdc.NewPublisher(cfg)
+		if err != nil {
+			d.logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "sink": cfg.Sink}).Error("creating changes publisher")
+			d.sleepTime = time.Minute
+			return err
+		}
+		changesPublisher = publisher
+	}
+	count, err := changesPublisher.Publish()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "sink": cfg.Sink}).Error("publishing changes")
+		d.sleepTime = 10 * time.Second
+		return err
+	}
+	if count > 0 {
+		d.sleepTime = 100 * time.Millisecond
+	} else {
+		d.sleepTime = time.Second
+	}
+	return nil
+}
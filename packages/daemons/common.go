@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/statsd"
@@ -51,6 +52,7 @@ var daemonsList = map[string]func(context.Context, *daemon) error{
 	"Confirmations":     Confirmations,
 	"Notificator":       Notificate,
 	"Scheduler":         Scheduler,
+	"ChangesPublisher":  ChangesPublisher,
 }
 
 var serverList = []string{
@@ -62,6 +64,7 @@ var serverList = []string{
 	"Confirmations",
 	"Notificator",
 	"Scheduler",
+	"ChangesPublisher",
 }
 
 var rollbackList = []string{
@@ -126,6 +129,8 @@ func StartDaemons() {
 		}
 	}()
 
+	consensus.SetNetwork(&votesNetwork{})
+
 	ctx, cancel := context.WithCancel(context.Background())
 	utils.CancelFunc = cancel
 	utils.ReturnCh = make(chan string)
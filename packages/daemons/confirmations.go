@@ -17,6 +17,7 @@
 package daemons
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
@@ -35,7 +37,7 @@ import (
 var tick int
 
 // Confirmations gets and checks blocks from nodes
-// Getting amount of nodes, which has the same hash as we do
+// Collects the signed finality attestations of nodes, which has the same hash as we do
 func Confirmations(ctx context.Context, d *daemon) error {
 
 	// the first 2 minutes we sleep for 10 sec for blocks to be collected
@@ -48,15 +50,15 @@ func Confirmations(ctx context.Context, d *daemon) error {
 
 	var startBlockID int64
 
-	// check last blocks, but not more than 5
-	confirmations := &model.Confirmation{}
-	_, err := confirmations.GetGoodBlock(consts.MIN_CONFIRMED_NODES)
+	// check last blocks after the last final block, but not more than 5
+	finalBlock := &model.Block{}
+	_, err := finalBlock.GetLastFinal()
 	if err != nil {
-		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting good block")
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last final block")
 		return err
 	}
 
-	ConfirmedBlockID := confirmations.BlockID
+	ConfirmedBlockID := finalBlock.ID
 	infoBlock := &model.InfoBlock{}
 	_, err = infoBlock.Get()
 	if err != nil {
@@ -64,7 +66,7 @@ func Confirmations(ctx context.Context, d *daemon) error {
 		return err
 	}
 	lastBlockID := infoBlock.BlockID
-	if lastBlockID == 0 {
+	if lastBlockID <= ConfirmedBlockID {
 		return nil
 	}
 
@@ -106,7 +108,7 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 			return err
 		}
 
-		ch := make(chan string)
+		ch := make(chan *tcpserver.AttestationResponse)
 		for i := 0; i < len(hosts); i++ {
 			host, err := NormalizeHostAddress(hosts[i], consts.DEFAULT_TCP_PORT)
 			if err != nil {
@@ -119,14 +121,26 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 				IsReachable(host, blockID, ch, d.logger)
 			}()
 		}
-		var answer string
+		var answer *tcpserver.AttestationResponse
 		var st0, st1 int64
 		for i := 0; i < len(hosts); i++ {
 			answer = <-ch
-			if answer == hashStr {
-				st1++
-			} else {
+			if answer == nil || len(answer.Sign) == 0 {
 				st0++
+				continue
+			}
+			// the votes for other blocks are registered too, they show when the round of votes is over
+			err = consensus.ReceiveVote(&consensus.Vote{
+				BlockID:      blockID,
+				Hash:         answer.Hash,
+				NodePosition: int64(answer.NodePosition),
+				Round:        int64(answer.Round),
+				Sign:         answer.Sign,
+			})
+			if err != nil || !bytes.Equal(answer.Hash, block.Hash) {
+				st0++
+			} else {
+				st1++
 			}
 		}
 		confirmation := &model.Confirmation{}
@@ -140,7 +154,12 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 			return err
 		}
 
-		if blockID > startBlockID && st1 >= consts.MIN_CONFIRMED_NODES {
+		// the blocks below the final block are final too
+		final, err := block.IsFinal(blockID, block.Hash)
+		if err != nil {
+			return err
+		}
+		if final {
 			break
 		}
 	}
@@ -148,50 +167,50 @@ func confirmationsBlocks(ctx context.Context, d *daemon, lastBlockID, startBlock
 	return nil
 }
 
-func checkConf(host string, blockID int64, logger *log.Entry) string {
+func getAttestation(host string, blockID int64, logger *log.Entry) *tcpserver.AttestationResponse {
 	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host, "block_id": blockID}).Debug("dialing to host")
-		return "0"
+		return nil
 	}
 	defer conn.Close()
 
 	conn.SetReadDeadline(time.Now().Add(consts.READ_TIMEOUT * time.Second))
 	conn.SetWriteDeadline(time.Now().Add(consts.WRITE_TIMEOUT * time.Second))
 
-	if err = tcpserver.SendRequestType(tcpserver.RequestTypeConfirmation, conn); err != nil {
+	if err = tcpserver.SendRequestType(tcpserver.RequestTypeAttestation, conn); err != nil {
 		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("sending request type")
-		return "0"
+		return nil
 	}
 
-	req := &tcpserver.ConfirmRequest{
+	req := &tcpserver.AttestationRequest{
 		BlockID: uint32(blockID),
 	}
 	if err = tcpserver.SendRequest(req, conn); err != nil {
-		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("sending confirmation request")
-		return "0"
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("sending attestation request")
+		return nil
 	}
 
-	resp := &tcpserver.ConfirmResponse{}
+	resp := &tcpserver.AttestationResponse{}
 	err = tcpserver.ReadRequest(resp, conn)
 	if err != nil {
-		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("receiving confirmation response")
-		return "0"
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": blockID}).Error("receiving attestation response")
+		return nil
 	}
-	return string(converter.BinToHex(resp.Hash))
+	return resp
 }
 
-// IsReachable checks if there is blockID on the host
-func IsReachable(host string, blockID int64, ch0 chan string, logger *log.Entry) {
-	ch := make(chan string, 1)
+// IsReachable gets the attestation of the block from the host
+func IsReachable(host string, blockID int64, ch0 chan *tcpserver.AttestationResponse, logger *log.Entry) {
+	ch := make(chan *tcpserver.AttestationResponse, 1)
 	go func() {
-		ch <- checkConf(host, blockID, logger)
+		ch <- getAttestation(host, blockID, logger)
 	}()
 	select {
 	case reachable := <-ch:
 		ch0 <- reachable
 	case <-time.After(consts.WAIT_CONFIRMED_NODES * time.Second):
-		ch0 <- "0"
+		ch0 <- nil
 	}
 }
 
@@ -54,6 +54,28 @@ func Monitoring(w http.ResponseWriter, r *http.Request) {
 	}
 	addKey(&buf, "transactions_count", trCount)
 
+	reorgs, failedReorgs, err := model.GetReorgsCount()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting reorgs count")
+		logError(w, fmt.Errorf("can't get reorgs count: %s", err))
+		return
+	}
+	addKey(&buf, "reorgs_count", reorgs)
+	addKey(&buf, "failed_reorgs_count", failedReorgs)
+
+	reorg := &model.Reorg{}
+	if _, err = reorg.GetLast(); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last reorg")
+		logError(w, fmt.Errorf("can't get last reorg: %s", err))
+		return
+	}
+	addKey(&buf, "last_reorg_time", reorg.Time)
+	addKey(&buf, "last_reorg_host", reorg.Host)
+	addKey(&buf, "last_reorg_fork_block_id", reorg.ForkBlockID)
+	addKey(&buf, "last_reorg_depth", reorg.Depth)
+	addKey(&buf, "last_reorg_error", reorg.Error)
+	addKey(&buf, "chain_tips_count", len(chainTips.List()))
+
 	w.Write(buf.Bytes())
 }
 
@@ -56,5 +56,5 @@ func QueueParserTx(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	return nil
+	return transaction.SyncMempool()
 }
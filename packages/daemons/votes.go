@@ -0,0 +1,75 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package daemons
+
+import (
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/tcpserver"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// votesNetwork sends the finality votes of the node to other full nodes
+type votesNetwork struct{}
+
+// Broadcast sends the vote to all full nodes
+func (n *votesNetwork) Broadcast(vote *consensus.Vote) error {
+	hosts, err := filterBannedHosts(syspar.GetRemoteHosts())
+	if err != nil {
+		return err
+	}
+	req := &tcpserver.VoteRequest{
+		BlockID:      uint32(vote.BlockID),
+		Hash:         vote.Hash,
+		NodePosition: uint32(vote.NodePosition),
+		Round:        uint32(vote.Round),
+		Sign:         vote.Sign,
+	}
+
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(h string) {
+			defer wg.Done()
+			sendVote(h, req)
+		}(utils.GetHostPort(host))
+	}
+	wg.Wait()
+	return nil
+}
+
+func sendVote(host string, req *tcpserver.VoteRequest) {
+	conn, err := utils.TCPConn(host)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConnectionError, "error": err, "host": host}).Debug("tcp connection to host")
+		return
+	}
+	defer conn.Close()
+
+	if err = tcpserver.SendRequestType(tcpserver.RequestTypeVote, conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("sending request type")
+		return
+	}
+	if err = tcpserver.SendRequest(req, conn); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host, "block_id": req.BlockID}).Error("sending vote")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkchoice
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNotHeavier is returned if the candidate chain doesn't outweigh the local chain
+	ErrNotHeavier = errors.New("Chain is not heavier than the local chain")
+	// ErrReorgTooDeep is returned if the fork point is deeper than the allowed depth of reorg
+	ErrReorgTooDeep = errors.New("Reorg is deeper than allowed")
+	// ErrFinalConflict is returned if the candidate chain conflicts with the final block
+	ErrFinalConflict = errors.New("Chain conflicts with the final block")
+	// ErrInvalidFork is returned if the fork point is out of the chains
+	ErrInvalidFork = errors.New("Invalid fork point")
+)
+
+// Tip is the head of the chain which is announced by the host
+type Tip struct {
+	Host    string
+	BlockID int64
+	Hash    []byte
+}
+
+// Compare compares the weights of the chains with tips a and b.
+// It returns 1 if a is heavier than b, -1 if b is heavier than a and 0 if the tips are equal.
+// The longer chain is heavier, the chain with the known hash of the tip outweighs the chain
+// with the unknown one, then the lower hash and finally the lower host win,
+// so all nodes choose the same chain for the same tips
+func Compare(a, b *Tip) int {
+	switch {
+	case a.BlockID != b.BlockID:
+		if a.BlockID > b.BlockID {
+			return 1
+		}
+		return -1
+	case (len(a.Hash) == 0) != (len(b.Hash) == 0):
+		if len(a.Hash) > 0 {
+			return 1
+		}
+		return -1
+	}
+	if c := bytes.Compare(a.Hash, b.Hash); c != 0 {
+		return -c
+	}
+	if a.Host != b.Host {
+		if a.Host < b.Host {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// Tips tracks the competing tips of the chains of hosts
+type Tips struct {
+	mu   sync.Mutex
+	tips map[string]*Tip
+}
+
+// NewTips returns the empty list of tips
+func NewTips() *Tips {
+	return &Tips{tips: make(map[string]*Tip)}
+}
+
+// Update sets the tip of the host. The known hash of the tip is kept
+// if the hash is empty and the height of the tip isn't changed
+func (t *Tips) Update(host string, blockID int64, hash []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tip, ok := t.tips[host]; ok && tip.BlockID == blockID && len(hash) == 0 {
+		return
+	}
+	t.tips[host] = &Tip{Host: host, BlockID: blockID, Hash: hash}
+}
+
+// Reset replaces the tracked tips with the heights of hosts
+func (t *Tips) Reset(heights map[string]int64) {
+	t.mu.Lock()
+	for host := range t.tips {
+		if _, ok := heights[host]; !ok {
+			delete(t.tips, host)
+		}
+	}
+	t.mu.Unlock()
+
+	for host, blockID := range heights {
+		t.Update(host, blockID, nil)
+	}
+}
+
+// Remove stops tracking the tip of the host
+func (t *Tips) Remove(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.tips, host)
+}
+
+// Get returns the tip of the host
+func (t *Tips) Get(host string) (Tip, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tip, ok := t.tips[host]; ok {
+		return *tip, true
+	}
+	return Tip{}, false
+}
+
+// List returns the tips sorted from the heaviest to the lightest
+func (t *Tips) List() []Tip {
+	t.mu.Lock()
+	list := make([]Tip, 0, len(t.tips))
+	for _, tip := range t.tips {
+		list = append(list, *tip)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return Compare(&list[i], &list[j]) > 0
+	})
+	return list
+}
+
+// Best returns the heaviest tip
+func (t *Tips) Best() (Tip, bool) {
+	list := t.List()
+	if len(list) == 0 {
+		return Tip{}, false
+	}
+	return list[0], true
+}
+
+// Reorg is the switch of the local chain to the heavier chain from the fork point
+type Reorg struct {
+	ForkBlockID int64
+	Old         Tip
+	New         Tip
+}
+
+// Depth returns the count of the local blocks which are rolled back
+func (r *Reorg) Depth() int64 {
+	return r.Old.BlockID - r.ForkBlockID
+}
+
+// Choice decides whether the local chain should be switched to the candidate chain
+type Choice struct {
+	// MaxDepth is the max count of the local blocks which can be rolled back, 0 means unlimited
+	MaxDepth int64
+}
+
+// Plan returns the reorg of the local chain to the candidate chain which forks at forkBlockID.
+// The reorg is refused if the candidate chain isn't heavier than the local one,
+// if it rolls back the final block or more than MaxDepth blocks
+func (c *Choice) Plan(local, candidate Tip, forkBlockID, lastFinal int64) (*Reorg, error) {
+	if forkBlockID < 0 || forkBlockID > local.BlockID || forkBlockID > candidate.BlockID {
+		return nil, ErrInvalidFork
+	}
+	r := &Reorg{ForkBlockID: forkBlockID, Old: local, New: candidate}
+	if forkBlockID == candidate.BlockID {
+		// the candidate chain is the part of the local chain
+		return r, ErrNotHeavier
+	}
+	if forkBlockID < lastFinal {
+		return r, ErrFinalConflict
+	}
+	if c.MaxDepth > 0 && r.Depth() > c.MaxDepth {
+		return r, ErrReorgTooDeep
+	}
+	if Compare(&candidate, &local) <= 0 {
+		return r, ErrNotHeavier
+	}
+	return r, nil
+}
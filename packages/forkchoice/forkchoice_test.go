@@ -0,0 +1,117 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkchoice
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b Tip
+		want int
+	}{
+		{Tip{BlockID: 5}, Tip{BlockID: 4, Hash: []byte{1}}, 1},
+		{Tip{BlockID: 4, Hash: []byte{9}}, Tip{BlockID: 5}, -1},
+		{Tip{BlockID: 5, Hash: []byte{9}}, Tip{BlockID: 5}, 1},
+		{Tip{BlockID: 5, Hash: []byte{1}}, Tip{BlockID: 5, Hash: []byte{2}}, 1},
+		{Tip{BlockID: 5, Hash: []byte{2}}, Tip{BlockID: 5, Hash: []byte{1}}, -1},
+		{Tip{Host: "a", BlockID: 5}, Tip{Host: "b", BlockID: 5}, 1},
+		{Tip{Host: "a", BlockID: 5, Hash: []byte{1}}, Tip{Host: "a", BlockID: 5, Hash: []byte{1}}, 0},
+	}
+	for i, v := range cases {
+		if got := Compare(&v.a, &v.b); got != v.want {
+			t.Errorf("case %d: expected %d, got %d", i, v.want, got)
+		}
+		if got := Compare(&v.b, &v.a); got != -v.want {
+			t.Errorf("case %d: expected %d for reversed tips, got %d", i, -v.want, got)
+		}
+	}
+}
+
+func TestTips(t *testing.T) {
+	tips := NewTips()
+	if _, ok := tips.Best(); ok {
+		t.Fatal("expected no tips")
+	}
+
+	tips.Reset(map[string]int64{"a": 10, "b": 12, "c": 12})
+	if best, _ := tips.Best(); best.Host != "b" {
+		t.Errorf("expected tip of b, got %+v", best)
+	}
+
+	tips.Update("c", 12, []byte{1})
+	if best, _ := tips.Best(); best.Host != "c" {
+		t.Errorf("expected tip with the known hash, got %+v", best)
+	}
+
+	tips.Reset(map[string]int64{"a": 10, "c": 12})
+	if tip, _ := tips.Get("c"); len(tip.Hash) == 0 {
+		t.Error("expected the hash of unchanged tip to be kept")
+	}
+	if _, ok := tips.Get("b"); ok {
+		t.Error("expected tip of b to be removed")
+	}
+
+	tips.Update("c", 13, nil)
+	if tip, _ := tips.Get("c"); len(tip.Hash) != 0 {
+		t.Error("expected the hash of changed tip to be reset")
+	}
+
+	tips.Remove("c")
+	list := tips.List()
+	if len(list) != 1 || list[0].Host != "a" {
+		t.Errorf("expected only tip of a, got %+v", list)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	choice := &Choice{MaxDepth: 3}
+	local := Tip{BlockID: 10, Hash: []byte{5}}
+
+	cases := []struct {
+		candidate       Tip
+		fork, lastFinal int64
+		err             error
+	}{
+		{Tip{Host: "a", BlockID: 11}, 9, 5, nil},
+		{Tip{Host: "a", BlockID: 11}, 7, 5, nil},
+		{Tip{Host: "a", BlockID: 11}, 6, 5, ErrReorgTooDeep},
+		{Tip{Host: "a", BlockID: 11}, 4, 5, ErrFinalConflict},
+		{Tip{Host: "a", BlockID: 10, Hash: []byte{4}}, 9, 5, nil},
+		{Tip{Host: "a", BlockID: 10, Hash: []byte{6}}, 9, 5, ErrNotHeavier},
+		{Tip{Host: "a", BlockID: 9}, 8, 5, ErrNotHeavier},
+		{Tip{Host: "a", BlockID: 9}, 9, 5, ErrNotHeavier},
+		{Tip{Host: "a", BlockID: 11}, 11, 5, ErrInvalidFork},
+		{Tip{Host: "a", BlockID: 11}, -1, 5, ErrInvalidFork},
+	}
+	for i, v := range cases {
+		r, err := choice.Plan(local, v.candidate, v.fork, v.lastFinal)
+		if err != v.err {
+			t.Errorf("case %d: expected %v, got %v", i, v.err, err)
+			continue
+		}
+		if err == nil && r.Depth() != local.BlockID-v.fork {
+			t.Errorf("case %d: expected depth %d, got %d", i, local.BlockID-v.fork, r.Depth())
+		}
+	}
+
+	unlimited := &Choice{}
+	if _, err := unlimited.Plan(local, Tip{BlockID: 11}, 5, 5); err != nil {
+		t.Errorf("expected unlimited reorg, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkchoice
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var errBadBlock = errors.New("bad block")
+
+// simChain is the chain of block hashes where the block with id N has index N-1.
+// The hash of the block which starts with "!" is the hash of invalid block
+type simChain []string
+
+func newSimChain(spec string) simChain {
+	return simChain(strings.Fields(spec))
+}
+
+func (c simChain) tip(host string) Tip {
+	if len(c) == 0 {
+		return Tip{Host: host}
+	}
+	return Tip{Host: host, BlockID: int64(len(c)), Hash: []byte(c[len(c)-1])}
+}
+
+type simEvent struct {
+	host  string
+	fork  int64
+	depth int64
+	err   error
+}
+
+// simNode replays the fork choice of the node with the in-memory chain
+type simNode struct {
+	chain  simChain
+	final  int64
+	choice Choice
+	tips   *Tips
+	events []simEvent
+}
+
+func newSimNode(spec string, final, maxDepth int64) *simNode {
+	return &simNode{
+		chain:  newSimChain(spec),
+		final:  final,
+		choice: Choice{MaxDepth: maxDepth},
+		tips:   NewTips(),
+	}
+}
+
+// forkPoint walks back from the local tip like the node loading blocks from the host
+// and returns the highest common block of chains
+func (n *simNode) forkPoint(remote simChain) (int64, error) {
+	id := int64(len(n.chain))
+	if int64(len(remote)) < id {
+		id = int64(len(remote))
+	}
+	for ; id > 0; id-- {
+		if n.choice.MaxDepth > 0 && int64(len(n.chain))-id > n.choice.MaxDepth {
+			return 0, ErrReorgTooDeep
+		}
+		if n.chain[id-1] == remote[id-1] {
+			return id, nil
+		}
+	}
+	return 0, nil
+}
+
+// sync switches the chain of the node to the remote chain if the fork choice allows it.
+// The blocks are applied to the copy of the chain so the chain is replaced only if all blocks are valid
+func (n *simNode) sync(host string, remote simChain) error {
+	candidate := remote.tip(host)
+	n.tips.Update(host, candidate.BlockID, candidate.Hash)
+
+	fork, err := n.forkPoint(remote)
+	if err != nil {
+		n.events = append(n.events, simEvent{host: host, err: err})
+		return err
+	}
+
+	r, err := n.choice.Plan(n.chain.tip(""), candidate, fork, n.final)
+	if err == nil {
+		chain := append(simChain{}, n.chain[:fork]...)
+		for _, hash := range remote[fork:] {
+			if strings.HasPrefix(hash, "!") {
+				err = errBadBlock
+				break
+			}
+			chain = append(chain, hash)
+		}
+		if err == nil {
+			n.chain = chain
+		}
+	}
+	if r != nil && r.Depth() > 0 {
+		n.events = append(n.events, simEvent{host: host, fork: r.ForkBlockID, depth: r.Depth(), err: err})
+	}
+	return err
+}
+
+// syncBest syncs the node with the heaviest of the announced chains
+func (n *simNode) syncBest(chains map[string]simChain) error {
+	heights := make(map[string]int64)
+	for host, c := range chains {
+		heights[host] = int64(len(c))
+	}
+	n.tips.Reset(heights)
+	for host, c := range chains {
+		n.tips.Update(host, int64(len(c)), []byte(c[len(c)-1]))
+	}
+
+	best, ok := n.tips.Best()
+	if !ok {
+		return nil
+	}
+	return n.sync(best.Host, chains[best.Host])
+}
+
+func TestForkScenarios(t *testing.T) {
+	cases := []struct {
+		name     string
+		local    string
+		final    int64
+		maxDepth int64
+		remote   string
+		err      error
+		chain    string
+		events   []simEvent
+	}{
+		{
+			name:   "extension of chain",
+			local:  "a1 a2 a3",
+			remote: "a1 a2 a3 a4 a5",
+			chain:  "a1 a2 a3 a4 a5",
+		},
+		{
+			name:     "longer fork",
+			local:    "a1 a2 a3 b4 b5",
+			maxDepth: 5,
+			remote:   "a1 a2 a3 c4 c5 c6",
+			chain:    "a1 a2 a3 c4 c5 c6",
+			events:   []simEvent{{host: "h", fork: 3, depth: 2}},
+		},
+		{
+			name:   "fork with equal height and lower hash",
+			local:  "a1 a2 a3 b4",
+			remote: "a1 a2 a3 a4",
+			chain:  "a1 a2 a3 a4",
+			events: []simEvent{{host: "h", fork: 3, depth: 1}},
+		},
+		{
+			name:   "fork with equal height and higher hash",
+			local:  "a1 a2 a3 a4",
+			remote: "a1 a2 a3 b4",
+			err:    ErrNotHeavier,
+			chain:  "a1 a2 a3 a4",
+			events: []simEvent{{host: "h", fork: 3, depth: 1, err: ErrNotHeavier}},
+		},
+		{
+			name:   "shorter fork",
+			local:  "a1 a2 b3 b4 b5",
+			remote: "a1 a2 c3 c4",
+			err:    ErrNotHeavier,
+			chain:  "a1 a2 b3 b4 b5",
+			events: []simEvent{{host: "h", fork: 2, depth: 3, err: ErrNotHeavier}},
+		},
+		{
+			name:     "too deep fork",
+			local:    "a1 a2 b3 b4 b5 b6",
+			maxDepth: 3,
+			remote:   "a1 a2 c3 c4 c5 c6 c7",
+			err:      ErrReorgTooDeep,
+			chain:    "a1 a2 b3 b4 b5 b6",
+			events:   []simEvent{{host: "h", err: ErrReorgTooDeep}},
+		},
+		{
+			name:   "fork below the final block",
+			local:  "a1 a2 b3 b4 b5",
+			final:  3,
+			remote: "a1 a2 c3 c4 c5 c6",
+			err:    ErrFinalConflict,
+			chain:  "a1 a2 b3 b4 b5",
+			events: []simEvent{{host: "h", fork: 2, depth: 3, err: ErrFinalConflict}},
+		},
+		{
+			name:   "fork above the final block",
+			local:  "a1 a2 b3 b4 b5",
+			final:  2,
+			remote: "a1 a2 c3 c4 c5 c6",
+			chain:  "a1 a2 c3 c4 c5 c6",
+			events: []simEvent{{host: "h", fork: 2, depth: 3}},
+		},
+		{
+			name:   "fork with invalid block is applied atomically",
+			local:  "a1 a2 b3 b4",
+			remote: "a1 a2 c3 !c4 c5",
+			err:    errBadBlock,
+			chain:  "a1 a2 b3 b4",
+			events: []simEvent{{host: "h", fork: 2, depth: 2, err: errBadBlock}},
+		},
+	}
+
+	for _, v := range cases {
+		t.Run(v.name, func(t *testing.T) {
+			node := newSimNode(v.local, v.final, v.maxDepth)
+			if err := node.sync("h", newSimChain(v.remote)); err != v.err {
+				t.Fatalf("expected error %v, got %v", v.err, err)
+			}
+			if chain := newSimChain(v.chain); !reflect.DeepEqual(node.chain, chain) {
+				t.Errorf("expected chain %v, got %v", chain, node.chain)
+			}
+			if !reflect.DeepEqual(node.events, v.events) {
+				t.Errorf("expected events %+v, got %+v", v.events, node.events)
+			}
+		})
+	}
+}
+
+func TestCompetingTips(t *testing.T) {
+	chains := map[string]simChain{
+		"host1": newSimChain("a1 a2 b3 b4 b5"),
+		"host2": newSimChain("a1 a2 c3 c4 c5"),
+		"host3": newSimChain("a1 a2 c3 c4"),
+	}
+
+	// the nodes with different chains choose the same tip and converge
+	nodes := []*simNode{
+		newSimNode("a1 a2 b3", 0, 10),
+		newSimNode("a1 a2 c3 c4", 0, 10),
+		newSimNode("a1 a2 d3 d4 d5", 0, 10),
+	}
+	for i, node := range nodes {
+		for round := 0; round < 2; round++ {
+			if err := node.syncBest(chains); err != nil && err != ErrNotHeavier {
+				t.Fatalf("node %d: %v", i, err)
+			}
+		}
+		if best, _ := node.tips.Best(); best.Host != "host1" {
+			t.Errorf("node %d: expected best tip of host1, got %+v", i, best)
+		}
+		if !reflect.DeepEqual(node.chain, chains["host1"]) {
+			t.Errorf("node %d: expected chain %v, got %v", i, chains["host1"], node.chain)
+		}
+	}
+
+	// the longest chain outweighs the chain with the lowest hash
+	chains["host3"] = newSimChain("a1 a2 c3 c4 c5 c6")
+	for i, node := range nodes {
+		if err := node.syncBest(chains); err != nil {
+			t.Fatalf("node %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(node.chain, chains["host3"]) {
+			t.Errorf("node %d: expected chain %v, got %v", i, chains["host3"], node.chain)
+		}
+		last := node.events[len(node.events)-1]
+		if last.host != "host3" || last.fork != 2 || last.depth != 3 {
+			t.Errorf("node %d: unexpected reorg event %+v", i, last)
+		}
+	}
+}
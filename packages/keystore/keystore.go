@@ -0,0 +1,347 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// Version is the version of the format of keystore files
+	Version = 1
+
+	// StandardScryptN is N parameter of scrypt for the keys of nodes
+	StandardScryptN = 1 << 18
+	// StandardScryptP is P parameter of scrypt for the keys of nodes
+	StandardScryptP = 1
+	// LightScryptN is N parameter of scrypt which uses less memory and CPU
+	LightScryptN = 1 << 12
+	// LightScryptP is P parameter of scrypt which uses less memory and CPU
+	LightScryptP = 6
+
+	// PassphraseEnv is the environment variable with the passphrase of keystore files
+	PassphraseEnv = "GENESIS_KEYSTORE_PASSPHRASE"
+
+	scryptR     = 8
+	scryptDKLen = 32
+	saltLength  = 32
+	kdfScrypt   = "scrypt"
+	cipherGCM   = "aes-256-gcm"
+	fileMode    = 0600
+)
+
+var (
+	// ErrDecrypt is returned if the passphrase is wrong or the file is damaged
+	ErrDecrypt = errors.New("Could not decrypt key with given passphrase")
+	// ErrLocked is returned if the key file is encrypted and the passphrase isn't specified
+	ErrLocked = errors.New("Keystore is locked, passphrase is required")
+	// ErrFormat is returned if the keystore file has unknown format
+	ErrFormat = errors.New("Unknown format of keystore file")
+)
+
+type kdfParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	Nonce string `json:"nonce"`
+}
+
+type cryptoJSON struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    kdfParams    `json:"kdfparams"`
+}
+
+type keyJSON struct {
+	Version int        `json:"version"`
+	ID      string     `json:"id"`
+	Crypto  cryptoJSON `json:"crypto"`
+}
+
+// IsEncrypted returns whether the content of the key file is keystore JSON
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte(`{`))
+}
+
+// Encrypt encrypts the key with the passphrase and returns keystore JSON
+func Encrypt(key, passphrase []byte, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating salt")
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("deriving key by scrypt")
+		return nil, err
+	}
+	defer Zero(derivedKey)
+
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("generating nonce")
+		return nil, err
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.MarshalIndent(keyJSON{
+		Version: Version,
+		ID:      id.String(),
+		Crypto: cryptoJSON{
+			Cipher:       cipherGCM,
+			CipherText:   hex.EncodeToString(gcm.Seal(nil, nonce, key, nil)),
+			CipherParams: cipherParams{Nonce: hex.EncodeToString(nonce)},
+			KDF:          kdfScrypt,
+			KDFParams: kdfParams{
+				N:     scryptN,
+				R:     scryptR,
+				P:     scryptP,
+				DKLen: scryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling keystore")
+		return nil, err
+	}
+	return out, nil
+}
+
+// Decrypt decrypts the key from keystore JSON with the passphrase
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	var k keyJSON
+	if err := json.Unmarshal(data, &k); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling keystore")
+		return nil, ErrFormat
+	}
+	if k.Version != Version || k.Crypto.KDF != kdfScrypt || k.Crypto.Cipher != cipherGCM {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "version": k.Version, "kdf": k.Crypto.KDF,
+			"cipher": k.Crypto.Cipher}).Error("unknown format of keystore")
+		return nil, ErrFormat
+	}
+	salt, err := hex.DecodeString(k.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	nonce, err := hex.DecodeString(k.Crypto.CipherParams.Nonce)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, ErrFormat
+	}
+
+	params := k.Crypto.KDFParams
+	derivedKey, err := scrypt.Key(passphrase, salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("deriving key by scrypt")
+		return nil, ErrFormat
+	}
+	defer Zero(derivedKey)
+
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, ErrFormat
+	}
+	key, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("creating aes cipher")
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Store keeps the passphrase of keystore files and the decrypted keys in memory
+type Store struct {
+	mu         sync.Mutex
+	passphrase []byte
+	scryptN    int
+	scryptP    int
+	keys       map[[sha256.Size]byte][]byte
+}
+
+// NewStore returns the store with the scrypt parameters for new keystore files
+func NewStore(scryptN, scryptP int) *Store {
+	return &Store{
+		scryptN: scryptN,
+		scryptP: scryptP,
+		keys:    make(map[[sha256.Size]byte][]byte),
+	}
+}
+
+// Unlock sets the passphrase of keystore files
+func (s *Store) Unlock(passphrase []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	Zero(s.passphrase)
+	s.passphrase = append([]byte{}, passphrase...)
+}
+
+// Lock removes the passphrase and the decrypted keys from memory
+func (s *Store) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	Zero(s.passphrase)
+	s.passphrase = nil
+	for hash, key := range s.keys {
+		Zero(key)
+		delete(s.keys, hash)
+	}
+}
+
+// IsUnlocked returns whether the passphrase is set
+func (s *Store) IsUnlocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.passphrase) > 0
+}
+
+// ReadKey returns the content of the key file, the encrypted keys are decrypted once and kept in memory
+func (s *Store) ReadKey(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": filename}).Error("reading key file")
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return bytes.TrimSpace(data), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash := sha256.Sum256(data)
+	if key, ok := s.keys[hash]; ok {
+		return append([]byte{}, key...), nil
+	}
+	if len(s.passphrase) == 0 {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "path": filename}).Error(ErrLocked.Error())
+		return nil, ErrLocked
+	}
+	key, err := Decrypt(data, s.passphrase)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "path": filename}).Error("decrypting key file")
+		return nil, err
+	}
+	s.keys[hash] = key
+	return append([]byte{}, key...), nil
+}
+
+// WriteKey writes the key to the file, the key is encrypted if the store is unlocked
+func (s *Store) WriteKey(filename string, key []byte) error {
+	s.mu.Lock()
+	passphrase := append([]byte{}, s.passphrase...)
+	s.mu.Unlock()
+	defer Zero(passphrase)
+
+	data := key
+	if len(passphrase) > 0 {
+		var err error
+		if data, err = Encrypt(key, passphrase, s.scryptN, s.scryptP); err != nil {
+			return err
+		}
+	}
+	return writeFile(filename, data)
+}
+
+// writeFile replaces the file atomically so the key isn't lost if writing fails
+func writeFile(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": dir}).Error("creating dir")
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(filename)+".tmp")
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": dir}).Error("creating temporary key file")
+		return err
+	}
+	tmpName := f.Name()
+	if err = f.Chmod(fileMode); err == nil {
+		if _, err = f.Write(data); err == nil {
+			err = f.Sync()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpName, filename)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": filename}).Error("writing key file")
+	}
+	return err
+}
+
+var defaultStore = NewStore(StandardScryptN, StandardScryptP)
+
+// Default returns the store of the node keys
+func Default() *Store {
+	return defaultStore
+}
+
+// Zero overwrites the secret data in memory
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
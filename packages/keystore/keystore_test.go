@@ -0,0 +1,156 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, key := range [][]byte{{}, []byte("1"), make([]byte, 32), []byte("a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5")} {
+		first, err := Encrypt(key, []byte("secret"), LightScryptN, LightScryptP)
+		require.NoError(t, err)
+		second, err := Encrypt(key, []byte("secret"), LightScryptN, LightScryptP)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second, "salt and nonce must be random")
+
+		for _, data := range [][]byte{first, second} {
+			decrypted, err := Decrypt(data, []byte("secret"))
+			require.NoError(t, err)
+			assert.Equal(t, hex.EncodeToString(key), hex.EncodeToString(decrypted))
+
+			_, err = Decrypt(data, []byte("Secret"))
+			assert.Equal(t, ErrDecrypt, err)
+			_, err = Decrypt(data, nil)
+			assert.Equal(t, ErrDecrypt, err)
+		}
+	}
+}
+
+func TestCorrupted(t *testing.T) {
+	key := []byte("a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5")
+	data, err := Encrypt(key, []byte("secret"), LightScryptN, LightScryptP)
+	require.NoError(t, err)
+
+	corrupt := func(change func(k *keyJSON)) []byte {
+		var k keyJSON
+		require.NoError(t, json.Unmarshal(data, &k))
+		change(&k)
+		out, err := json.Marshal(k)
+		require.NoError(t, err)
+		return out
+	}
+	flip := func(value string) string {
+		b, err := hex.DecodeString(value)
+		require.NoError(t, err)
+		b[0] ^= 1
+		return hex.EncodeToString(b)
+	}
+	cases := []struct {
+		data []byte
+		err  error
+	}{
+		{data[:len(data)/2], ErrFormat},
+		{corrupt(func(k *keyJSON) { k.Crypto.CipherText = flip(k.Crypto.CipherText) }), ErrDecrypt},
+		{corrupt(func(k *keyJSON) { k.Crypto.CipherText = k.Crypto.CipherText[:len(k.Crypto.CipherText)-2] }), ErrDecrypt},
+		{corrupt(func(k *keyJSON) { k.Crypto.CipherParams.Nonce = flip(k.Crypto.CipherParams.Nonce) }), ErrDecrypt},
+		{corrupt(func(k *keyJSON) { k.Crypto.KDFParams.Salt = flip(k.Crypto.KDFParams.Salt) }), ErrDecrypt},
+		{corrupt(func(k *keyJSON) { k.Crypto.KDFParams.N *= 2 }), ErrDecrypt},
+		{corrupt(func(k *keyJSON) { k.Crypto.KDFParams.Salt = "zz" }), ErrFormat},
+		{corrupt(func(k *keyJSON) { k.Crypto.CipherParams.Nonce = "00" }), ErrFormat},
+		{corrupt(func(k *keyJSON) { k.Crypto.KDFParams.N = 1000 }), ErrFormat},
+		{corrupt(func(k *keyJSON) { k.Crypto.KDFParams.DKLen = 20 }), ErrFormat},
+		{corrupt(func(k *keyJSON) { k.Crypto.Cipher = "aes-128-ctr" }), ErrFormat},
+	}
+	for i, c := range cases {
+		_, err := Decrypt(c.data, []byte("secret"))
+		assert.Equal(t, c.err, err, "case %d", i)
+	}
+
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "corrupted")
+	require.NoError(t, ioutil.WriteFile(filename, cases[1].data, fileMode))
+	store := NewStore(LightScryptN, LightScryptP)
+	store.Unlock([]byte("secret"))
+	_, err = store.ReadKey(filename)
+	assert.Equal(t, ErrDecrypt, err)
+}
+
+func TestEncrypt(t *testing.T) {
+	key := []byte("a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5")
+	data, err := Encrypt(key, []byte("secret"), LightScryptN, LightScryptP)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(data))
+	assert.False(t, IsEncrypted(key))
+	assert.NotContains(t, string(data), string(key))
+
+	decrypted, err := Decrypt(data, []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, key, decrypted)
+
+	_, err = Decrypt(data, []byte("wrong"))
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = Decrypt([]byte(`{"version": 100}`), []byte("secret"))
+	assert.Equal(t, ErrFormat, err)
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := []byte("a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5")
+	plain, encrypted := filepath.Join(dir, "plain"), filepath.Join(dir, "encrypted")
+
+	store := NewStore(LightScryptN, LightScryptP)
+	require.NoError(t, store.WriteKey(plain, key))
+	data, err := ioutil.ReadFile(plain)
+	require.NoError(t, err)
+	assert.Equal(t, key, data)
+
+	store.Unlock([]byte("secret"))
+	require.NoError(t, store.WriteKey(encrypted, key))
+	info, err := os.Stat(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+
+	for _, filename := range []string{plain, encrypted} {
+		data, err = store.ReadKey(filename)
+		require.NoError(t, err)
+		assert.Equal(t, key, data)
+	}
+
+	store.Lock()
+	assert.False(t, store.IsUnlocked())
+	_, err = store.ReadKey(encrypted)
+	assert.Equal(t, ErrLocked, err)
+
+	store.Unlock([]byte("wrong"))
+	_, err = store.ReadKey(encrypted)
+	assert.Equal(t, ErrDecrypt, err)
+}
@@ -132,51 +132,78 @@ func langIndex(state int, vde bool) int {
 	return state
 }
 
-// LangText looks for the specified word through language sources and returns the meaning of the source
-// if it is found. Search goes according to the languages specified in 'accept'
-func LangText(in string, state, appID int, accept string, vde bool) (string, bool) {
+// Fallbacks returns the fallback chain of the language tag from the most specific to the base language,
+// for example pt-BR -> pt. The tag is normalized to the lower case.
+func Fallbacks(tag string) []string {
+	if ind := strings.IndexByte(tag, ';'); ind >= 0 {
+		tag = tag[:ind]
+	}
+	tag = strings.Replace(strings.ToLower(strings.TrimSpace(tag)), `_`, `-`, -1)
+	if len(tag) == 0 {
+		return nil
+	}
+	chain := []string{tag}
+	for ind := strings.LastIndexByte(tag, '-'); ind > 0; ind = strings.LastIndexByte(tag, '-') {
+		tag = tag[:ind]
+		chain = append(chain, tag)
+	}
+	return chain
+}
+
+// langText returns the value of the resource and the language which has been chosen
+func langText(in string, state, appID int, accept string, vde bool) (string, string, bool) {
 	if strings.IndexByte(in, ' ') >= 0 || state == 0 {
-		return in, false
+		return in, ``, false
 	}
 	istate := langIndex(state, vde)
 	if _, ok := lang[istate]; !ok {
 		if err := loadLang(state, vde); err != nil {
-			return err.Error(), false
+			return err.Error(), ``, false
 		}
 	}
-	langs := strings.Split(accept, `,`)
 	if _, ok := (*lang[istate]).res[appID]; !ok {
-		return in, false
-	}
-	if lres, ok := (*lang[istate]).res[appID][in]; ok {
-		lng := DefLang()
-		for _, val := range langs {
-			val = strings.ToLower(val)
-			if len(val) < 2 {
-				break
-			}
-			if !IsLang(val[:2]) {
-				continue
-			}
-			if len(val) >= 5 && val[2] == '-' {
-				if _, ok := (*lres)[val[:5]]; ok {
-					lng = val[:5]
-					break
-				}
-			}
-			if _, ok := (*lres)[val[:2]]; ok {
-				lng = val[:2]
-				break
-			}
+		return in, ``, false
+	}
+	lres, ok := (*lang[istate]).res[appID][in]
+	if !ok {
+		return in, ``, false
+	}
+	for _, val := range strings.Split(accept, `,`) {
+		chain := Fallbacks(val)
+		if len(chain) == 0 || !IsLang(chain[len(chain)-1]) {
+			continue
 		}
-		if len((*lres)[lng]) == 0 {
-			for _, val := range *lres {
-				return val, true
+		for _, lng := range chain {
+			if len((*lres)[lng]) > 0 {
+				return (*lres)[lng], lng, true
 			}
 		}
-		return (*lres)[lng], true
 	}
-	return in, false
+	lng := DefLang()
+	if len((*lres)[lng]) == 0 {
+		for key, val := range *lres {
+			return val, key, true
+		}
+	}
+	return (*lres)[lng], lng, true
+}
+
+// LangText looks for the specified word through language sources and returns the meaning of the source
+// if it is found. Search goes according to the languages specified in 'accept', every language falls back
+// to its base language and then to the default language.
+func LangText(in string, state, appID int, accept string, vde bool) (string, bool) {
+	ret, _, ok := langText(in, state, appID, accept, vde)
+	return ret, ok
+}
+
+// LangFormat looks for the resource like LangText and formats its value with the specified parameters
+// using the plural rules of the chosen language
+func LangFormat(in string, state, appID int, accept string, vde bool, params map[string]string) (string, bool) {
+	ret, lng, ok := langText(in, state, appID, accept, vde)
+	if !ok {
+		return ret, false
+	}
+	return Format(lng, ret, params), true
 }
 
 // LangMacro replaces all inclusions of $resname$ in the incoming text with the corresponding language resources,
@@ -0,0 +1,274 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+const (
+	msgText   = ``
+	msgArg    = `arg`
+	msgNumber = `#`
+	msgPlural = `plural`
+	msgSelect = `select`
+
+	maxMsgDepth = 8
+)
+
+var errMessage = errors.New(`wrong message format`)
+
+// msgPart is the part of the message, it is either a text or an argument
+type msgPart struct {
+	kind    string
+	text    string // the text or the source of the argument
+	name    string
+	options map[string][]msgPart
+}
+
+type msgParser struct {
+	in  []rune
+	pos int
+}
+
+func (p *msgParser) skipSpaces() {
+	for p.pos < len(p.in) && (p.in[p.pos] == ' ' || p.in[p.pos] == '\t' ||
+		p.in[p.pos] == '\r' || p.in[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// readUntil returns the trimmed text before one of the stop runes
+func (p *msgParser) readUntil(stop string) string {
+	start := p.pos
+	for p.pos < len(p.in) && !strings.ContainsRune(stop, p.in[p.pos]) {
+		p.pos++
+	}
+	return strings.TrimSpace(string(p.in[start:p.pos]))
+}
+
+// quoted processes the apostrophe at the current position
+func (p *msgParser) quoted() string {
+	if p.pos+1 < len(p.in) && p.in[p.pos+1] == '\'' {
+		p.pos += 2
+		return `'`
+	}
+	if p.pos+1 >= len(p.in) || !strings.ContainsRune(`{}#`, p.in[p.pos+1]) {
+		p.pos++
+		return `'`
+	}
+	out := make([]rune, 0, 16)
+	for p.pos++; p.pos < len(p.in); p.pos++ {
+		if p.in[p.pos] == '\'' {
+			if p.pos+1 < len(p.in) && p.in[p.pos+1] == '\'' {
+				out = append(out, '\'')
+				p.pos++
+				continue
+			}
+			p.pos++
+			break
+		}
+		out = append(out, p.in[p.pos])
+	}
+	return string(out)
+}
+
+// parse parses the message until the end of the input or the closing brace of the option
+func (p *msgParser) parse(plural bool, depth int) ([]msgPart, error) {
+	if depth > maxMsgDepth {
+		return nil, errMessage
+	}
+	parts := make([]msgPart, 0)
+	text := make([]rune, 0, len(p.in))
+	flush := func() {
+		if len(text) > 0 {
+			parts = append(parts, msgPart{kind: msgText, text: string(text)})
+			text = text[:0]
+		}
+	}
+	for p.pos < len(p.in) {
+		switch r := p.in[p.pos]; {
+		case r == '\'':
+			text = append(text, []rune(p.quoted())...)
+		case r == '#' && plural:
+			flush()
+			parts = append(parts, msgPart{kind: msgNumber})
+			p.pos++
+		case r == '{':
+			flush()
+			arg, err := p.argument(plural, depth)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, arg)
+		case r == '}':
+			if depth == 0 {
+				return nil, errMessage
+			}
+			flush()
+			return parts, nil
+		default:
+			text = append(text, r)
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, errMessage
+	}
+	flush()
+	return parts, nil
+}
+
+// argument parses {name}, {name, type}, {name, plural, ...} and {name, select, ...}
+func (p *msgParser) argument(plural bool, depth int) (arg msgPart, err error) {
+	start := p.pos
+	p.pos++
+	arg.kind = msgArg
+	arg.name = p.readUntil(`,}`)
+	if len(arg.name) == 0 || p.pos >= len(p.in) {
+		return arg, errMessage
+	}
+	if p.in[p.pos] == ',' {
+		p.pos++
+		kind := strings.ToLower(p.readUntil(`,}`))
+		if p.pos >= len(p.in) {
+			return arg, errMessage
+		}
+		switch kind {
+		case msgPlural, msgSelect:
+			if p.in[p.pos] != ',' {
+				return arg, errMessage
+			}
+			p.pos++
+			arg.kind = kind
+			if arg.options, err = p.options(plural || kind == msgPlural, depth); err != nil {
+				return
+			}
+		default:
+			// other formats such as number or date are output as the simple argument
+			if p.in[p.pos] == ',' {
+				p.pos++
+				p.readUntil(`}`)
+			}
+		}
+		if p.pos >= len(p.in) {
+			return arg, errMessage
+		}
+	}
+	if p.in[p.pos] != '}' {
+		return arg, errMessage
+	}
+	p.pos++
+	arg.text = string(p.in[start:p.pos])
+	return
+}
+
+func (p *msgParser) options(plural bool, depth int) (map[string][]msgPart, error) {
+	options := make(map[string][]msgPart)
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.in) {
+			return nil, errMessage
+		}
+		if p.in[p.pos] == '}' {
+			break
+		}
+		selector := p.readUntil("{} \t\r\n")
+		p.skipSpaces()
+		if len(selector) == 0 || p.pos >= len(p.in) || p.in[p.pos] != '{' {
+			return nil, errMessage
+		}
+		p.pos++
+		option, err := p.parse(plural, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+		options[selector] = option
+	}
+	if len(options) == 0 {
+		return nil, errMessage
+	}
+	return options, nil
+}
+
+func formatParts(parts []msgPart, lang string, params map[string]string, number string) string {
+	var out bytes.Buffer
+	for _, part := range parts {
+		switch part.kind {
+		case msgText:
+			out.WriteString(part.text)
+		case msgNumber:
+			if len(number) == 0 {
+				out.WriteString(`#`)
+			} else {
+				out.WriteString(number)
+			}
+		case msgArg:
+			if val, ok := params[part.name]; ok {
+				out.WriteString(val)
+			} else {
+				out.WriteString(part.text)
+			}
+		default:
+			val, ok := params[part.name]
+			if !ok {
+				out.WriteString(part.text)
+				continue
+			}
+			var option []msgPart
+			if part.kind == msgPlural {
+				if option, ok = part.options[`=`+strings.TrimSpace(val)]; !ok {
+					option, ok = part.options[PluralCategory(lang, val)]
+				}
+			} else {
+				option, ok = part.options[val]
+			}
+			if !ok {
+				option, ok = part.options[PluralOther]
+			}
+			if !ok {
+				out.WriteString(part.text)
+				continue
+			}
+			if part.kind == msgPlural {
+				out.WriteString(formatParts(option, lang, params, val))
+			} else {
+				out.WriteString(formatParts(option, lang, params, number))
+			}
+		}
+	}
+	return out.String()
+}
+
+// Format formats the message in ICU MessageFormat style with the specified parameters.
+// It supports {name} placeholders, {name, plural, ...} with the plural rules of the language
+// and {name, select, ...} which is used for gender forms. The placeholders without parameters
+// are left as is and the message is returned unchanged if it cannot be parsed.
+func Format(lang, message string, params map[string]string) string {
+	if !strings.ContainsRune(message, '{') {
+		return message
+	}
+	parser := msgParser{in: []rune(message)}
+	parts, err := parser.parse(false, 0)
+	if err != nil {
+		return message
+	}
+	return formatParts(parts, lang, params, ``)
+}
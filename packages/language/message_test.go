@@ -0,0 +1,80 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"testing"
+)
+
+func TestPluralCategory(t *testing.T) {
+	for _, item := range []struct {
+		lang, number, want string
+	}{
+		{`en`, `1`, PluralOne}, {`en`, `1.0`, PluralOther}, {`en`, `0`, PluralOther},
+		{`fr`, `0`, PluralOne}, {`fr`, `1.5`, PluralOne}, {`fr`, `2`, PluralOther},
+		{`pt-BR`, `0`, PluralOne}, {`pt-PT`, `0`, PluralOther},
+		{`ru`, `1`, PluralOne}, {`ru`, `21`, PluralOne}, {`ru`, `11`, PluralMany},
+		{`ru`, `3`, PluralFew}, {`ru`, `14`, PluralMany}, {`ru`, `25`, PluralMany}, {`ru`, `1.5`, PluralOther},
+		{`pl`, `22`, PluralFew}, {`pl`, `21`, PluralMany}, {`cs`, `3`, PluralFew}, {`cs`, `0.5`, PluralMany},
+		{`ar`, `0`, PluralZero}, {`ar`, `2`, PluralTwo}, {`ar`, `103`, PluralFew}, {`ar`, `111`, PluralMany},
+		{`ar`, `100`, PluralOther}, {`zh`, `1`, PluralOther}, {`xx`, `1`, PluralOne}, {`en`, `abc`, PluralOther},
+	} {
+		if got := PluralCategory(item.lang, item.number); got != item.want {
+			t.Errorf(`wrong category %s %s: %s != %s`, item.lang, item.number, got, item.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	files := `{count, plural, =0 {no files} one {# file} few {# files (few)} many {# files (many)} other {# files}}`
+	for _, item := range []struct {
+		lang, message string
+		params        map[string]string
+		want          string
+	}{
+		{`en`, `Hello, {name}!`, map[string]string{`name`: `Bob`}, `Hello, Bob!`},
+		{`en`, `Hello, {name}!`, nil, `Hello, {name}!`},
+		{`en`, `Plain text`, nil, `Plain text`},
+		{`en`, files, map[string]string{`count`: `0`}, `no files`},
+		{`en`, files, map[string]string{`count`: `1`}, `1 file`},
+		{`en`, files, map[string]string{`count`: `5`}, `5 files`},
+		{`ru`, files, map[string]string{`count`: `3`}, `3 files (few)`},
+		{`ru`, files, map[string]string{`count`: `5`}, `5 files (many)`},
+		{`en`, files, nil, files},
+		{`en`, `{gender, select, female {She} male {He} other {They}} sent {n, plural, one {# message} other {# messages}}`,
+			map[string]string{`gender`: `female`, `n`: `2`}, `She sent 2 messages`},
+		{`en`, `{gender, select, female {She} other {They}} left`, map[string]string{`gender`: `x`}, `They left`},
+		{`en`, `It''s '{literal}' {v, number}`, map[string]string{`v`: `7`}, `It's {literal} 7`},
+		{`en`, `Broken {name`, map[string]string{`name`: `x`}, `Broken {name`},
+		{`en`, `{n, plural, one {# and {s, select, a {A} other {#}}} other {x}}`,
+			map[string]string{`n`: `1`, `s`: `b`}, `1 and 1`},
+	} {
+		if got := Format(item.lang, item.message, item.params); got != item.want {
+			t.Errorf(`wrong format %s: %s != %s`, item.message, got, item.want)
+		}
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	chain := Fallbacks(` zh_Hant-TW;q=0.8`)
+	if len(chain) != 3 || chain[0] != `zh-hant-tw` || chain[1] != `zh-hant` || chain[2] != `zh` {
+		t.Errorf(`wrong fallback chain %v`, chain)
+	}
+	if len(Fallbacks(``)) != 0 {
+		t.Error(`fallback chain of the empty tag must be empty`)
+	}
+}
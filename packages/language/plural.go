@@ -0,0 +1,189 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Plural categories of CLDR plural rules
+const (
+	PluralZero  = `zero`
+	PluralOne   = `one`
+	PluralTwo   = `two`
+	PluralFew   = `few`
+	PluralMany  = `many`
+	PluralOther = `other`
+)
+
+// operands are the plural operands of the number, n is the absolute value,
+// i is the integer part and v is the count of the visible fraction digits
+type operands struct {
+	n float64
+	i int64
+	v int
+}
+
+type pluralRule func(op operands) string
+
+var pluralRules = map[string]pluralRule{
+	`ar`:    pluralArabic,
+	`be`:    pluralEastSlavic,
+	`cs`:    pluralCzech,
+	`de`:    pluralOneInteger,
+	`el`:    pluralOneNumber,
+	`en`:    pluralOneInteger,
+	`es`:    pluralOneNumber,
+	`fr`:    pluralZeroOne,
+	`hi`:    pluralZeroOne,
+	`id`:    pluralNone,
+	`it`:    pluralOneInteger,
+	`ja`:    pluralNone,
+	`ko`:    pluralNone,
+	`nl`:    pluralOneInteger,
+	`pl`:    pluralPolish,
+	`pt`:    pluralZeroOne,
+	`pt-pt`: pluralOneInteger,
+	`ru`:    pluralEastSlavic,
+	`sk`:    pluralCzech,
+	`sv`:    pluralOneInteger,
+	`th`:    pluralNone,
+	`tr`:    pluralOneNumber,
+	`uk`:    pluralEastSlavic,
+	`vi`:    pluralNone,
+	`zh`:    pluralNone,
+}
+
+func newOperands(number string) (op operands, ok bool) {
+	number = strings.TrimPrefix(strings.TrimSpace(number), `-`)
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return
+	}
+	op.n = n
+	op.i = int64(n)
+	if dot := strings.IndexByte(number, '.'); dot >= 0 && !strings.ContainsAny(number, `eE`) {
+		op.v = len(number) - dot - 1
+	}
+	return op, true
+}
+
+func inRange(val, from, to int64) bool {
+	return val >= from && val <= to
+}
+
+func pluralNone(op operands) string {
+	return PluralOther
+}
+
+func pluralOneInteger(op operands) string {
+	if op.i == 1 && op.v == 0 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralOneNumber(op operands) string {
+	if op.n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralZeroOne(op operands) string {
+	if op.i == 0 || op.i == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralEastSlavic(op operands) string {
+	if op.v != 0 {
+		return PluralOther
+	}
+	mod10, mod100 := op.i%10, op.i%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case inRange(mod10, 2, 4) && !inRange(mod100, 12, 14):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+func pluralPolish(op operands) string {
+	if op.v != 0 {
+		return PluralOther
+	}
+	mod10, mod100 := op.i%10, op.i%100
+	switch {
+	case op.i == 1:
+		return PluralOne
+	case inRange(mod10, 2, 4) && !inRange(mod100, 12, 14):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+func pluralCzech(op operands) string {
+	switch {
+	case op.v != 0:
+		return PluralMany
+	case op.i == 1:
+		return PluralOne
+	case inRange(op.i, 2, 4):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+func pluralArabic(op operands) string {
+	if op.v != 0 || op.n != float64(op.i) {
+		return PluralOther
+	}
+	mod100 := op.i % 100
+	switch {
+	case op.i == 0:
+		return PluralZero
+	case op.i == 1:
+		return PluralOne
+	case op.i == 2:
+		return PluralTwo
+	case inRange(mod100, 3, 10):
+		return PluralFew
+	case inRange(mod100, 11, 99):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// PluralCategory returns the plural category of the number according to the rules of the language.
+// The rules of the region are used if they exist, otherwise the rules of the base language are applied.
+func PluralCategory(lang, number string) string {
+	op, ok := newOperands(number)
+	if !ok {
+		return PluralOther
+	}
+	for _, tag := range Fallbacks(lang) {
+		if rule, ok := pluralRules[tag]; ok {
+			return rule(op)
+		}
+	}
+	return pluralOneInteger(op)
+}
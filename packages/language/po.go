@@ -0,0 +1,152 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var errPO = errors.New(`wrong PO file`)
+
+// poQuote returns the string in the double quotes with escaped symbols
+func poQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s) + `"`
+}
+
+func poUnquote(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return ``, errPO
+	}
+	return strconv.Unquote(s)
+}
+
+// WritePO writes the translation units as gettext PO file. The names of resources are written as msgctxt
+// and the source texts are written as msgid.
+func WritePO(w io.Writer, units []Unit, source, target string) error {
+	if _, err := fmt.Fprintf(w, "msgid \"\"\nmsgstr \"\"\n%s\n%s\n%s\n", poQuote("Content-Type: text/plain; charset=UTF-8\n"),
+		poQuote(`Language: `+target+"\n"), poQuote(`X-Source-Language: `+source+"\n")); err != nil {
+		return err
+	}
+	for _, unit := range units {
+		msgid := unit.Source
+		if len(msgid) == 0 {
+			msgid = unit.Name
+		}
+		if _, err := fmt.Fprintf(w, "\nmsgctxt %s\nmsgid %s\nmsgstr %s\n", poQuote(unit.Name),
+			poQuote(msgid), poQuote(unit.Target)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type poEntry struct {
+	fuzzy   bool
+	context *string
+	msgid   *string
+	msgstr  *string
+}
+
+// ReadPO reads the translation units from gettext PO file. The entries marked as fuzzy are skipped.
+// The target language is taken from the Language header.
+func ReadPO(r io.Reader) ([]Unit, string, error) {
+	var (
+		units   []Unit
+		lang    string
+		entry   poEntry
+		current *string
+	)
+	flush := func() {
+		if entry.msgid != nil && entry.msgstr != nil {
+			if entry.context == nil && len(*entry.msgid) == 0 {
+				for _, line := range strings.Split(*entry.msgstr, "\n") {
+					if strings.HasPrefix(line, `Language:`) {
+						lang = strings.TrimSpace(strings.TrimPrefix(line, `Language:`))
+					}
+				}
+			} else if !entry.fuzzy {
+				name := *entry.msgid
+				if entry.context != nil {
+					name = *entry.context
+				}
+				units = append(units, Unit{Name: name, Source: *entry.msgid, Target: *entry.msgstr})
+			}
+		}
+		entry = poEntry{}
+		current = nil
+	}
+	field := func(ptr **string, value string) error {
+		text, err := poUnquote(value)
+		if err != nil {
+			return err
+		}
+		*ptr = &text
+		current = *ptr
+		return nil
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		var err error
+		switch {
+		case len(text) == 0:
+			flush()
+		case strings.HasPrefix(text, `#,`):
+			if entry.msgstr != nil {
+				flush()
+			}
+			entry.fuzzy = entry.fuzzy || strings.Contains(text, `fuzzy`)
+		case strings.HasPrefix(text, `#`):
+		case strings.HasPrefix(text, `msgctxt `):
+			if entry.msgstr != nil {
+				flush()
+			}
+			err = field(&entry.context, text[len(`msgctxt `):])
+		case strings.HasPrefix(text, `msgid `):
+			if entry.msgstr != nil {
+				flush()
+			}
+			err = field(&entry.msgid, text[len(`msgid `):])
+		case strings.HasPrefix(text, `msgstr `):
+			err = field(&entry.msgstr, text[len(`msgstr `):])
+		case strings.HasPrefix(text, `"`):
+			var value string
+			if current == nil {
+				err = errPO
+			} else if value, err = poUnquote(text); err == nil {
+				*current += value
+			}
+		default:
+			err = errPO
+		}
+		if err != nil {
+			return nil, ``, fmt.Errorf(`%s at line %d`, errPO, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ``, err
+	}
+	flush()
+	return units, lang, nil
+}
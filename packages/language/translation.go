@@ -0,0 +1,232 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Formats of the translation files
+const (
+	FormatPO    = `po`
+	FormatXLIFF = `xliff`
+)
+
+var (
+	// ErrFormat is returned for unknown formats of translation files
+	ErrFormat = errors.New(`unknown translation format`)
+
+	templateUsage = []*regexp.Regexp{
+		regexp.MustCompile(`LangRes\(\s*(?:Name\s*:\s*)?"?([\w\-.]+)`),
+		regexp.MustCompile(`\$([\w\-.]+)\$`),
+	}
+	contractUsage = []*regexp.Regexp{
+		regexp.MustCompile(`Lang(?:Res|Format)\(\s*[^,()]+,\s*"([\w\-.]+)"`),
+	}
+)
+
+// Translation is the language resource with values for all languages
+type Translation struct {
+	ID    int64
+	AppID int64
+	Name  string
+	Res   map[string]string
+}
+
+// Unit is the translation of the resource from the source language to the target language
+type Unit struct {
+	Name   string
+	Source string
+	Target string
+}
+
+// Change is the difference between the language resource and the imported translation
+type Change struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Trans string `json:"trans"`
+}
+
+// LoadTranslations returns the language resources of the ecosystem, if appID is not zero then
+// only resources of the application are returned
+func LoadTranslations(prefix string, appID int64) ([]Translation, error) {
+	languages, err := (&model.Language{}).GetAll(prefix)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("Error querying all languages")
+		return nil, err
+	}
+	list := make([]Translation, 0, len(languages))
+	for _, item := range languages {
+		if appID != 0 && item.AppID != appID {
+			continue
+		}
+		res := make(map[string]string)
+		if len(item.Res) > 0 {
+			if err = json.Unmarshal([]byte(item.Res), &res); err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "value": item.Res, "error": err}).Error("Unmarshalling json")
+				return nil, err
+			}
+		}
+		list = append(list, Translation{ID: item.ID, AppID: item.AppID, Name: item.Name, Res: res})
+	}
+	return list, nil
+}
+
+// Units returns the translation units of the resources for the source and target languages
+func Units(list []Translation, source, target string) []Unit {
+	units := make([]Unit, len(list))
+	for i, item := range list {
+		units[i] = Unit{Name: item.Name, Source: item.Res[source], Target: item.Res[target]}
+	}
+	return units
+}
+
+// Export writes the translation units in the specified format
+func Export(w io.Writer, format string, units []Unit, source, target string) error {
+	switch format {
+	case FormatPO:
+		return WritePO(w, units, source, target)
+	case FormatXLIFF:
+		return WriteXLIFF(w, units, source, target)
+	}
+	return ErrFormat
+}
+
+// Import reads the translation units in the specified format and returns them with the target language
+// which is specified in the file
+func Import(r io.Reader, format string) ([]Unit, string, error) {
+	switch format {
+	case FormatPO:
+		return ReadPO(r)
+	case FormatXLIFF:
+		return ReadXLIFF(r)
+	}
+	return nil, ``, ErrFormat
+}
+
+// Diff compares the imported translations of the language with the resources. It returns the changes
+// with the new values of resources and the names which have not been found in the resources.
+// The units with empty translations are skipped.
+func Diff(list []Translation, lang string, units []Unit) ([]Change, []string, error) {
+	byName := make(map[string]*Translation, len(list))
+	for i := range list {
+		byName[list[i].Name] = &list[i]
+	}
+	changes := make([]Change, 0)
+	unknown := make([]string, 0)
+	for _, unit := range units {
+		if len(unit.Target) == 0 {
+			continue
+		}
+		item, ok := byName[unit.Name]
+		if !ok {
+			unknown = append(unknown, unit.Name)
+			continue
+		}
+		if item.Res[lang] == unit.Target {
+			continue
+		}
+		res := make(map[string]string, len(item.Res)+1)
+		for key, val := range item.Res {
+			res[key] = val
+		}
+		res[lang] = unit.Target
+		trans, err := json.Marshal(res)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling language resource")
+			return nil, nil, err
+		}
+		changes = append(changes, Change{ID: item.ID, Name: item.Name, Old: item.Res[lang],
+			New: unit.Target, Trans: string(trans)})
+	}
+	return changes, unknown, nil
+}
+
+func findUsage(used map[string]bool, patterns []*regexp.Regexp, query string) error {
+	return model.ProcessRows(nil, query, func(columns []string, row map[string]string) error {
+		for _, re := range patterns {
+			for _, match := range re.FindAllStringSubmatch(row[`value`], -1) {
+				used[match[1]] = true
+			}
+		}
+		return nil
+	})
+}
+
+// Usage returns the names of the language resources which are used in pages, blocks, menu and contracts
+// of the ecosystem
+func Usage(prefix string) (map[string]bool, error) {
+	used := make(map[string]bool)
+	for _, table := range []string{`pages`, `blocks`, `menu`} {
+		if err := findUsage(used, templateUsage, `select value from "`+prefix+`_`+table+`"`); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("scanning language usage")
+			return nil, err
+		}
+	}
+	if err := findUsage(used, contractUsage, `select value from "`+prefix+`_contracts"`); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("scanning language usage in contracts")
+		return nil, err
+	}
+	return used, nil
+}
+
+// CheckKeys returns the names which are used but don't exist in the resources and the resources
+// which are not used anywhere. If appID is not zero then only obsolete resources of the application
+// are returned.
+func CheckKeys(list []Translation, used map[string]bool, appID int64) (missing []string, obsolete []string) {
+	missing, obsolete = make([]string, 0), make([]string, 0)
+	exists := make(map[string]bool, len(list))
+	for _, item := range list {
+		exists[item.Name] = true
+		if !used[item.Name] && (appID == 0 || item.AppID == appID) {
+			obsolete = append(obsolete, item.Name)
+		}
+	}
+	for name := range used {
+		if !exists[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(obsolete)
+	return
+}
+
+// CheckUsage scans the ecosystem and returns missing and obsolete names of the language resources
+func CheckUsage(prefix string, appID int64) ([]string, []string, error) {
+	list, err := LoadTranslations(prefix, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	used, err := Usage(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	missing, obsolete := CheckKeys(list, used, appID)
+	return missing, obsolete, nil
+}
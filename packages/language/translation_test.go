@@ -0,0 +1,128 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"bytes"
+	"testing"
+)
+
+var testTranslations = []Translation{
+	{ID: 1, AppID: 1, Name: `hello`, Res: map[string]string{`en`: `Hello, "{name}"`, `ru`: `Привет`}},
+	{ID: 2, AppID: 1, Name: `lines`, Res: map[string]string{`en`: "First\nsecond"}},
+	{ID: 3, AppID: 2, Name: `unused`, Res: map[string]string{`en`: `Unused`}},
+}
+
+func TestExchangeFormats(t *testing.T) {
+	units := Units(testTranslations, `en`, `ru`)
+	for _, format := range []string{FormatPO, FormatXLIFF} {
+		var buf bytes.Buffer
+		if err := Export(&buf, format, units, `en`, `ru`); err != nil {
+			t.Fatal(err)
+		}
+		out, lang, err := Import(&buf, format)
+		if err != nil {
+			t.Fatalf(`%s: %s`, format, err)
+		}
+		if lang != `ru` || len(out) != len(units) {
+			t.Fatalf(`%s: wrong import %s %v`, format, lang, out)
+		}
+		for i, unit := range units {
+			if out[i] != unit {
+				t.Errorf(`%s: wrong unit %v != %v`, format, out[i], unit)
+			}
+		}
+	}
+	if _, _, err := Import(&bytes.Buffer{}, `csv`); err != ErrFormat {
+		t.Error(`unknown format must be rejected`)
+	}
+}
+
+func TestReadPO(t *testing.T) {
+	input := `# translator comment
+msgid ""
+msgstr ""
+"Language: de\n"
+
+msgctxt "hello"
+msgid "Hello"
+msgstr "Hal"
+"lo"
+
+#, fuzzy
+msgctxt "lines"
+msgid "Lines"
+msgstr "Zeilen"
+msgid "world"
+msgstr "Welt"
+`
+	units, lang, err := ReadPO(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang != `de` || len(units) != 2 || units[0].Target != `Hallo` || units[1].Name != `world` {
+		t.Errorf(`wrong PO units %s %v`, lang, units)
+	}
+	if _, _, err = ReadPO(bytes.NewBufferString("msgid \"a\"\nmsgstr unquoted\n")); err == nil {
+		t.Error(`wrong PO file must be rejected`)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	changes, unknown, err := Diff(testTranslations, `ru`, []Unit{
+		{Name: `hello`, Target: `Привет`},
+		{Name: `lines`, Target: "Первая\nвторая"},
+		{Name: `unused`},
+		{Name: `absent`, Target: `Нет`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].ID != 2 || changes[0].Trans != `{"en":"First\nsecond","ru":"Первая\nвторая"}` {
+		t.Errorf(`wrong changes %v`, changes)
+	}
+	if len(unknown) != 1 || unknown[0] != `absent` {
+		t.Errorf(`wrong unknown %v`, unknown)
+	}
+	if _, ok := testTranslations[1].Res[`ru`]; ok {
+		t.Error(`resources have been modified`)
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	missing, obsolete := CheckKeys(testTranslations, map[string]bool{`hello`: true, `lines`: true, `new`: true}, 0)
+	if len(missing) != 1 || missing[0] != `new` || len(obsolete) != 1 || obsolete[0] != `unused` {
+		t.Errorf(`wrong keys %v %v`, missing, obsolete)
+	}
+	if _, obsolete = CheckKeys(testTranslations, map[string]bool{}, 2); len(obsolete) != 1 {
+		t.Errorf(`wrong obsolete keys of application %v`, obsolete)
+	}
+	used := make(map[string]bool)
+	for _, re := range templateUsage {
+		for _, match := range re.FindAllStringSubmatch(`Span($title$) LangRes(Name: "header") LangRes(save, fr)`, -1) {
+			used[match[1]] = true
+		}
+	}
+	for _, re := range contractUsage {
+		for _, match := range re.FindAllStringSubmatch(`$a = LangRes(1, "contract_res", "en")`, -1) {
+			used[match[1]] = true
+		}
+	}
+	if len(used) != 4 || !used[`title`] || !used[`header`] || !used[`save`] || !used[`contract_res`] {
+		t.Errorf(`wrong usage %v`, used)
+	}
+}
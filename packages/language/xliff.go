@@ -0,0 +1,86 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package language
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const xliffNamespace = `urn:oasis:names:tc:xliff:document:1.2`
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr,omitempty"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+type xliffUnit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target"`
+}
+
+// WriteXLIFF writes the translation units as XLIFF 1.2 document, the names of resources are used as ids
+func WriteXLIFF(w io.Writer, units []Unit, source, target string) error {
+	file := xliffFile{Original: `languages`, SourceLanguage: source, TargetLanguage: target,
+		Datatype: `plaintext`, Units: make([]xliffUnit, len(units))}
+	for i, unit := range units {
+		file.Units[i] = xliffUnit{ID: unit.Name, Source: unit.Source, Target: unit.Target}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent(``, `  `)
+	if err := enc.Encode(xliffDoc{Xmlns: xliffNamespace, Version: `1.2`, Files: []xliffFile{file}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadXLIFF reads the translation units from XLIFF 1.2 document. The target language is taken
+// from the first file which has it.
+func ReadXLIFF(r io.Reader) ([]Unit, string, error) {
+	var doc xliffDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, ``, err
+	}
+	var (
+		units []Unit
+		lang  string
+	)
+	for _, file := range doc.Files {
+		if len(lang) == 0 {
+			lang = file.TargetLanguage
+		}
+		for _, unit := range file.Units {
+			units = append(units, Unit{Name: unit.ID, Source: unit.Source, Target: unit.Target})
+		}
+	}
+	return units, lang, nil
+}
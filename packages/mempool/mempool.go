@@ -0,0 +1,475 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultMaxCount is the default max count of transactions in the mempool
+	DefaultMaxCount = 10000
+	// DefaultMaxSize is the default max size of transactions in the mempool
+	DefaultMaxSize = 64 << 20
+)
+
+var (
+	// ErrDuplicate is returned if the transaction is already in the mempool
+	ErrDuplicate = errors.New("Transaction is already in the mempool")
+	// ErrUnderpriced is returned if the transaction of the same request pays more
+	ErrUnderpriced = errors.New("Transaction of the same request pays more")
+	// ErrPoolFull is returned if the mempool is full of transactions which pay more
+	ErrPoolFull = errors.New("Mempool is full")
+	// ErrReplaced is the reason of the drop of the transaction which has been replaced by fee
+	ErrReplaced = errors.New("Transaction has been replaced by the transaction which pays more")
+	// ErrEvicted is the reason of the drop of the transaction which has been evicted by memory pressure
+	ErrEvicted = errors.New("Transaction has been evicted from the full mempool")
+)
+
+var (
+	pool     *Pool
+	poolOnce sync.Once
+)
+
+// GetPool returns the mempool of the node with the limits from the config
+func GetPool() *Pool {
+	poolOnce.Do(func() {
+		pool = New(Config{MaxCount: conf.Config.Mempool.MaxCount, MaxSize: conf.Config.Mempool.MaxSize})
+	})
+	return pool
+}
+
+// Config contains the limits of the mempool
+type Config struct {
+	MaxCount int
+	MaxSize  int64
+}
+
+// Fee is the price which the sender offers for the transaction
+type Fee struct {
+	PayOver decimal.Decimal
+	MaxSum  decimal.Decimal
+}
+
+// ParseFee returns the fee of payover and max_sum of the transaction, the incorrect values are zero
+func ParseFee(payOver, maxSum string) Fee {
+	var fee Fee
+	if v, err := decimal.NewFromString(payOver); err == nil {
+		fee.PayOver = v
+	}
+	if v, err := decimal.NewFromString(maxSum); err == nil {
+		fee.MaxSum = v
+	}
+	return fee
+}
+
+// Cmp compares the fees, the fuel price over the rate is more important than the max sum
+func (f Fee) Cmp(other Fee) int {
+	if c := f.PayOver.Cmp(other.PayOver); c != 0 {
+		return c
+	}
+	return f.MaxSum.Cmp(other.MaxSum)
+}
+
+// Tx is the verified transaction which is waiting for the block
+type Tx struct {
+	Hash      []byte
+	Data      []byte
+	KeyID     int64
+	RequestID string
+	Type      int64
+	// Seq is the sequence number of the transaction of the account
+	Seq int64
+	// Nonce is the optional nonce of the transaction, the transactions with nonces
+	// are selected only without gaps after the last used nonce of the account
+	Nonce    int64
+	HighRate int8
+	Fee      Fee
+	Arrival  time.Time
+
+	order uint64
+	index int
+}
+
+// priority returns true if a should be included in the block before b
+func priority(a, b *Tx) bool {
+	if a.HighRate != b.HighRate {
+		return a.HighRate > b.HighRate
+	}
+	if c := a.Fee.Cmp(b.Fee); c != 0 {
+		return c > 0
+	}
+	return a.order < b.order
+}
+
+// sequence returns true if a precedes b in the transactions of the account
+func sequence(a, b *Tx) bool {
+	if a.Seq != b.Seq {
+		return a.Seq < b.Seq
+	}
+	return a.order < b.order
+}
+
+// lowest is the heap of transactions where the top has the lowest priority
+type lowest []*Tx
+
+func (h lowest) Len() int           { return len(h) }
+func (h lowest) Less(i, j int) bool { return priority(h[j], h[i]) }
+func (h lowest) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *lowest) Push(x interface{}) {
+	tx := x.(*Tx)
+	tx.index = len(*h)
+	*h = append(*h, tx)
+}
+func (h *lowest) Pop() interface{} {
+	old := *h
+	tx := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return tx
+}
+
+// highest is the heap of transactions where the top has the highest priority
+type highest []*Tx
+
+func (h highest) Len() int            { return len(h) }
+func (h highest) Less(i, j int) bool  { return priority(h[i], h[j]) }
+func (h highest) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *highest) Push(x interface{}) { *h = append(*h, x.(*Tx)) }
+func (h *highest) Pop() interface{} {
+	old := *h
+	tx := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return tx
+}
+
+type requestKey struct {
+	keyID     int64
+	requestID string
+	txType    int64
+	nonce     int64
+}
+
+// replaceKey returns the key of the transactions which replace each other.
+// These are the transactions with the same nonce or the parts of the same request
+func replaceKey(tx *Tx) (requestKey, bool) {
+	if tx.Nonce > 0 {
+		return requestKey{keyID: tx.KeyID, nonce: tx.Nonce}, true
+	}
+	if len(tx.RequestID) > 0 {
+		return requestKey{keyID: tx.KeyID, requestID: tx.RequestID, txType: tx.Type}, true
+	}
+	return requestKey{}, false
+}
+
+// Dropped is the transaction which has been removed from the mempool by another transaction
+type Dropped struct {
+	Tx     *Tx
+	Reason error
+}
+
+// Stats contains the state of the mempool
+type Stats struct {
+	Count    int   `json:"count"`
+	Size     int64 `json:"size"`
+	Accounts int   `json:"accounts"`
+	MaxCount int   `json:"max_count"`
+	MaxSize  int64 `json:"max_size"`
+}
+
+// Pool keeps the transactions in memory ordered by fee and arrival
+type Pool struct {
+	mu       sync.RWMutex
+	config   Config
+	txs      map[string]*Tx
+	accounts map[int64][]*Tx
+	requests map[requestKey][]*Tx
+	lowest   lowest
+	size     int64
+	counter  uint64
+}
+
+// New returns the empty mempool, the zero limits are replaced with the defaults
+func New(config Config) *Pool {
+	if config.MaxCount <= 0 {
+		config.MaxCount = DefaultMaxCount
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMaxSize
+	}
+	return &Pool{
+		config:   config,
+		txs:      make(map[string]*Tx),
+		accounts: make(map[int64][]*Tx),
+		requests: make(map[requestKey][]*Tx),
+	}
+}
+
+// Add puts the transaction into the mempool. The transactions of the same request which pay less
+// are replaced and the transactions with the lowest priority are evicted if the mempool is full
+func (p *Pool) Add(tx *Tx) ([]Dropped, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.txs[string(tx.Hash)]; ok {
+		return nil, ErrDuplicate
+	}
+	if int64(len(tx.Data)) > p.config.MaxSize {
+		return nil, ErrPoolFull
+	}
+
+	p.counter++
+	tx.order = p.counter
+	if tx.Arrival.IsZero() {
+		tx.Arrival = time.Now()
+	}
+
+	var dropped []Dropped
+	if key, ok := replaceKey(tx); ok {
+		for _, prev := range p.requests[key] {
+			// the transaction with the same nonce must pay more to replace the previous one
+			if c := prev.Fee.Cmp(tx.Fee); c > 0 || (c == 0 && tx.Nonce > 0) {
+				return nil, ErrUnderpriced
+			}
+		}
+		for _, prev := range append([]*Tx{}, p.requests[key]...) {
+			// the transactions with the same fee are the different parts of the request
+			if prev.Fee.Cmp(tx.Fee) < 0 {
+				p.remove(prev)
+				dropped = append(dropped, Dropped{Tx: prev, Reason: ErrReplaced})
+			}
+		}
+	}
+
+	for len(p.txs) >= p.config.MaxCount || p.size+int64(len(tx.Data)) > p.config.MaxSize {
+		last := p.lowest[0]
+		if !priority(tx, last) {
+			p.restore(dropped)
+			return nil, ErrPoolFull
+		}
+		p.remove(last)
+		dropped = append(dropped, Dropped{Tx: last, Reason: ErrEvicted})
+	}
+
+	p.insert(tx)
+	return dropped, nil
+}
+
+// restore puts back the dropped transactions if the new transaction has been refused
+func (p *Pool) restore(dropped []Dropped) {
+	for _, d := range dropped {
+		p.insert(d.Tx)
+	}
+}
+
+func (p *Pool) insert(tx *Tx) {
+	p.txs[string(tx.Hash)] = tx
+	p.size += int64(len(tx.Data))
+	heap.Push(&p.lowest, tx)
+
+	list := p.accounts[tx.KeyID]
+	i := sort.Search(len(list), func(i int) bool { return sequence(tx, list[i]) })
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = tx
+	p.accounts[tx.KeyID] = list
+
+	if key, ok := replaceKey(tx); ok {
+		p.requests[key] = append(p.requests[key], tx)
+	}
+}
+
+func (p *Pool) remove(tx *Tx) {
+	delete(p.txs, string(tx.Hash))
+	p.size -= int64(len(tx.Data))
+	heap.Remove(&p.lowest, tx.index)
+
+	p.accounts[tx.KeyID] = without(p.accounts[tx.KeyID], tx)
+	if len(p.accounts[tx.KeyID]) == 0 {
+		delete(p.accounts, tx.KeyID)
+	}
+
+	if key, ok := replaceKey(tx); ok {
+		p.requests[key] = without(p.requests[key], tx)
+		if len(p.requests[key]) == 0 {
+			delete(p.requests, key)
+		}
+	}
+}
+
+func without(list []*Tx, tx *Tx) []*Tx {
+	for i, item := range list {
+		if item == tx {
+			return append(list[:i:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// Remove removes the transaction from the mempool
+func (p *Pool) Remove(hash []byte) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tx, ok := p.txs[string(hash)]
+	if ok {
+		p.remove(tx)
+	}
+	return ok
+}
+
+// Has returns true if the transaction is in the mempool
+func (p *Pool) Has(hash []byte) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.txs[string(hash)]
+	return ok
+}
+
+// Get returns the transaction with the hash
+func (p *Pool) Get(hash []byte) (*Tx, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tx, ok := p.txs[string(hash)]
+	return tx, ok
+}
+
+// Hashes returns the hashes of all transactions of the mempool
+func (p *Pool) Hashes() [][]byte {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	hashes := make([][]byte, 0, len(p.txs))
+	for _, tx := range p.txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+// Account returns the transactions of the account in the order of sequence
+func (p *Pool) Account(keyID int64) []*Tx {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]*Tx{}, p.accounts[keyID]...)
+}
+
+// NonceFunc returns the next nonce of the account which is expected by the blockchain
+type NonceFunc func(keyID int64) int64
+
+// cursor walks through the transactions of the account in the order of sequence
+type cursor struct {
+	list  []*Tx
+	pos   int
+	nonce int64
+}
+
+// next returns the next transaction of the account which can be included into the block.
+// The transactions with gaps between nonces wait in the mempool for the missing transactions
+func (c *cursor) next(nonces NonceFunc) *Tx {
+	for ; c.pos < len(c.list); c.pos++ {
+		tx := c.list[c.pos]
+		if tx.Nonce > 0 && nonces != nil {
+			if c.nonce == 0 {
+				c.nonce = nonces(tx.KeyID)
+			}
+			if tx.Nonce > c.nonce {
+				continue
+			}
+			if tx.Nonce == c.nonce {
+				c.nonce++
+			}
+		}
+		c.pos++
+		return tx
+	}
+	return nil
+}
+
+// Select returns up to limit transactions in the order of inclusion into the block.
+// The transactions are ordered by priority, but the transactions of the account
+// are never reordered against their sequence. The zero limit returns all transactions.
+// If nonces is nil then the gaps between nonces aren't checked
+func (p *Pool) Select(limit int, nonces NonceFunc) []*Tx {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if limit <= 0 || limit > len(p.txs) {
+		limit = len(p.txs)
+	}
+
+	cursors := make(map[int64]*cursor, len(p.accounts))
+	heads := make(highest, 0, len(p.accounts))
+	for keyID, list := range p.accounts {
+		cursors[keyID] = &cursor{list: list}
+		if tx := cursors[keyID].next(nonces); tx != nil {
+			heads = append(heads, tx)
+		}
+	}
+	heap.Init(&heads)
+
+	result := make([]*Tx, 0, limit)
+	for len(result) < limit && heads.Len() > 0 {
+		tx := heap.Pop(&heads).(*Tx)
+		result = append(result, tx)
+
+		if next := cursors[tx.KeyID].next(nonces); next != nil {
+			heap.Push(&heads, next)
+		}
+	}
+	return result
+}
+
+// PendingNonce returns the next nonce of the account after the transactions of the mempool
+// which follow the nonce expected by the blockchain without gaps
+func (p *Pool) PendingNonce(keyID, nonce int64) int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, tx := range p.accounts[keyID] {
+		if tx.Nonce == nonce {
+			nonce++
+		}
+	}
+	return nonce
+}
+
+// Stats returns the state of the mempool
+func (p *Pool) Stats() Stats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return Stats{
+		Count:    len(p.txs),
+		Size:     p.size,
+		Accounts: len(p.accounts),
+		MaxCount: p.config.MaxCount,
+		MaxSize:  p.config.MaxSize,
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package mempool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTx(name string, keyID, seq int64, payOver string) *Tx {
+	return &Tx{
+		Hash:  []byte(name),
+		Data:  make([]byte, 10),
+		KeyID: keyID,
+		Seq:   seq,
+		Fee:   ParseFee(payOver, ""),
+	}
+}
+
+func hashes(list []*Tx) []string {
+	result := make([]string, len(list))
+	for i, tx := range list {
+		result[i] = string(tx.Hash)
+	}
+	return result
+}
+
+func TestFee(t *testing.T) {
+	assert.Equal(t, 1, ParseFee("1.5", "").Cmp(ParseFee("1", "1000")))
+	assert.Equal(t, 1, ParseFee("1", "1000").Cmp(ParseFee("1", "999")))
+	assert.Equal(t, 0, ParseFee("wrong", "").Cmp(ParseFee("", "0")))
+}
+
+func TestSelectByFee(t *testing.T) {
+	p := New(Config{})
+	for _, tx := range []*Tx{
+		newTx("spam1", 1, 1, ""),
+		newTx("spam2", 2, 1, ""),
+		newTx("payment", 3, 1, "10"),
+		newTx("cheap", 4, 1, "0.5"),
+	} {
+		_, err := p.Add(tx)
+		require.NoError(t, err)
+	}
+	_, err := p.Add(newTx("payment", 3, 1, "10"))
+	assert.Equal(t, ErrDuplicate, err)
+
+	stop := newTx("stop", 5, 1, "")
+	stop.HighRate = 2
+	_, err = p.Add(stop)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"stop", "payment", "cheap", "spam1", "spam2"}, hashes(p.Select(0, nil)))
+	assert.Equal(t, []string{"stop", "payment"}, hashes(p.Select(2, nil)))
+}
+
+func TestSelectBySequence(t *testing.T) {
+	p := New(Config{})
+	for _, tx := range []*Tx{
+		newTx("a2", 1, 2, "100"),
+		newTx("b1", 2, 1, "5"),
+		newTx("a1", 1, 1, "1"),
+		newTx("a3", 1, 3, "1"),
+	} {
+		_, err := p.Add(tx)
+		require.NoError(t, err)
+	}
+
+	// the expensive transaction of account waits for the previous transaction of the account
+	assert.Equal(t, []string{"b1", "a1", "a2", "a3"}, hashes(p.Select(0, nil)))
+	assert.Equal(t, []string{"a1", "a2", "a3"}, hashes(p.Account(1)))
+
+	require.True(t, p.Remove([]byte("a1")))
+	assert.False(t, p.Remove([]byte("a1")))
+	assert.Equal(t, []string{"a2", "b1", "a3"}, hashes(p.Select(0, nil)))
+}
+
+func TestReplaceByFee(t *testing.T) {
+	p := New(Config{})
+
+	first := newTx("first", 1, 1, "1")
+	first.RequestID = "req"
+	_, err := p.Add(first)
+	require.NoError(t, err)
+
+	// the part of the multiple request with the same fee isn't replaced
+	part := newTx("part", 1, 1, "1")
+	part.RequestID = "req"
+	_, err = p.Add(part)
+	require.NoError(t, err)
+
+	cheaper := newTx("cheaper", 1, 1, "0.5")
+	cheaper.RequestID = "req"
+	_, err = p.Add(cheaper)
+	assert.Equal(t, ErrUnderpriced, err)
+
+	other := newTx("other", 1, 1, "0.5")
+	other.RequestID = "req"
+	other.Type = 2
+	_, err = p.Add(other)
+	require.NoError(t, err)
+
+	bump := newTx("bump", 1, 1, "2")
+	bump.RequestID = "req"
+	dropped, err := p.Add(bump)
+	require.NoError(t, err)
+	require.Len(t, dropped, 2)
+	for _, d := range dropped {
+		assert.Equal(t, ErrReplaced, d.Reason)
+	}
+	assert.False(t, p.Has([]byte("first")))
+	assert.False(t, p.Has([]byte("part")))
+	assert.Equal(t, []string{"other", "bump"}, hashes(p.Select(0, nil)))
+}
+
+func TestEviction(t *testing.T) {
+	p := New(Config{MaxCount: 3, MaxSize: 1000})
+	for i := 0; i < 3; i++ {
+		_, err := p.Add(newTx(fmt.Sprintf("spam%d", i), int64(i), 1, "1"))
+		require.NoError(t, err)
+	}
+
+	_, err := p.Add(newTx("late", 10, 1, "1"))
+	assert.Equal(t, ErrPoolFull, err)
+
+	dropped, err := p.Add(newTx("payment", 11, 1, "5"))
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, "spam2", string(dropped[0].Tx.Hash))
+	assert.Equal(t, ErrEvicted, dropped[0].Reason)
+	assert.Equal(t, []string{"payment", "spam0", "spam1"}, hashes(p.Select(0, nil)))
+
+	// the size of transactions is limited as well
+	p = New(Config{MaxCount: 100, MaxSize: 25})
+	_, err = p.Add(newTx("a", 1, 1, "1"))
+	require.NoError(t, err)
+	_, err = p.Add(newTx("b", 2, 1, "2"))
+	require.NoError(t, err)
+	dropped, err = p.Add(newTx("c", 3, 1, "3"))
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, "a", string(dropped[0].Tx.Hash))
+
+	big := newTx("big", 4, 1, "100")
+	big.Data = make([]byte, 26)
+	_, err = p.Add(big)
+	assert.Equal(t, ErrPoolFull, err)
+
+	stats := p.Stats()
+	assert.Equal(t, Stats{Count: 2, Size: 20, Accounts: 2, MaxCount: 100, MaxSize: 25}, stats)
+}
+
+func TestRestoreOnFull(t *testing.T) {
+	p := New(Config{MaxSize: 25})
+	old := newTx("old", 1, 1, "1")
+	old.RequestID = "req"
+	_, err := p.Add(old)
+	require.NoError(t, err)
+	_, err = p.Add(newTx("rich", 2, 1, "10"))
+	require.NoError(t, err)
+
+	// the replacement is rolled back if there is no room for the new transaction
+	bump := newTx("bump", 1, 1, "1.5")
+	bump.RequestID = "req"
+	bump.Data = make([]byte, 20)
+	_, err = p.Add(bump)
+	assert.Equal(t, ErrPoolFull, err)
+	assert.Equal(t, []string{"rich", "old"}, hashes(p.Select(0, nil)))
+	assert.Equal(t, int64(20), p.Stats().Size)
+}
+
+func newNonceTx(name string, keyID, nonce int64, payOver string) *Tx {
+	tx := newTx(name, keyID, nonce, payOver)
+	tx.Nonce = nonce
+	return tx
+}
+
+func TestSelectByNonce(t *testing.T) {
+	p := New(Config{})
+	for _, tx := range []*Tx{
+		newNonceTx("n4", 1, 4, ""),
+		newNonceTx("n2", 1, 2, ""),
+		newNonceTx("n1", 1, 1, ""),
+		newTx("other", 2, 1, ""),
+	} {
+		_, err := p.Add(tx)
+		require.NoError(t, err)
+	}
+
+	next := func(keyID int64) int64 { return 1 }
+	assert.Equal(t, []string{"n1", "n2", "other"}, hashes(p.Select(0, next)))
+	assert.Equal(t, int64(3), p.PendingNonce(1, 1))
+	assert.Equal(t, []string{"n1", "n2", "n4", "other"}, hashes(p.Select(0, nil)))
+
+	_, err := p.Add(newNonceTx("n3", 1, 3, ""))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2", "other", "n3", "n4"}, hashes(p.Select(0, next)))
+	assert.Equal(t, int64(5), p.PendingNonce(1, 1))
+
+	// the used nonces are selected to be refused by the check of the transaction
+	next = func(keyID int64) int64 { return 3 }
+	assert.Equal(t, []string{"n1", "n2", "other", "n3", "n4"}, hashes(p.Select(0, next)))
+}
+
+func TestReplaceByNonce(t *testing.T) {
+	p := New(Config{})
+	_, err := p.Add(newNonceTx("first", 1, 1, "1"))
+	require.NoError(t, err)
+
+	_, err = p.Add(newNonceTx("same", 1, 1, "1"))
+	assert.Equal(t, ErrUnderpriced, err)
+
+	dropped, err := p.Add(newNonceTx("bump", 1, 1, "2"))
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, "first", string(dropped[0].Tx.Hash))
+	assert.Equal(t, ErrReplaced, dropped[0].Reason)
+	assert.Equal(t, []string{"bump"}, hashes(p.Select(0, nil)))
+}
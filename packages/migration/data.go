@@ -149,4 +149,196 @@ var (
 		DROP TABLE IF EXISTS "stop_daemons"; CREATE TABLE "stop_daemons" (
 		"stop_time" int NOT NULL DEFAULT '0'
 		);`
+
+	migrationRollbackTxBlock = `
+		CREATE INDEX "rollback_tx_table_block" ON "rollback_tx" (table_name, block_id);`
+
+	migrationChanges = `
+		DROP SEQUENCE IF EXISTS changes_id_seq CASCADE;
+		CREATE SEQUENCE changes_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "changes"; CREATE TABLE "changes" (
+		"id" bigint NOT NULL  default nextval('changes_id_seq'),
+		"block_id" bigint NOT NULL DEFAULT '0',
+		"tx_hash" bytea  NOT NULL DEFAULT '',
+		"table_name" varchar(255) NOT NULL DEFAULT '',
+		"table_id" varchar(255) NOT NULL DEFAULT '',
+		"event" varchar(16) NOT NULL DEFAULT '',
+		"old" TEXT NOT NULL DEFAULT '',
+		"new" TEXT NOT NULL DEFAULT ''
+		);
+		ALTER SEQUENCE changes_id_seq owned by changes.id;
+		ALTER TABLE ONLY "changes" ADD CONSTRAINT changes_pkey PRIMARY KEY (id);
+		CREATE INDEX "changes_block" ON "changes" (block_id);`
+
+	migrationSearchIndexes = `
+		DO $$
+		DECLARE
+			tbl record;
+		BEGIN
+			FOR tbl IN SELECT table_name, CASE WHEN table_name LIKE '%\_languages' THEN 'res' ELSE 'value' END AS col
+				FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name ~ '^[0-9]+_(pages|menu|blocks|contracts|languages)$'
+			LOOP
+				EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING gin (to_tsvector(''simple'', name || '' '' || %I))',
+					tbl.table_name || '_index_search', tbl.table_name, tbl.col);
+			END LOOP;
+		END $$;`
+
+	migrationComponents = `
+		DO $$
+		DECLARE
+			eco text;
+		BEGIN
+			FOR eco IN SELECT substring(table_name from '^[0-9]+')
+				FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name ~ '^[0-9]+_blocks$'
+			LOOP
+				EXECUTE format('CREATE TABLE IF NOT EXISTS %I (
+					"id" bigint NOT NULL DEFAULT ''0'' PRIMARY KEY,
+					"name" character varying(255) UNIQUE NOT NULL DEFAULT '''',
+					"value" text NOT NULL DEFAULT '''',
+					"params" text NOT NULL DEFAULT '''',
+					"conditions" text NOT NULL DEFAULT '''',
+					"app_id" bigint NOT NULL DEFAULT ''1'')', eco || '_components');
+				EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (name)', eco || '_components_index_name', eco || '_components');
+				EXECUTE format('INSERT INTO %I ("id", "name", "permissions", "columns", "conditions")
+					SELECT -1, ''components'',
+						''{"insert": "ContractConditions(\"MainCondition\")", "update": "ContractConditions(\"MainCondition\")",
+							"new_column": "ContractConditions(\"MainCondition\")"}'',
+						''{"name": "ContractConditions(\"MainCondition\")", "value": "ContractConditions(\"MainCondition\")",
+							"params": "ContractConditions(\"MainCondition\")", "conditions": "ContractConditions(\"MainCondition\")",
+							"app_id": "ContractConditions(\"MainCondition\")"}'',
+						''ContractAccess("@1EditTable")''
+					WHERE NOT EXISTS (SELECT 1 FROM %I WHERE name = ''components'')', eco || '_tables', eco || '_tables');
+			END LOOP;
+		END $$;`
+
+	migrationTableMigrations = `
+		DO $$
+		DECLARE
+			eco text;
+		BEGIN
+			FOR eco IN SELECT substring(table_name from '^[0-9]+')
+				FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name ~ '^[0-9]+_blocks$'
+			LOOP
+				EXECUTE format('CREATE TABLE IF NOT EXISTS %I (
+					"id" bigint NOT NULL DEFAULT ''0'' PRIMARY KEY,
+					"table_name" character varying(255) NOT NULL DEFAULT '''',
+					"version" bigint NOT NULL DEFAULT ''0'',
+					"action" character varying(32) NOT NULL DEFAULT '''',
+					"params" jsonb,
+					"block_id" bigint NOT NULL DEFAULT ''0'')', eco || '_migrations');
+				EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS %I ON %I (table_name, version)',
+					eco || '_migrations_index_version', eco || '_migrations');
+				EXECUTE format('INSERT INTO %I ("id", "name", "permissions", "columns", "conditions")
+					SELECT -2, ''migrations'',
+						''{"insert": "false", "update": "false", "new_column": "false"}'',
+						''{"table_name": "false", "version": "false", "action": "false", "params": "false",
+							"block_id": "false"}'',
+						''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM %I WHERE name = ''migrations'')', eco || '_tables', eco || '_tables');
+			END LOOP;
+		END $$;`
+
+	migrationConsensus = `
+		DO $$
+		BEGIN
+			IF to_regclass('"1_system_parameters"') IS NOT NULL THEN
+				INSERT INTO "1_system_parameters" ("id", "name", "value", "conditions")
+					SELECT 68, 'consensus', 'roundrobin', 'true'
+					WHERE NOT EXISTS (SELECT 1 FROM "1_system_parameters" WHERE name = 'consensus');
+			END IF;
+		END $$;`
+
+	migrationBlockFinality = `
+		ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "final" boolean NOT NULL DEFAULT false;
+		ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "attestations" bytea;
+		CREATE INDEX IF NOT EXISTS "block_chain_index_final" ON "block_chain" (id) WHERE final;`
+
+	migrationReorgs = `
+		DROP SEQUENCE IF EXISTS reorgs_id_seq CASCADE;
+		CREATE SEQUENCE reorgs_id_seq START WITH 1;
+		DROP TABLE IF EXISTS "reorgs"; CREATE TABLE "reorgs" (
+		"id" bigint NOT NULL  default nextval('reorgs_id_seq'),
+		"time" bigint NOT NULL DEFAULT '0',
+		"host" varchar(255) NOT NULL DEFAULT '',
+		"fork_block_id" bigint NOT NULL DEFAULT '0',
+		"old_block_id" bigint NOT NULL DEFAULT '0',
+		"old_hash" bytea  NOT NULL DEFAULT '',
+		"new_block_id" bigint NOT NULL DEFAULT '0',
+		"new_hash" bytea  NOT NULL DEFAULT '',
+		"depth" bigint NOT NULL DEFAULT '0',
+		"error" TEXT NOT NULL DEFAULT ''
+		);
+		ALTER SEQUENCE reorgs_id_seq owned by reorgs.id;
+		ALTER TABLE ONLY "reorgs" ADD CONSTRAINT reorgs_pkey PRIMARY KEY (id);`
+
+	migrationKeyNonces = `
+		DROP TABLE IF EXISTS "key_nonces"; CREATE TABLE "key_nonces" (
+		"id" bigint NOT NULL DEFAULT '0',
+		"nonce" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "key_nonces" ADD CONSTRAINT key_nonces_pkey PRIMARY KEY (id);`
+
+	migrationMultisig = `
+		DO $$
+		DECLARE
+			eco text;
+		BEGIN
+			FOR eco IN SELECT substring(table_name from '^[0-9]+')
+				FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name ~ '^[0-9]+_blocks$'
+			LOOP
+				EXECUTE format('CREATE TABLE IF NOT EXISTS %I (
+					"id" bigint NOT NULL DEFAULT ''0'' PRIMARY KEY,
+					"threshold" bigint NOT NULL DEFAULT ''0'',
+					"keys" text NOT NULL DEFAULT '''')', eco || '_multisig_wallets');
+				EXECUTE format('INSERT INTO %I ("id", "name", "permissions", "columns", "conditions")
+					SELECT -3, ''multisig_wallets'',
+						''{"insert": "ContractAccess(\"@1NewMultisigWallet\")", "update": "false",
+							"new_column": "false"}'',
+						''{"threshold": "false", "keys": "false"}'',
+						''ContractConditions("MainCondition")''
+					WHERE NOT EXISTS (SELECT 1 FROM %I WHERE name = ''multisig_wallets'')', eco || '_tables', eco || '_tables');
+			END LOOP;
+		END $$;
+
+		DROP TABLE IF EXISTS "multisig_txs"; CREATE TABLE "multisig_txs" (
+		"hash" bytea NOT NULL DEFAULT '',
+		"wallet_id" bigint NOT NULL DEFAULT '0',
+		"ecosystem_id" bigint NOT NULL DEFAULT '0',
+		"data" bytea NOT NULL DEFAULT '',
+		"signers" bigint NOT NULL DEFAULT '0',
+		"time" bigint NOT NULL DEFAULT '0',
+		"tx_hash" bytea
+		);
+		ALTER TABLE ONLY "multisig_txs" ADD CONSTRAINT multisig_txs_pkey PRIMARY KEY (hash);
+		CREATE INDEX "multisig_txs_index_wallet" ON "multisig_txs" (ecosystem_id, wallet_id);`
+
+	migrationKeyTypes = `
+		DO $$
+		DECLARE
+			eco text;
+		BEGIN
+			FOR eco IN SELECT substring(table_name from '^[0-9]+')
+				FROM information_schema.tables
+				WHERE table_schema = 'public' AND table_name ~ '^[0-9]+_blocks$'
+			LOOP
+				EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS "key_type" bigint NOT NULL DEFAULT ''0''',
+					eco || '_keys');
+				EXECUTE format('UPDATE %I SET "columns" = jsonb_set("columns", ''{key_type}'',
+					''"ContractConditions(\"MainCondition\")"'') WHERE name = ''keys''', eco || '_tables');
+			END LOOP;
+		END $$;`
+
+	migrationNodeKeyRotation = `
+		DO $$
+		BEGIN
+			IF to_regclass('"1_system_parameters"') IS NOT NULL THEN
+				INSERT INTO "1_system_parameters" ("id", "name", "value", "conditions")
+					SELECT 69, 'node_key_overlap', '1000', 'true'
+					WHERE NOT EXISTS (SELECT 1 FROM "1_system_parameters" WHERE name = 'node_key_overlap');
+			END IF;
+		END $$;`
 )
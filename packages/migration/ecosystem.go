@@ -28,6 +28,7 @@ func GetFirstEcosystemScript() string {
 		firstEcosystemSchema,
 		firstDelayedContractsDataSQL,
 		firstEcosystemContractsSQL,
+		firstEcosystemUpgradeSQL(),
 		firstEcosystemDataSQL,
 		firstSystemParametersDataSQL,
 		firstTablesDataSQL,
@@ -44,7 +45,8 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		"maxpay" decimal(30) NOT NULL DEFAULT '0' CHECK (maxpay >= 0),
 		"multi" bigint NOT NULL DEFAULT '0',
 		"deleted" bigint NOT NULL DEFAULT '0',
-		"blocked" bigint NOT NULL DEFAULT '0'
+		"blocked" bigint NOT NULL DEFAULT '0',
+		"key_type" bigint NOT NULL DEFAULT '0'
 		);
 		ALTER TABLE ONLY "%[1]d_keys" ADD CONSTRAINT "%[1]d_keys_pkey" PRIMARY KEY (id);
 		
@@ -73,6 +75,7 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		);
 		ALTER TABLE ONLY "%[1]d_languages" ADD CONSTRAINT "%[1]d_languages_pkey" PRIMARY KEY (id);
 		CREATE INDEX "%[1]d_languages_index_name" ON "%[1]d_languages" (name);
+		CREATE INDEX "%[1]d_languages_index_search" ON "%[1]d_languages" USING gin (to_tsvector('simple', name || ' ' || res));
 		
 		DROP TABLE IF EXISTS "%[1]d_sections"; CREATE TABLE "%[1]d_sections" (
 		"id" bigint  NOT NULL DEFAULT '0',
@@ -94,6 +97,7 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		);
 		ALTER TABLE ONLY "%[1]d_menu" ADD CONSTRAINT "%[1]d_menu_pkey" PRIMARY KEY (id);
 		CREATE INDEX "%[1]d_menu_index_name" ON "%[1]d_menu" (name);
+		CREATE INDEX "%[1]d_menu_index_search" ON "%[1]d_menu" USING gin (to_tsvector('simple', name || ' ' || value));
 
 		DROP TABLE IF EXISTS "%[1]d_pages"; 
 		CREATE TABLE "%[1]d_pages" (
@@ -108,6 +112,7 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		);
 		ALTER TABLE ONLY "%[1]d_pages" ADD CONSTRAINT "%[1]d_pages_pkey" PRIMARY KEY (id);
 		CREATE INDEX "%[1]d_pages_index_name" ON "%[1]d_pages" (name);
+		CREATE INDEX "%[1]d_pages_index_search" ON "%[1]d_pages" USING gin (to_tsvector('simple', name || ' ' || value));
 
 
 		DROP TABLE IF EXISTS "%[1]d_blocks"; CREATE TABLE "%[1]d_blocks" (
@@ -119,6 +124,36 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		);
 		ALTER TABLE ONLY "%[1]d_blocks" ADD CONSTRAINT "%[1]d_blocks_pkey" PRIMARY KEY (id);
 		CREATE INDEX "%[1]d_blocks_index_name" ON "%[1]d_blocks" (name);
+		CREATE INDEX "%[1]d_blocks_index_search" ON "%[1]d_blocks" USING gin (to_tsvector('simple', name || ' ' || value));
+
+		DROP TABLE IF EXISTS "%[1]d_components"; CREATE TABLE "%[1]d_components" (
+			"id" bigint  NOT NULL DEFAULT '0',
+			"name" character varying(255) UNIQUE NOT NULL DEFAULT '',
+			"value" text NOT NULL DEFAULT '',
+			"params" text NOT NULL DEFAULT '',
+			"conditions" text NOT NULL DEFAULT '',
+			"app_id" bigint NOT NULL DEFAULT '1'
+		);
+		ALTER TABLE ONLY "%[1]d_components" ADD CONSTRAINT "%[1]d_components_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_components_index_name" ON "%[1]d_components" (name);
+
+		DROP TABLE IF EXISTS "%[1]d_migrations"; CREATE TABLE "%[1]d_migrations" (
+			"id" bigint  NOT NULL DEFAULT '0',
+			"table_name" character varying(255) NOT NULL DEFAULT '',
+			"version" bigint NOT NULL DEFAULT '0',
+			"action" character varying(32) NOT NULL DEFAULT '',
+			"params" jsonb,
+			"block_id" bigint NOT NULL DEFAULT '0'
+		);
+		ALTER TABLE ONLY "%[1]d_migrations" ADD CONSTRAINT "%[1]d_migrations_pkey" PRIMARY KEY (id);
+		CREATE UNIQUE INDEX "%[1]d_migrations_index_version" ON "%[1]d_migrations" (table_name, version);
+
+		DROP TABLE IF EXISTS "%[1]d_multisig_wallets"; CREATE TABLE "%[1]d_multisig_wallets" (
+			"id" bigint  NOT NULL DEFAULT '0',
+			"threshold" bigint NOT NULL DEFAULT '0',
+			"keys" text NOT NULL DEFAULT ''
+		);
+		ALTER TABLE ONLY "%[1]d_multisig_wallets" ADD CONSTRAINT "%[1]d_multisig_wallets_pkey" PRIMARY KEY (id);
 		
 		DROP TABLE IF EXISTS "%[1]d_signatures"; CREATE TABLE "%[1]d_signatures" (
 			"id" bigint  NOT NULL DEFAULT '0',
@@ -139,6 +174,7 @@ var schemaEcosystem = `DROP TABLE IF EXISTS "%[1]d_keys"; CREATE TABLE "%[1]d_ke
 		"app_id" bigint NOT NULL DEFAULT '1'
 		);
 		ALTER TABLE ONLY "%[1]d_contracts" ADD CONSTRAINT "%[1]d_contracts_pkey" PRIMARY KEY (id);
+		CREATE INDEX "%[1]d_contracts_index_search" ON "%[1]d_contracts" USING gin (to_tsvector('simple', name || ' ' || value));
 		
 		
 		DROP TABLE IF EXISTS "%[1]d_parameters";
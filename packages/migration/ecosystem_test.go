@@ -3,6 +3,8 @@ package migration
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -10,3 +12,41 @@ func TestGetEcosystemScript(t *testing.T) {
 	str := fmt.Sprintf(GetFirstEcosystemScript(), -1744264011260937456)
 	ioutil.WriteFile("/home/losaped/ecosystem_test.sql", []byte(str), 0777)
 }
+
+func TestTablesIDs(t *testing.T) {
+	re := regexp.MustCompile(`(?m)^\s*\('(-?\d+)', '(\w+)',`)
+	ids := make(map[string]string)
+	for _, script := range []string{tablesDataSQL, firstTablesDataSQL} {
+		for _, match := range re.FindAllStringSubmatch(script, -1) {
+			if name, ok := ids[match[1]]; ok {
+				t.Errorf(`tables %s and %s have the same id %s`, name, match[2], match[1])
+			}
+			ids[match[1]] = match[2]
+		}
+	}
+	if ids[`-1`] != `components` || ids[`-2`] != `migrations` || ids[`-3`] != `multisig_wallets` {
+		t.Errorf(`wrong ids of tables added after the genesis %v`, ids)
+	}
+}
+
+func TestUpgradeContracts(t *testing.T) {
+	re := regexp.MustCompile(`(?m)^\s*(?:VALUES )?\('(\d+)', '(\w+)',`)
+	ids := make(map[string]string)
+	for _, match := range re.FindAllStringSubmatch(firstEcosystemContractsSQL, -1) {
+		ids[match[1]] = match[2]
+	}
+	script := GetFirstEcosystemScript()
+	for _, contract := range firstEcosystemUpgradeContracts {
+		id := fmt.Sprint(contract.ID)
+		if name, ok := ids[id]; ok {
+			t.Errorf(`contracts %s and %s have the same id %s`, name, contract.Name, id)
+		}
+		ids[id] = contract.Name
+		if !strings.Contains(script, fmt.Sprintf(`('%s', '%s', '%s'`, id, contract.Name, sqlString(contract.Value))) {
+			t.Errorf(`contract %s is missing in genesis data`, contract.Name)
+		}
+	}
+	if len(UpgradeContracts(1))+len(UpgradeContracts(2)) != len(firstEcosystemUpgradeContracts) {
+		t.Error(`upgrade contracts must belong to the first two applications`)
+	}
+}
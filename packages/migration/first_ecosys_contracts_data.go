@@ -560,6 +560,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
 
     conditions {
         ValidateCondition($Conditions, $ecosystem_id)
+        ValidateTemplate($Value)
 
         if $ApplicationId == 0 {
             warning "Application id cannot equal 0"
@@ -580,12 +581,14 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
         Name string
         Type string
         Permissions string
+        Unique bool "optional"
+        References string "optional"
     }
     conditions {
-        ColumnCondition($TableName, $Name, $Type, $Permissions)
+        ColumnCondition($TableName, $Name, $Type, $Permissions, $Unique, $References)
     }
     action {
-        CreateColumn($TableName, $Name, $Type, $Permissions)
+        CreateColumn($TableName, $Name, $Type, $Permissions, $Unique, $References)
     }
     func rollback() {
         RollbackColumn($TableName, $Name)
@@ -676,6 +679,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
 
     conditions {
         ValidateCondition($Conditions,$ecosystem_id)
+        ValidateTemplate($Value)
 
         if DBFind("menu").Columns("id").Where("name = ?", $Name).One("id") {
             warning Sprintf( "Menu %%s already exists", $Name)
@@ -716,6 +720,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
 
     conditions {
         ValidateCondition($Conditions,$ecosystem_id)
+        ValidateTemplate($Value)
 
         if $ApplicationId == 0 {
             warning "Application id cannot equal 0"
@@ -942,6 +947,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
         if $Conditions {
             ValidateCondition($Conditions, $ecosystem_id)
         }
+        ValidateTemplate($Value)
     }
 
     action {
@@ -975,6 +981,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
         if $Conditions {
             ValidateCondition($Conditions, $ecosystem_id)
         }
+        ValidateTemplate($Value)
     }
 
     action {
@@ -1027,6 +1034,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
         if $Conditions {
             ValidateCondition($Conditions, $ecosystem_id)
         }
+        ValidateTemplate($Value)
         $ValidateCount = preparePageValidateCount($ValidateCount)
     }
 
@@ -1413,9 +1421,10 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
 ('40', 'NewUser','contract NewUser {
 	data {
 		NewPubkey string
+		KeyType int "optional"
 	}
 	conditions {
-		$newId = PubToID($NewPubkey)
+		$newId = PubToID($NewPubkey, $KeyType)
 		if $newId == 0 {
 			error "Wrong pubkey"
 		}
@@ -1427,7 +1436,7 @@ VALUES ('2', 'DelApplication', 'contract DelApplication {
 	}
 	action {
         NewMoney($newId, Str($amount), "New user deposit")
-        SetPubKey($newId, StringToBytes($NewPubkey))
+        SetPubKey($newId, StringToBytes($NewPubkey), $KeyType)
 	}
 }', %[1]d, 'ContractConditions("NodeOwnerCondition")', 1),
 ('41', 'EditEcosystemName','contract EditEcosystemName {
@@ -0,0 +1,205 @@
+package migration
+
+// firstEcosystemUpgradeContracts are the contracts of the first ecosystem which have been added
+// after 0.1.6b9. New networks get them with the genesis data, running networks install them
+// with the upgrade bundle, see UpgradeContracts
+var firstEcosystemUpgradeContracts = []UpgradeContract{
+	{114, `ImportTableData`, `contract ImportTableData {
+    data {
+        Table string
+        Data string "file"
+    }
+
+    conditions {
+        if Size($Table) == 0 {
+            warning "Table was not received"
+        }
+    }
+
+    action {
+        $result = DBImport($Table, $Data, $DataMimeType)
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{115, `NewComponent`, `contract NewComponent {
+    data {
+        ApplicationId int
+        Name string
+        Value string
+        Params string "optional"
+        Conditions string
+    }
+
+    conditions {
+        ValidateCondition($Conditions, $ecosystem_id)
+        ValidateTemplate($Value)
+        ValidateComponent($Name, $Params)
+
+        if $ApplicationId == 0 {
+            warning "Application id cannot equal 0"
+        }
+
+        if DBFind("components").Columns("id").Where("name = ?", $Name).One("id") {
+            warning Sprintf( "Component %s already exists", $Name)
+        }
+    }
+
+    action {
+        DBInsert("components", "name,value,params,conditions,app_id", $Name, $Value, $Params, $Conditions, $ApplicationId)
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{116, `EditComponent`, `contract EditComponent {
+    data {
+        Id int
+        Value string "optional"
+        Params string "optional"
+        Conditions string "optional"
+    }
+    func onlyConditions() bool {
+        return $Conditions && !$Value && !$Params
+    }
+
+    conditions {
+        RowConditions("components", $Id, onlyConditions())
+        if $Conditions {
+            ValidateCondition($Conditions, $ecosystem_id)
+        }
+        ValidateTemplate($Value)
+        if $Params {
+            var name string
+            name = DBFind("components").Columns("name").WhereId($Id).One("name")
+            ValidateComponent(name, $Params)
+        }
+    }
+
+    action {
+        var pars, vals array
+        if $Value {
+            pars[0] = "value"
+            vals[0] = $Value
+        }
+        if $Params {
+            pars = Append(pars, "params")
+            vals = Append(vals, $Params)
+        }
+        if $Conditions {
+            pars = Append(pars, "conditions")
+            vals = Append(vals, $Conditions)
+        }
+        if Len(vals) > 0 {
+            DBUpdate("components", $Id, Join(pars, ","), vals...)
+        }
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{117, `MigrateTable`, `contract MigrateTable {
+    data {
+        TableName string
+        Version int
+        Action string
+        Column string
+        NewName string "optional"
+        Type string "optional"
+        Conversion string "optional"
+        Format string "optional"
+    }
+    conditions {
+        MigrationConditions($TableName, $Version, $Action, $Column, $NewName, $Type, $Conversion, $Format)
+    }
+    action {
+        Migrate($TableName, $Version, $Action, $Column, $NewName, $Type, $Conversion, $Format)
+    }
+    func rollback() {
+        RollbackMigration($TableName, $Version)
+    }
+    func price() int {
+        return SysParamInt("column_price")
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{118, `consensus`, `contract consensus {
+    data {
+      Value string
+    }
+
+    conditions {
+      if Size($Value) == 0 {
+        warning "Value was not received"
+      }
+      if $Value != "roundrobin" && $Value != "bft" {
+        warning "Value must be roundrobin or bft"
+      }
+    }
+}`, `ContractConditions("MainCondition")`, 2},
+	{119, `NewMultisigWallet`, `contract NewMultisigWallet {
+    data {
+        Keys array
+        Threshold int
+    }
+
+    conditions {
+        $wallet = MultisigAddress($Keys, $Threshold)
+        if DBFind("multisig_wallets").Columns("id").WhereId($wallet).One("id") {
+            warning Sprintf("Multisig wallet %s already exists", IdToAddress($wallet))
+        }
+    }
+
+    action {
+        DBInsert("multisig_wallets", "id,threshold,keys", $wallet, $Threshold, MultisigKeys($Keys))
+        if DBFind("keys").Columns("id").WhereId($wallet).One("id") == nil {
+            DBInsert("keys", "id", $wallet)
+        }
+        $result = IdToAddress($wallet)
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{120, `node_key_overlap`, `contract node_key_overlap {
+    data {
+      Value string
+    }
+
+    conditions {
+      if Size($Value) == 0 {
+        warning "Value was not received"
+      }
+      if Int($Value) < 0 {
+        warning "Value must be greater or equal to 0"
+      }
+    }
+}`, `ContractConditions("MainCondition")`, 2},
+	{121, `RotateNodeKey`, `contract RotateNodeKey {
+    data {
+        NewPubKey string
+    }
+
+    conditions {
+        ContractConditions("NodeOwnerCondition")
+    }
+
+    action {
+        RotateNodeKey($NewPubKey)
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+	{122, `EditLangs`, `contract EditLangs {
+    data {
+        IdList array
+        TransList array
+    }
+
+    conditions {
+        EvalCondition("parameters", "changing_language", "value")
+        if Len($IdList) != Len($TransList) {
+            warning "The count of translations must be equal to the count of identifiers"
+        }
+    }
+
+    action {
+        var i int
+        var lang map
+        while i < Len($IdList) {
+            lang = DBFind("languages").Where("id=?", $IdList[i]).Row()
+            if !lang {
+                error Sprintf("Language resource %v does not exist", $IdList[i])
+            }
+            EditLanguage(Int($IdList[i]), lang["name"], $TransList[i], Int(lang["app_id"]))
+            i = i + 1
+        }
+    }
+}`, `ContractConditions("MainCondition")`, 1},
+}
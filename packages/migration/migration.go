@@ -13,6 +13,42 @@ var migrations = []*migration{
 
 	// Initial schema
 	&migration{"0.1.6b9", migrationInitialSchema},
+
+	// Index of rollback records by blocks
+	&migration{"0.1.7.1", migrationRollbackTxBlock},
+
+	// Change data capture of tables
+	&migration{"0.1.7.2", migrationChanges},
+
+	// Full-text indexes of ecosystem sources
+	&migration{"0.1.7.3", migrationSearchIndexes},
+
+	// Template components of ecosystems
+	&migration{"0.1.7.4", migrationComponents},
+
+	// Versioned migrations of ecosystem tables
+	&migration{"0.1.7.5", migrationTableMigrations},
+
+	// Consensus algorithm of full nodes
+	&migration{"0.1.7.6", migrationConsensus},
+
+	// Finality attestations of blocks
+	&migration{"0.1.7.7", migrationBlockFinality},
+
+	// Log of reorgs of blockchain
+	&migration{"0.1.7.8", migrationReorgs},
+
+	// Nonces of keys
+	&migration{"0.1.7.9", migrationKeyNonces},
+
+	// Multisig wallets and their pending transactions
+	&migration{"0.1.7.10", migrationMultisig},
+
+	// Types of keys
+	&migration{"0.1.7.11", migrationKeyTypes},
+
+	// History of keys of full nodes
+	&migration{"0.1.7.12", migrationNodeKeyRotation},
 }
 
 type migration struct {
@@ -3,6 +3,8 @@ package migration
 import (
 	"testing"
 
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
 	version "github.com/hashicorp/go-version"
 )
 
@@ -62,3 +64,42 @@ func TestMockMigration(t *testing.T) {
 		t.Errorf("current version expected 0.0.2 get %s", v)
 	}
 }
+
+func TestMigrationsOrder(t *testing.T) {
+	prev := version.Must(version.NewVersion(migrations[0].version))
+	for _, m := range migrations[1:] {
+		ver := version.Must(version.NewVersion(m.version))
+		if !prev.LessThan(ver) {
+			t.Errorf("migration %s must be greater than %s", m.version, prev)
+		}
+		prev = ver
+	}
+	if last := migrations[len(migrations)-1].version; last != consts.VERSION {
+		t.Errorf("last migration %s must be equal to the version %s", last, consts.VERSION)
+	}
+}
+
+func TestUpgradeMigration(t *testing.T) {
+	appVer := version.Must(version.NewVersion(consts.VERSION))
+
+	db := createDBMock("0.1.6b9")
+	if err := migrate(db, appVer, migrations); err != nil {
+		t.Fatal(err)
+	}
+	var applied []string
+	for _, m := range migrations {
+		if m.version == "0.1.6b9" {
+			applied = nil
+			continue
+		}
+		applied = append(applied, m.version)
+	}
+	if len(db.versions)-1 != len(applied) {
+		t.Fatalf("expected %d applied migrations, got %v", len(applied), db.versions[1:])
+	}
+	for i, v := range applied {
+		if db.versions[i+1] != v {
+			t.Errorf("expected migration %s, got %s", v, db.versions[i+1])
+		}
+	}
+}
@@ -1791,13 +1791,13 @@ Div(content-wrapper){
 
 	DBFind(#pre_name#).Count(count)
 	If(#page#>0){
-		SetVar(prev_page,Calculate(#page#-1)
+		SetVar(prev_page,Calculate(#page#-1))
 	}.Else{
 		SetVar(page,0).(prev_page,0)
 	}
 	SetVar(per_page,25).(off,Calculate(#page#*#per_page#)).(last_page,Calculate(#count#/#per_page#)).(next_page,#last_page#)
 	If(#count#>Calculate(#off#+#per_page#)){
-		SetVar(next_page,Calculate(#page#+1)
+		SetVar(next_page,Calculate(#page#+1))
 	}
 	Div(button-group){
 		If(#page#>0){
@@ -1,5 +1,8 @@
 package migration
 
+// The tables which have been added after the genesis have the negative identifiers. They are the same
+// in the new ecosystems and in the ecosystems which are updated by migrations, and they don't change
+// the identifiers of the tables which are created by users.
 var tablesDataSQL = `INSERT INTO "%[1]d_tables" ("id", "name", "permissions","columns", "conditions") VALUES 
 ('1', 'contracts', '{"insert": "ContractConditions(\"MainCondition\")", "update": "ContractConditions(\"MainCondition\")", "new_column": "ContractConditions(\"MainCondition\")"}', 
 '{"name": "false", 
@@ -16,7 +19,8 @@ var tablesDataSQL = `INSERT INTO "%[1]d_tables" ("id", "name", "permissions","co
 	  "maxpay": "ContractConditions(\"MainCondition\")",
 	  "deleted": "ContractConditions(\"MainCondition\")",
 	  "blocked": "ContractConditions(\"MainCondition\")",
-	  "multi": "ContractConditions(\"MainCondition\")"}', 
+	  "multi": "ContractConditions(\"MainCondition\")",
+	  "key_type": "ContractConditions(\"MainCondition\")"}',
 	'ContractAccess("@1EditTable")'),
 	('3', 'history', 
 	'{"insert": "ContractConditions(\"NodeOwnerCondition\")", "update": "ContractConditions(\"MainCondition\")", 
@@ -161,5 +165,29 @@ var tablesDataSQL = `INSERT INTO "%[1]d_tables" ("id", "name", "permissions","co
 		'{"key": "false",
 			"value": "true",
 			"member_id": "false"}',
+		'ContractConditions("MainCondition")'),
+	('-1', 'components',
+		'{"insert": "ContractConditions(\"MainCondition\")", "update": "ContractConditions(\"MainCondition\")",
+			"new_column": "ContractConditions(\"MainCondition\")"}',
+		'{"name": "ContractConditions(\"MainCondition\")",
+			"value": "ContractConditions(\"MainCondition\")",
+			"params": "ContractConditions(\"MainCondition\")",
+			"conditions": "ContractConditions(\"MainCondition\")",
+			"app_id": "ContractConditions(\"MainCondition\")"}',
+		'ContractAccess("@1EditTable")'),
+	('-2', 'migrations',
+		'{"insert": "false", "update": "false",
+			"new_column": "false"}',
+		'{"table_name": "false",
+			"version": "false",
+			"action": "false",
+			"params": "false",
+			"block_id": "false"}',
+		'ContractConditions("MainCondition")'),
+	('-3', 'multisig_wallets',
+		'{"insert": "ContractAccess(\"@1NewMultisigWallet\")", "update": "false",
+			"new_column": "false"}',
+		'{"threshold": "false",
+			"keys": "false"}',
 		'ContractConditions("MainCondition")');
 `
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpgradeContract is the contract of the first ecosystem which is missing on the networks
+// that have been started before it was added. Migrations of nodes change only the schema,
+// so such contracts are installed by the transactions of the ecosystem.
+type UpgradeContract struct {
+	ID         int64
+	Name       string
+	Value      string
+	Conditions string
+	AppID      int64
+}
+
+// UpgradeContracts returns the contracts of the application of the first ecosystem which have
+// been added after 0.1.6b9
+func UpgradeContracts(appID int64) []UpgradeContract {
+	list := make([]UpgradeContract, 0)
+	for _, contract := range firstEcosystemUpgradeContracts {
+		if contract.AppID == appID {
+			list = append(list, contract)
+		}
+	}
+	return list
+}
+
+func sqlString(value string) string {
+	return strings.Replace(strings.Replace(value, `'`, `''`, -1), `%`, `%%`, -1)
+}
+
+// firstEcosystemUpgradeSQL returns the query which inserts the upgrade contracts into the genesis data
+func firstEcosystemUpgradeSQL() string {
+	values := make([]string, len(firstEcosystemUpgradeContracts))
+	for i, contract := range firstEcosystemUpgradeContracts {
+		values[i] = fmt.Sprintf(`('%d', '%s', '%s', %%[1]d, '%s', %d)`, contract.ID, sqlString(contract.Name),
+			sqlString(contract.Value), sqlString(contract.Conditions), contract.AppID)
+	}
+	return "INSERT INTO \"1_contracts\" (id, name, value, wallet_id, conditions, app_id)\nVALUES " +
+		strings.Join(values, ",\n") + ";\n"
+}
@@ -462,12 +462,14 @@ var contractsDataSQL = `INSERT INTO "%[1]d_contracts" ("id", "name", "value", "c
 			  Name        string
 			  Type        string
 			  Permissions string
+			  Unique      bool "optional"
+			  References  string "optional"
 		  }
 		  conditions {
-			  ColumnCondition($TableName, $Name, $Type, $Permissions)
+			  ColumnCondition($TableName, $Name, $Type, $Permissions, $Unique, $References)
 		  }
 		  action {
-			  CreateColumn($TableName, $Name, $Type, $Permissions)
+			  CreateColumn($TableName, $Name, $Type, $Permissions, $Unique, $References)
 		  }
 	  }', 'ContractConditions("MainCondition")'),
 	  ('17','EditColumn','contract EditColumn {
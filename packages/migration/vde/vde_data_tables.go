@@ -71,6 +71,7 @@ INSERT INTO "%[1]d_tables" ("id", "name", "permissions","columns", "conditions")
 		'{"pub": "ContractConditions(\"MainCondition\")",
 			"multi": "ContractConditions(\"MainCondition\")",
 			"deleted": "ContractConditions(\"MainCondition\")",
-			"blocked": "ContractConditions(\"MainCondition\")"}',
+			"blocked": "ContractConditions(\"MainCondition\")",
+			"key_type": "ContractConditions(\"MainCondition\")"}',
 		'ContractConditions("MainCondition")');	 
 `
@@ -26,7 +26,8 @@ var schemaVDE = `
 	"pub" bytea  NOT NULL DEFAULT '',
 	"multi" bigint NOT NULL DEFAULT '0',
 	"deleted" bigint NOT NULL DEFAULT '0',
-	"blocked" bigint NOT NULL DEFAULT '0'
+	"blocked" bigint NOT NULL DEFAULT '0',
+	"key_type" bigint NOT NULL DEFAULT '0'
 	);
 	ALTER TABLE ONLY "%[1]d_keys" ADD CONSTRAINT "%[1]d_keys_pkey" PRIMARY KEY (id);
 
@@ -14,14 +14,14 @@ type BatchModel interface {
 }
 
 // BatchInsert create and execute batch queries from rows splitted by maxBatchRows and fields
-func BatchInsert(rows []BatchModel, fields []string) error {
+func BatchInsert(transaction *DbTransaction, rows []BatchModel, fields []string) error {
 	queries, values, err := batchQueue(rows, fields)
 	if err != nil {
 		return err
 	}
 
 	for i := 0; i < len(queries); i++ {
-		if err := DBConn.Exec(queries[i], values[i]...).Error; err != nil {
+		if err := GetDB(transaction).Exec(queries[i], values[i]...).Error; err != nil {
 			return err
 		}
 	}
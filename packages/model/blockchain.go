@@ -13,6 +13,8 @@ type Block struct {
 	NodePosition  int64  `gorm:"not null"`
 	Time          int64  `gorm:"not null"`
 	Tx            int32  `gorm:"not null"`
+	Final         bool   `gorm:"not null"`
+	Attestations  []byte
 }
 
 // TableName returns name of table
@@ -30,6 +32,29 @@ func (b *Block) Get(blockID int64) (bool, error) {
 	return isFound(DBConn.Where("id = ?", blockID).First(b))
 }
 
+// GetLastFinal returns the last final block
+func (b *Block) GetLastFinal() (bool, error) {
+	return isFound(DBConn.Where("final = true").Order("id DESC").First(b))
+}
+
+// IsFinal returns true if the block with the specified hash is final
+func (b *Block) IsFinal(blockID int64, hash []byte) (bool, error) {
+	var count int64
+	err := DBConn.Model(&Block{}).Where("id = ? AND hash = ? AND final = true", blockID, hash).Count(&count).Error
+	return count > 0, err
+}
+
+// SetFinal saves the attestations of the final block and marks it with its ancestors as final
+func (b *Block) SetFinal(transaction *DbTransaction, blockID int64, hash, attestations []byte) (bool, error) {
+	query := GetDB(transaction).Model(&Block{}).Where("id = ? AND hash = ?", blockID, hash).
+		Updates(map[string]interface{}{"final": true, "attestations": attestations})
+	if query.Error != nil || query.RowsAffected == 0 {
+		return false, query.Error
+	}
+	err := GetDB(transaction).Exec(`UPDATE "block_chain" SET "final" = true WHERE "id" < ? AND NOT "final"`, blockID).Error
+	return err == nil, err
+}
+
 // GetMaxBlock returns last block existence
 func (b *Block) GetMaxBlock() (bool, error) {
 	return isFound(DBConn.Last(b))
@@ -0,0 +1,46 @@
+package model
+
+// The types of change events
+const (
+	ChangeInsert   = "insert"
+	ChangeUpdate   = "update"
+	ChangeRollback = "rollback"
+)
+
+// Change is model of the captured change of the table row
+type Change struct {
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+	TxHash    []byte `gorm:"not null" json:"tx_hash"`
+	NameTable string `gorm:"not null;size:255;column:table_name" json:"table_name"`
+	TableID   string `gorm:"not null;size:255" json:"table_id"`
+	Event     string `gorm:"not null;size:16" json:"event"`
+	Old       string `gorm:"not null" json:"old"`
+	New       string `gorm:"not null" json:"new"`
+}
+
+// TableName returns name of table
+func (Change) TableName() string {
+	return "changes"
+}
+
+// Create is creating record of model
+func (c *Change) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(c).Error
+}
+
+// GetChanges returns changes of the tables with prefix which have been made since blockID.
+// The changes are sorted in the order they have been made, afterID is used for pagination.
+func GetChanges(prefix string, blockID, afterID int64, limit int) ([]Change, error) {
+	var changes []Change
+	err := DBConn.Where("table_name LIKE ? AND block_id >= ? AND id > ?", prefix+"\\_%", blockID, afterID).
+		Order("id asc").Limit(limit).Find(&changes).Error
+	return changes, err
+}
+
+// GetChangesAfterID returns changes of all tables which have been made after the change afterID
+func GetChangesAfterID(afterID int64, limit int) ([]Change, error) {
+	var changes []Change
+	err := DBConn.Where("id > ?", afterID).Order("id asc").Limit(limit).Find(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,34 @@
+package model
+
+// Component is model of the user-defined template component
+type Component struct {
+	tableName  string
+	ID         int64  `gorm:"primary_key;not null" json:"id"`
+	Name       string `gorm:"not null" json:"name"`
+	Value      string `gorm:"not null" json:"value"`
+	Params     string `gorm:"not null" json:"params"`
+	Conditions string `gorm:"not null" json:"conditions"`
+	AppID      int64  `gorm:"not null" json:"app_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (c *Component) SetTablePrefix(prefix string) {
+	c.tableName = prefix + "_components"
+}
+
+// TableName returns name of table
+func (c Component) TableName() string {
+	return c.tableName
+}
+
+// Get is retrieving model from database
+func (c *Component) Get(name string) (bool, error) {
+	return isFound(DBConn.Where("name = ?", name).First(c))
+}
+
+// GetAll returns all components of the ecosystem
+func (c *Component) GetAll() ([]Component, error) {
+	var components []Component
+	err := DBConn.Table(c.TableName()).Order("id").Find(&components).Error
+	return components, err
+}
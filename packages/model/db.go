@@ -80,6 +80,21 @@ func StartTransaction() (*DbTransaction, error) {
 	}, nil
 }
 
+// StartSnapshotTransaction starts the read-only transaction which sees the snapshot of the database
+// as of its first query, so several queries return the consistent data
+func StartSnapshotTransaction() (*DbTransaction, error) {
+	tx, err := StartTransaction()
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.conn.Exec(`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`).Error; err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting isolation level of transaction")
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
 // Rollback is transaction rollback
 func (tr *DbTransaction) Rollback() {
 	tr.conn.Rollback()
@@ -170,8 +185,18 @@ func Update(transaction *DbTransaction, tblname, set, where string) error {
 }
 
 // Delete is deleting table rows
-func Delete(tblname, where string) error {
-	return DBConn.Exec(`DELETE FROM "` + tblname + `" ` + where).Error
+func Delete(transaction *DbTransaction, tblname, where string) error {
+	return GetDB(transaction).Exec(`DELETE FROM "` + tblname + `" ` + where).Error
+}
+
+// DeferConstraints sets the checking of deferrable constraints at the end of the transaction
+// or checks them immediately if deferred is false
+func DeferConstraints(transaction *DbTransaction, deferred bool) error {
+	mode := `IMMEDIATE`
+	if deferred {
+		mode = `DEFERRED`
+	}
+	return GetDB(transaction).Exec(`SET CONSTRAINTS ALL ` + mode).Error
 }
 
 // GetColumnCount is counting rows in table
@@ -220,8 +245,93 @@ func AlterTableAddColumn(transaction *DbTransaction, tableName, columnName, colu
 }
 
 // AlterTableDropColumn is dropping column from table
-func AlterTableDropColumn(tableName, columnName string) error {
-	return DBConn.Exec(`ALTER TABLE "` + tableName + `" DROP COLUMN "` + columnName + `"`).Error
+func AlterTableDropColumn(transaction *DbTransaction, tableName, columnName string) error {
+	return GetDB(transaction).Exec(`ALTER TABLE "` + tableName + `" DROP COLUMN "` + columnName + `"`).Error
+}
+
+// AlterTableRenameColumn is renaming column of table
+func AlterTableRenameColumn(transaction *DbTransaction, tableName, columnName, newName string) error {
+	return GetDB(transaction).Exec(`ALTER TABLE "` + tableName + `" RENAME COLUMN "` + columnName + `" TO "` + newName + `"`).Error
+}
+
+// AlterTableColumnType is changing type of table column, the values are cast to the new type
+// or they are cleared if clear is true. The default value and NOT NULL constraint of the column are dropped
+func AlterTableColumnType(transaction *DbTransaction, tableName, columnName, sqlType string, clear bool) error {
+	using := `"` + columnName + `"::` + sqlType
+	if clear {
+		using = `NULL`
+	}
+	alter := `ALTER TABLE "` + tableName + `" ALTER COLUMN "` + columnName + `" `
+	for _, query := range []string{alter + `DROP DEFAULT`, alter + `DROP NOT NULL`,
+		alter + `TYPE ` + sqlType + ` USING ` + using} {
+		if err := GetDB(transaction).Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertTableColumn changes the type of table column to text and converts the values with the function.
+// The format is passed to the function as the bound argument.
+func ConvertTableColumn(transaction *DbTransaction, tableName, columnName, function, format string) error {
+	if err := AlterTableColumnType(transaction, tableName, columnName, `text`, false); err != nil {
+		return err
+	}
+	return GetDB(transaction).Exec(`UPDATE "`+tableName+`" SET "`+columnName+`" = `+function+`("`+
+		columnName+`", ?)::text`, format).Error
+}
+
+// SplitColumnType splits the definition of column into the type, NOT NULL flag and the default value
+func SplitColumnType(columnType string) (sqlType string, notNull bool, def string) {
+	sqlType = columnType
+	if off := strings.Index(sqlType, ` DEFAULT `); off > 0 {
+		sqlType, def = sqlType[:off], sqlType[off+len(` DEFAULT `):]
+	}
+	if strings.HasSuffix(sqlType, ` NOT NULL`) {
+		sqlType, notNull = strings.TrimSuffix(sqlType, ` NOT NULL`), true
+	}
+	return
+}
+
+// AlterTableColumnConstraints sets the default value and NOT NULL constraint of the column from columnType
+func AlterTableColumnConstraints(transaction *DbTransaction, tableName, columnName, columnType string) error {
+	_, notNull, def := SplitColumnType(columnType)
+	alter := `ALTER TABLE "` + tableName + `" ALTER COLUMN "` + columnName + `" `
+	queries := make([]string, 0, 3)
+	if len(def) > 0 {
+		if notNull {
+			queries = append(queries, `UPDATE "`+tableName+`" SET "`+columnName+`" = `+def+` WHERE "`+columnName+`" IS NULL`)
+		}
+		queries = append(queries, alter+`SET DEFAULT `+def)
+	}
+	if notNull {
+		queries = append(queries, alter+`SET NOT NULL`)
+	}
+	for _, query := range queries {
+		if err := GetDB(transaction).Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetColumnDefinition returns the type of column with NOT NULL constraint and the default value
+func GetColumnDefinition(transaction *DbTransaction, tableName, columnName string) (string, error) {
+	row, err := GetOneRowTransaction(transaction, `SELECT format_type(a.atttypid, a.atttypmod) AS type,
+			CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END AS notnull,
+			coalesce(pg_get_expr(d.adbin, d.adrelid), '') AS def
+		FROM pg_attribute a
+		INNER JOIN pg_class t ON t.oid = a.attrelid AND t.relkind = 'r'
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE t.relname = ? AND a.attname = ? AND NOT a.attisdropped`, tableName, columnName).String()
+	if err != nil || len(row) == 0 {
+		return ``, err
+	}
+	definition := row[`type`] + row[`notnull`]
+	if len(row[`def`]) > 0 {
+		definition += ` DEFAULT ` + row[`def`]
+	}
+	return definition, nil
 }
 
 // CreateIndex is creating index on table column
@@ -229,6 +339,11 @@ func CreateIndex(transaction *DbTransaction, indexName, tableName, onColumn stri
 	return GetDB(transaction).Exec(`CREATE INDEX "` + indexName + `_index" ON "` + tableName + `" (` + onColumn + `)`).Error
 }
 
+// DropIndex is dropping index which has been created by CreateIndex
+func DropIndex(transaction *DbTransaction, indexName string) error {
+	return GetDB(transaction).Exec(`DROP INDEX IF EXISTS "` + indexName + `_index"`).Error
+}
+
 // GetColumnDataTypeCharMaxLength is returns max length of table column
 func GetColumnDataTypeCharMaxLength(tableName, columnName string) (map[string]string, error) {
 	return GetOneRow(`select data_type,character_maximum_length from
@@ -244,6 +359,14 @@ func GetAllColumnTypes(tblname string) ([]map[string]string, error) {
 		ORDER BY ordinal_position ASC`, -1, tblname)
 }
 
+// GetAllColumnsInfo returns the types, the nullability and the maximum lengths of columns of the table
+func GetAllColumnsInfo(tblname string) ([]map[string]string, error) {
+	return GetAll(`SELECT column_name, data_type, is_nullable, coalesce(character_maximum_length, 0) AS max_length
+		FROM information_schema.columns
+		WHERE table_name = ?
+		ORDER BY ordinal_position ASC`, -1, tblname)
+}
+
 // GetColumnType is returns type of column
 func GetColumnType(tblname, column string) (itype string, err error) {
 	coltype, err := GetColumnDataTypeCharMaxLength(tblname, column)
@@ -300,6 +423,41 @@ func IsIndex(tblname, column string) (bool, error) {
 	return len(row) > 0 && row[`column_name`] == column, err
 }
 
+// IsConstrainedColumn returns true if the column has unique or foreign key constraints
+func IsConstrainedColumn(transaction *DbTransaction, tblname, column string) (bool, error) {
+	var exists bool
+	err := GetDB(transaction).Raw(`SELECT EXISTS(SELECT 1 FROM information_schema.key_column_usage k
+		INNER JOIN information_schema.table_constraints c ON c.constraint_name = k.constraint_name
+		WHERE k.table_name = ? AND k.column_name = ? AND c.constraint_type IN ('UNIQUE', 'FOREIGN KEY'))`,
+		tblname, column).Row().Scan(&exists)
+	return exists, err
+}
+
+// GetColumnConstraints returns the unique flag and the referenced table of the column
+func GetColumnConstraints(tblname, column string) (unique bool, references string, err error) {
+	rows, err := GetAll(`SELECT c.constraint_type AS type, coalesce(u.table_name, '') AS ref
+		FROM information_schema.table_constraints c
+		INNER JOIN information_schema.key_column_usage k ON k.constraint_name = c.constraint_name AND k.table_name = c.table_name
+		LEFT JOIN information_schema.constraint_column_usage u ON u.constraint_name = c.constraint_name
+			AND c.constraint_type = 'FOREIGN KEY'
+		WHERE c.table_name = ? AND k.column_name = ? AND c.constraint_type IN ('UNIQUE', 'FOREIGN KEY')`, -1, tblname, column)
+	for _, row := range rows {
+		if row[`type`] == `UNIQUE` {
+			unique = true
+		} else if len(row[`ref`]) > 0 {
+			references = row[`ref`]
+		}
+	}
+	return
+}
+
+// IsIndexName returns true if the index with the specified name exists
+func IsIndexName(indexName string) (bool, error) {
+	var exists bool
+	err := DBConn.Raw(`SELECT EXISTS(SELECT 1 FROM pg_class WHERE relname = ? AND relkind = 'i')`, indexName).Row().Scan(&exists)
+	return exists, err
+}
+
 // ListResult is a structure for the list result
 type ListResult struct {
 	result []string
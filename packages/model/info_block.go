@@ -22,6 +22,11 @@ func (ib *InfoBlock) Get() (bool, error) {
 	return isFound(DBConn.Last(ib))
 }
 
+// GetTransaction is retrieving model from database within the transaction
+func (ib *InfoBlock) GetTransaction(transaction *DbTransaction) (bool, error) {
+	return isFound(GetDB(transaction).Last(ib))
+}
+
 // Update is update model
 func (ib *InfoBlock) Update(transaction *DbTransaction) error {
 	return GetDB(transaction).Model(&InfoBlock{}).Updates(ib).Error
@@ -0,0 +1,22 @@
+package model
+
+// KeyNonce is model of the last used nonce of the key
+type KeyNonce struct {
+	ID    int64 `gorm:"primary_key;not null"`
+	Nonce int64 `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (KeyNonce) TableName() string {
+	return "key_nonces"
+}
+
+// Get is retrieving the nonce of the key
+func (kn *KeyNonce) Get(transaction *DbTransaction, keyID int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", keyID).First(kn))
+}
+
+// Save is saving model
+func (kn *KeyNonce) Save(transaction *DbTransaction) error {
+	return GetDB(transaction).Save(kn).Error
+}
@@ -7,8 +7,8 @@ type LogTransaction struct {
 }
 
 // GetByHash returns LogTransactions existence by hash
-func (lt *LogTransaction) GetByHash(hash []byte) (bool, error) {
-	return isFound(DBConn.Where("hash = ?", hash).First(lt))
+func (lt *LogTransaction) GetByHash(transaction *DbTransaction, hash []byte) (bool, error) {
+	return isFound(GetDB(transaction).Where("hash = ?", hash).First(lt))
 }
 
 // Create is creating record of model
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+)
+
+// MultisigWallet is model of the wallet which is controlled by M-of-N keys
+type MultisigWallet struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Threshold int64  `gorm:"not null" json:"threshold"`
+	Keys      string `gorm:"not null" json:"keys"`
+}
+
+// SetTablePrefix is setting table prefix
+func (m *MultisigWallet) SetTablePrefix(prefix int64) *MultisigWallet {
+	m.tableName = converter.Int64ToStr(prefix) + "_multisig_wallets"
+	return m
+}
+
+// TableName returns name of table
+func (m MultisigWallet) TableName() string {
+	return m.tableName
+}
+
+// Get is retrieving model from database
+func (m *MultisigWallet) Get(transaction *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("id = ?", id).First(m))
+}
+
+// KeyIDs returns the list of keys of the wallet
+func (m *MultisigWallet) KeyIDs() []int64 {
+	list := make([]int64, 0)
+	for _, key := range strings.Split(m.Keys, ",") {
+		if id := converter.StringToAddress(strings.TrimSpace(key)); id != 0 {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+// HasKey returns true if the key is one of the keys of the wallet
+func (m *MultisigWallet) HasKey(keyID int64) bool {
+	for _, id := range m.KeyIDs() {
+		if id == keyID {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigTx is model of the partially signed transaction of the multisig wallet.
+// The transaction waits for the signatures of the keys of the wallet on the node
+type MultisigTx struct {
+	Hash        []byte `gorm:"primary_key;not null"`
+	WalletID    int64  `gorm:"not null"`
+	EcosystemID int64  `gorm:"not null"`
+	Data        []byte `gorm:"not null"`
+	Signers     int64  `gorm:"not null"`
+	Time        int64  `gorm:"not null"`
+	TxHash      []byte
+}
+
+// TableName returns name of table
+func (MultisigTx) TableName() string {
+	return "multisig_txs"
+}
+
+// Get is retrieving model from database
+func (m *MultisigTx) Get(hash []byte) (bool, error) {
+	return isFound(DBConn.Where("hash = ?", hash).First(m))
+}
+
+// Save is saving model
+func (m *MultisigTx) Save() error {
+	return DBConn.Save(m).Error
+}
+
+// GetMultisigTxs returns the transactions of the wallet which wait for signatures
+func GetMultisigTxs(ecosystemID, walletID int64) ([]MultisigTx, error) {
+	var txs []MultisigTx
+	query := DBConn.Where("ecosystem_id = ? AND tx_hash IS NULL", ecosystemID)
+	if walletID != 0 {
+		query = query.Where("wallet_id = ?", walletID)
+	}
+	err := query.Order("time").Find(&txs).Error
+	return txs, err
+}
+
+// DeleteExpiredMultisigTxs deletes the transactions which have been created before the time
+func DeleteExpiredMultisigTxs(time int64) error {
+	return DBConn.Where("time < ?", time).Delete(&MultisigTx{}).Error
+}
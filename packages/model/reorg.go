@@ -0,0 +1,39 @@
+package model
+
+// Reorg is model of the switch of the local chain to the chain of the host
+type Reorg struct {
+	ID          int64  `gorm:"primary_key;not null" json:"id"`
+	Time        int64  `gorm:"not null" json:"time"`
+	Host        string `gorm:"not null;size:255" json:"host"`
+	ForkBlockID int64  `gorm:"not null" json:"fork_block_id"`
+	OldBlockID  int64  `gorm:"not null" json:"old_block_id"`
+	OldHash     []byte `gorm:"not null" json:"old_hash"`
+	NewBlockID  int64  `gorm:"not null" json:"new_block_id"`
+	NewHash     []byte `gorm:"not null" json:"new_hash"`
+	Depth       int64  `gorm:"not null" json:"depth"`
+	Error       string `gorm:"not null" json:"error"`
+}
+
+// TableName returns name of table
+func (Reorg) TableName() string {
+	return "reorgs"
+}
+
+// Create is creating record of model
+func (r *Reorg) Create(transaction *DbTransaction) error {
+	return GetDB(transaction).Create(r).Error
+}
+
+// GetLast returns the last reorg
+func (r *Reorg) GetLast() (bool, error) {
+	return isFound(DBConn.Order("id desc").First(r))
+}
+
+// GetReorgsCount returns the count of reorgs, failed reorgs are counted separately
+func GetReorgsCount() (done int64, failed int64, err error) {
+	if err = DBConn.Table("reorgs").Where("error = ''").Count(&done).Error; err != nil {
+		return
+	}
+	err = DBConn.Table("reorgs").Where("error <> ''").Count(&failed).Error
+	return
+}
@@ -187,14 +187,59 @@ func GetAllTransaction(transaction *DbTransaction, query string, countRows int,
 	return result, nil
 }
 
+// ProcessRows executes the query and calls fn for every row without loading all rows into memory.
+// The columns are passed in the order of the query, NULL values are returned as empty strings.
+func ProcessRows(transaction *DbTransaction, query string, fn func(columns []string, row map[string]string) error, args ...interface{}) error {
+	return ProcessRowsHeader(transaction, query, nil, fn, args...)
+}
+
+// ProcessRowsHeader is the same as ProcessRows but it calls header with the columns of the query
+// before the rows. The header is called even if the query has returned no rows.
+func ProcessRowsHeader(transaction *DbTransaction, query string, header func(columns []string) error,
+	fn func(columns []string, row map[string]string) error, args ...interface{}) error {
+	rows, err := GetDB(transaction).Raw(query, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("%s in query %s %s", err, query, args)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("%s in query %s %s", err, query, args)
+	}
+	if header != nil {
+		if err = header(columns); err != nil {
+			return err
+		}
+	}
+	values := make([][]byte, len(columns))
+	scanArgs := make([]interface{}, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(scanArgs...); err != nil {
+			return fmt.Errorf("%s in query %s %s", err, query, args)
+		}
+		row := make(map[string]string, len(columns))
+		for i, col := range values {
+			row[columns[i]] = string(col)
+		}
+		if err = fn(columns, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // GetAll returns all transaction
 func GetAll(query string, countRows int, args ...interface{}) ([]map[string]string, error) {
-	return GetAllTransaction(nil, query, countRows, args)
+	return GetAllTransaction(nil, query, countRows, args...)
 }
 
 // GetAllTx returns all tx's
 func GetAllTx(transaction *DbTransaction, query string, countRows int, args ...interface{}) ([]map[string]string, error) {
-	return GetAllTransaction(transaction, query, countRows, args)
+	return GetAllTransaction(transaction, query, countRows, args...)
 }
 
 // GetOneRowTransaction returns one row from transactions
@@ -1,5 +1,9 @@
 package model
 
+import (
+	"fmt"
+)
+
 // RollbackTx is model
 type RollbackTx struct {
 	ID        int64  `gorm:"primary_key;not null" json:"-"`
@@ -37,6 +41,26 @@ func (rt *RollbackTx) GetRollbackTxsByTableIDAndTableName(tableID, tableName str
 	return rollbackTx, nil
 }
 
+// GetRollbackTxsAfterBlock returns records of rollback by table name which have been written after blockID.
+// If tableID is empty then the records of all rows are returned. Records are sorted from the newest to the oldest.
+func (rt *RollbackTx) GetRollbackTxsAfterBlock(transaction *DbTransaction, tableName, tableID string, blockID int64, limit int) ([]RollbackTx, error) {
+	var rollbackTransactions []RollbackTx
+	query := GetDB(transaction).Where("table_name = ? AND block_id > ?", tableName, blockID)
+	if len(tableID) > 0 {
+		query = query.Where("table_id = ?", tableID)
+	}
+	err := query.Order("id desc").Limit(limit).Find(&rollbackTransactions).Error
+	return rollbackTransactions, err
+}
+
+// IsChangedAfterBlock returns true if any of the tables has been changed after blockID
+func (rt *RollbackTx) IsChangedAfterBlock(tables []string, blockID int64) (bool, error) {
+	var changed bool
+	err := DBConn.Raw("SELECT EXISTS(SELECT 1 FROM rollback_tx WHERE table_name IN (?) AND block_id > ?)",
+		tables, blockID).Row().Scan(&changed)
+	return changed, err
+}
+
 // DeleteByHash is deleting rollbackTx by hash
 func (rt *RollbackTx) DeleteByHash(dbTransaction *DbTransaction) error {
 	return GetDB(dbTransaction).Exec("DELETE FROM rollback_tx WHERE tx_hash = ?", rt.TxHash).Error
@@ -56,3 +80,21 @@ func (rt *RollbackTx) Create(transaction *DbTransaction) error {
 func (rt *RollbackTx) Get(dbTransaction *DbTransaction, transactionHash []byte, tableName string) (bool, error) {
 	return isFound(GetDB(dbTransaction).Where("tx_hash = ? AND table_name = ?", transactionHash, tableName).First(rt))
 }
+
+// FieldValue implementing BatchModel interface
+func (rt RollbackTx) FieldValue(fieldName string) (interface{}, error) {
+	switch fieldName {
+	case "block_id":
+		return rt.BlockID, nil
+	case "tx_hash":
+		return rt.TxHash, nil
+	case "table_name":
+		return rt.NameTable, nil
+	case "table_id":
+		return rt.TableID, nil
+	case "data":
+		return rt.Data, nil
+	default:
+		return nil, fmt.Errorf("Unknown field '%s' for RollbackTx", fieldName)
+	}
+}
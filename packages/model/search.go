@@ -0,0 +1,40 @@
+package model
+
+import (
+	"fmt"
+)
+
+// SearchSources contains the searchable tables of the ecosystem and their text columns
+var SearchSources = map[string]string{
+	"pages":     "value",
+	"menu":      "value",
+	"blocks":    "value",
+	"contracts": "value",
+	"languages": "res",
+}
+
+// SearchResult is the record which matches the search query
+type SearchResult struct {
+	Source  string  `json:"source"`
+	ID      int64   `json:"id"`
+	Name    string  `json:"name"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// SearchTable returns records of the source table which match the full-text query.
+// The expression of the search is the same as in the full-text index of the table.
+func SearchTable(prefix, source, query string, limit int) ([]SearchResult, error) {
+	column, ok := SearchSources[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown search source %s", source)
+	}
+	vector := fmt.Sprintf(`to_tsvector('simple', name || ' ' || %s)`, column)
+	sql := fmt.Sprintf(`SELECT '%[1]s' AS source, id, name, ts_headline('simple', %[2]s, q) AS snippet,
+		ts_rank(%[3]s, q) AS rank FROM "%[4]s_%[1]s", plainto_tsquery('simple', ?) q
+		WHERE %[3]s @@ q ORDER BY rank DESC, id LIMIT %[5]d`, source, column, vector, prefix, limit)
+
+	var result []SearchResult
+	err := DBConn.Raw(sql, query).Scan(&result).Error
+	return result, err
+}
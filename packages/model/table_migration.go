@@ -0,0 +1,35 @@
+package model
+
+// TableMigration is model of the applied migration of the ecosystem table
+type TableMigration struct {
+	tableName string
+	ID        int64  `gorm:"primary_key;not null" json:"id"`
+	Table     string `gorm:"not null;size:255;column:table_name" json:"table_name"`
+	Version   int64  `gorm:"not null" json:"version"`
+	Action    string `gorm:"not null;size:32" json:"action"`
+	Params    string `gorm:"not null;type:jsonb(PostgreSQL)" json:"params"`
+	BlockID   int64  `gorm:"not null" json:"block_id"`
+}
+
+// SetTablePrefix is setting table prefix
+func (m *TableMigration) SetTablePrefix(prefix string) {
+	m.tableName = prefix + "_migrations"
+}
+
+// TableName returns name of table
+func (m TableMigration) TableName() string {
+	return m.tableName
+}
+
+// Get is retrieving the migration of the table by version
+func (m *TableMigration) Get(transaction *DbTransaction, table string, version int64) (bool, error) {
+	return isFound(GetDB(transaction).Where("table_name = ? AND version = ?", table, version).First(m))
+}
+
+// LastVersion returns the version of the last migration of the table
+func (m *TableMigration) LastVersion(transaction *DbTransaction, table string) (int64, error) {
+	var version int64
+	err := GetDB(transaction).Raw(`SELECT coalesce(max(version), 0) FROM "`+m.TableName()+`" WHERE table_name = ?`,
+		table).Row().Scan(&version)
+	return version, err
+}
@@ -35,21 +35,6 @@ func GetAllTransactions(limit int) (*[]Transaction, error) {
 	return transactions, nil
 }
 
-// GetAllUnusedTransactions is retrieving all unused transactions
-func GetAllUnusedTransactions(limit int) ([]*Transaction, error) {
-	var transactions []*Transaction
-
-	query := DBConn.Where("used = ?", "0").Order("high_rate DESC")
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-
-	if err := query.Find(&transactions).Error; err != nil {
-		return nil, err
-	}
-	return transactions, nil
-}
-
 // GetAllUnsentTransactions is retrieving all unset transactions
 func GetAllUnsentTransactions() (*[]Transaction, error) {
 	transactions := new([]Transaction)
@@ -77,6 +62,23 @@ func GetTransactionsCount(hash []byte) (int64, error) {
 	return rowsCount, nil
 }
 
+// GetUnusedTransactionHashes returns the hashes of all verified unused transactions
+func GetUnusedTransactionHashes() ([][]byte, error) {
+	var hashes [][]byte
+	err := DBConn.Table("transactions").Where("used = 0 AND verified = 1").Pluck("hash", &hashes).Error
+	return hashes, err
+}
+
+// GetTransactionsByHashes returns the verified unused transactions with the hashes
+func GetTransactionsByHashes(hashes [][]byte) ([]*Transaction, error) {
+	var transactions []*Transaction
+	if len(hashes) == 0 {
+		return transactions, nil
+	}
+	err := DBConn.Where("hash IN (?) AND used = 0 AND verified = 1", hashes).Find(&transactions).Error
+	return transactions, err
+}
+
 // DeleteLoopedTransactions deleting lopped transactions
 func DeleteLoopedTransactions() (int64, error) {
 	query := DBConn.Exec("DELETE FROM transactions WHERE used = 0 AND counter > 10")
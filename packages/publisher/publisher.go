@@ -63,6 +63,24 @@ func Write(userID int64, data string) (bool, error) {
 	return publisher.Publish("client"+strconv.FormatInt(userID, 10), []byte(data))
 }
 
+// Publish is publishing data to the channel
+func Publish(channel string, data []byte) (bool, error) {
+	if publisher == nil {
+		return false, fmt.Errorf("publisher not initialized")
+	}
+	return publisher.Publish(channel, data)
+}
+
+// ChannelSign returns the sign which allows the client to subscribe to the private channel
+func ChannelSign(client, channel string) (string, error) {
+	secret, err := crypto.GetHMAC(config.Secret, client+channel)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("HMAC getting error")
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
 // GetStats returns Stats
 func GetStats() (gocent.Stats, error) {
 	if publisher == nil {
@@ -18,6 +18,7 @@ package rollback
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/GenesisKernel/go-genesis/packages/block"
@@ -30,8 +31,28 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// ErrFinalBlock is returned on the attempt to roll back the final block
+var ErrFinalBlock = errors.New("Final block can't be rolled back")
+
 // BlockRollback is blocking rollback
 func RollbackBlock(data []byte, deleteBlock bool) error {
+	dbTransaction, err := model.StartTransaction()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return err
+	}
+
+	if err = RollbackBlockTx(dbTransaction, data, deleteBlock); err != nil {
+		dbTransaction.Rollback()
+		return err
+	}
+
+	return dbTransaction.Commit()
+}
+
+// RollbackBlockTx rolls back the block within dbTransaction,
+// so several blocks can be rolled back and replaced atomically
+func RollbackBlockTx(dbTransaction *model.DbTransaction, data []byte, deleteBlock bool) error {
 	buf := bytes.NewBuffer(data)
 	if buf.Len() == 0 {
 		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty buffer")
@@ -43,16 +64,17 @@ func RollbackBlock(data []byte, deleteBlock bool) error {
 		return err
 	}
 
-	dbTransaction, err := model.StartTransaction()
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+	final := &model.Block{}
+	if _, err = final.Get(block.Header.BlockID); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block")
 		return err
 	}
+	if final.Final {
+		log.WithFields(log.Fields{"type": consts.BlockError, "block_id": block.Header.BlockID}).Error("rolling back final block")
+		return ErrFinalBlock
+	}
 
-	err = rollbackBlock(dbTransaction, block)
-
-	if err != nil {
-		dbTransaction.Rollback()
+	if err = rollbackBlock(dbTransaction, block); err != nil {
 		return err
 	}
 
@@ -61,13 +83,11 @@ func RollbackBlock(data []byte, deleteBlock bool) error {
 		err = b.DeleteById(dbTransaction, block.Header.BlockID)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block by id")
-			dbTransaction.Rollback()
 			return err
 		}
 	}
 
-	err = dbTransaction.Commit()
-	return err
+	return nil
 }
 
 func rollbackBlock(dbTransaction *model.DbTransaction, block *block.Block) error {
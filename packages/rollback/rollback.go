@@ -30,6 +30,16 @@ import (
 
 // ToBlockID rollbacks blocks till blockID
 func ToBlockID(blockID int64, dbTransaction *model.DbTransaction, logger *log.Entry) error {
+	final := &model.Block{}
+	if _, err := final.GetLastFinal(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last final block")
+		return err
+	}
+	if final.ID > blockID {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "block_id": final.ID}).Error("rolling back final block")
+		return ErrFinalBlock
+	}
+
 	_, err := model.MarkVerifiedAndNotUsedTransactionsUnverified()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking verified and not used transactions unverified")
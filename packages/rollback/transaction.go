@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"strings"
 
+	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/model"
@@ -28,13 +29,18 @@ import (
 )
 
 func rollbackUpdatedRow(tx map[string]string, where string, dbTransaction *model.DbTransaction, logger *log.Entry) error {
-	var rollbackInfo map[string]string
+	var rollbackInfo map[string]*string
 	if err := json.Unmarshal([]byte(tx["data"]), &rollbackInfo); err != nil {
 		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollback.Data from json")
 		return err
 	}
 	addSQLUpdate := ""
-	for k, v := range rollbackInfo {
+	for k, value := range rollbackInfo {
+		if value == nil {
+			addSQLUpdate += k + `=NULL,`
+			continue
+		}
+		v := *value
 		if v == "NULL" {
 			addSQLUpdate += k + `=NULL,`
 		} else if converter.IsByteColumn(tx["table_name"], k) && len(v) != 0 {
@@ -51,14 +57,34 @@ func rollbackUpdatedRow(tx map[string]string, where string, dbTransaction *model
 	return nil
 }
 
-func rollbackInsertedRow(tx map[string]string, where string, logger *log.Entry) error {
-	if err := model.Delete(tx["table_name"], where); err != nil {
+func rollbackInsertedRow(tx map[string]string, where string, dbTransaction *model.DbTransaction, logger *log.Entry) error {
+	if err := model.Delete(dbTransaction, tx["table_name"], where); err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting from table")
 		return err
 	}
 	return nil
 }
 
+// captureRollback writes the compensating event of the rolled back change into the change data capture log
+func captureRollback(tx map[string]string, txHash []byte, dbTransaction *model.DbTransaction, logger *log.Entry) error {
+	if !conf.Config.CDC.Enabled {
+		return nil
+	}
+	change := &model.Change{
+		BlockID:   converter.StrToInt64(tx["block_id"]),
+		TxHash:    txHash,
+		NameTable: tx["table_name"],
+		TableID:   tx["table_id"],
+		Event:     model.ChangeRollback,
+		New:       tx["data"],
+	}
+	if err := change.Create(dbTransaction); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback change")
+		return err
+	}
+	return nil
+}
+
 func rollbackTransaction(txHash []byte, dbTransaction *model.DbTransaction, logger *log.Entry) error {
 	rollbackTx := &model.RollbackTx{}
 	txs, err := rollbackTx.GetRollbackTransactions(dbTransaction, txHash)
@@ -66,18 +92,31 @@ func rollbackTransaction(txHash []byte, dbTransaction *model.DbTransaction, logg
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rollback transactions")
 		return err
 	}
+	// the rows are restored in reverse order so unique and foreign key constraints
+	// are checked after all rows of the transaction have been restored
+	if err = model.DeferConstraints(dbTransaction, true); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deferring constraints")
+		return err
+	}
 	for _, tx := range txs {
+		if err := captureRollback(tx, txHash, dbTransaction, logger); err != nil {
+			return err
+		}
 		where := " WHERE id='" + tx["table_id"] + `'`
 		if len(tx["data"]) > 0 {
 			if err := rollbackUpdatedRow(tx, where, dbTransaction, logger); err != nil {
 				return err
 			}
 		} else {
-			if err := rollbackInsertedRow(tx, where, logger); err != nil {
+			if err := rollbackInsertedRow(tx, where, dbTransaction, logger); err != nil {
 				return err
 			}
 		}
 	}
+	if err = model.DeferConstraints(dbTransaction, false); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking deferred constraints")
+		return err
+	}
 	txForDelete := &model.RollbackTx{TxHash: txHash}
 	err = txForDelete.DeleteByHash(dbTransaction)
 	if err != nil {
@@ -3,7 +3,6 @@ package contract
 import (
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,8 +13,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/conf"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
-	"github.com/GenesisKernel/go-genesis/packages/crypto"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -43,9 +41,9 @@ type contractResult struct {
 // The transaction is signed with a node key.
 func NodeContract(Name string) (result contractResult, err error) {
 	var (
-		sign                          []byte
-		ret                           authResult
-		NodePrivateKey, NodePublicKey string
+		sign          []byte
+		ret           authResult
+		NodePublicKey string
 	)
 	err = sendAPIRequest(`GET`, `getuid`, nil, &ret, ``)
 	if err != nil {
@@ -56,15 +54,12 @@ func NodeContract(Name string) (result contractResult, err error) {
 		err = fmt.Errorf(`getuid has returned empty uid`)
 		return
 	}
-	NodePrivateKey, NodePublicKey, err = utils.GetNodeKeys()
-	if err != nil || len(NodePrivateKey) == 0 {
-		if err == nil {
-			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-			err = errors.New(`empty node private key`)
-		}
+	NodePublicKey, err = signer.Node().PublicKey()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
 		return
 	}
-	sign, err = crypto.Sign(NodePrivateKey, ret.UID)
+	sign, err = signer.Node().Sign(ret.UID)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing node uid")
 		return
@@ -11,8 +11,8 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
-	"github.com/GenesisKernel/go-genesis/packages/utils"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -115,11 +115,9 @@ func (nbs *NodesBanService) localBan(node syspar.FullNode) {
 }
 
 func (nbs *NodesBanService) newBadBlock(producer syspar.FullNode, blockId, blockTime int64, reason string) error {
-	NodePrivateKey, NodePublicKey, err := utils.GetNodeKeys()
-	if err != nil || len(NodePrivateKey) < 1 {
-		if err == nil {
-			log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		}
+	NodePublicKey, err := signer.Node().PublicKey()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
 		return err
 	}
 
@@ -157,8 +155,7 @@ func (nbs *NodesBanService) newBadBlock(producer syspar.FullNode, blockId, block
 		SignedBy: smart.PubToID(NodePublicKey),
 		Data:     params,
 	},
-		NodePrivateKey,
-		NodePublicKey,
+		signer.Node(),
 		strconv.FormatInt(producer.KeyID, 10),
 		strconv.FormatInt(currentNode.KeyID, 10),
 		strconv.FormatInt(blockId, 10),
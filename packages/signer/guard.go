@@ -0,0 +1,144 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultWindow is the count of the last heights which are kept by the guard
+const DefaultWindow = 10000
+
+// Guard protects from signing two different blocks or votes at one height with the same key.
+// The key of the block is the hash of its previous block: two blocks on the same previous block are
+// the double sign, but the block on other previous block is on other branch. So after the fork-choice
+// reorg the node signs the blocks of the new branch at the heights which it has signed on the abandoned one.
+// The key of the vote is its round. The signed heights are saved to the file before the signature is returned
+type Guard struct {
+	mu     sync.Mutex
+	path   string
+	window int64
+	state  guardState
+}
+
+type guardState struct {
+	MaxBlockID int64                       `json:"max_block_id"`
+	Signed     map[int64]map[string]string `json:"signed"`
+}
+
+// NewGuard loads the signed heights from the file, the empty path keeps them in memory only
+func NewGuard(path string, window int64) (*Guard, error) {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	g := &Guard{path: path, window: window, state: guardState{Signed: make(map[int64]map[string]string)}}
+	if len(path) == 0 {
+		return g, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return g, nil
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": path}).Error("reading signer state")
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &g.state); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "path": path}).Error("unmarshalling signer state")
+		return nil, err
+	}
+	if g.state.Signed == nil {
+		g.state.Signed = make(map[int64]map[string]string)
+	}
+	return g, nil
+}
+
+// Check registers the data at the height with the key and returns error if the other data has been signed
+// at it with the same key. The same data can be signed again
+func (g *Guard) Check(blockID int64, key, data string) error {
+	hash := sha256.Sum256([]byte(data))
+	hexHash := hex.EncodeToString(hash[:])
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if signed, ok := g.state.Signed[blockID][key]; ok {
+		if signed != hexHash {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": blockID, "key": key}).Error("double sign of block")
+			return ErrDoubleSign
+		}
+		return nil
+	}
+	if blockID <= g.state.MaxBlockID-g.window {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": blockID, "max_block_id": g.state.MaxBlockID}).Error("signing of old block")
+		return ErrOldBlock
+	}
+
+	maxBlockID := g.state.MaxBlockID
+	signed, ok := g.state.Signed[blockID]
+	if !ok {
+		signed = make(map[string]string)
+		g.state.Signed[blockID] = signed
+	}
+	signed[key] = hexHash
+	if blockID > maxBlockID {
+		g.state.MaxBlockID = blockID
+	}
+	if err := g.save(); err != nil {
+		delete(signed, key)
+		if len(signed) == 0 {
+			delete(g.state.Signed, blockID)
+		}
+		g.state.MaxBlockID = maxBlockID
+		return err
+	}
+	for id := range g.state.Signed {
+		if id <= g.state.MaxBlockID-g.window {
+			delete(g.state.Signed, id)
+		}
+	}
+	return nil
+}
+
+func (g *Guard) save() error {
+	if len(g.path) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(g.state)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling signer state")
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(g.path), "."+filepath.Base(g.path)+".tmp")
+	if err = ioutil.WriteFile(tmp, data, 0600); err == nil {
+		err = os.Rename(tmp, g.path)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": g.path}).Error("saving signer state")
+	}
+	return err
+}
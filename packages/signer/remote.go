@@ -0,0 +1,169 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	serviceName = "Signer"
+	dialTimeout = 5 * time.Second
+)
+
+// PublicKeyArgs is the request of the public key
+type PublicKeyArgs struct{}
+
+// PublicKeyReply is the public key of the signer
+type PublicKeyReply struct {
+	PublicKey string
+}
+
+// SignArgs is the request of the signature of the data
+type SignArgs struct {
+	Data string
+}
+
+// SignBlockArgs is the request of the signature of the block
+type SignBlockArgs struct {
+	BlockID int64
+	Data    string
+}
+
+// SignVoteArgs is the request of the signature of the finality vote
+type SignVoteArgs struct {
+	BlockID int64
+	Data    string
+}
+
+// SignReply is the signature
+type SignReply struct {
+	Signature []byte
+}
+
+// Remote is the client of the signer daemon which keeps the node key,
+// it talks JSON-RPC over unix socket
+type Remote struct {
+	mu     sync.Mutex
+	socket string
+	client *rpc.Client
+	public string
+}
+
+// NewRemote returns the client of the signer daemon, it connects at the first request
+func NewRemote(socket string) *Remote {
+	return &Remote{socket: socket}
+}
+
+func (r *Remote) call(method string, args interface{}, reply interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the connection is restored once if the signer daemon has been restarted
+	for attempt := 0; ; attempt++ {
+		if r.client == nil {
+			conn, err := net.DialTimeout("unix", r.socket, dialTimeout)
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "socket": r.socket}).Error("connecting to signer")
+				return err
+			}
+			r.client = jsonrpc.NewClient(conn)
+			// the signer daemon can be restarted with the rotated key
+			r.public = ""
+		}
+		err := r.client.Call(serviceName+"."+method, args, reply)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(rpc.ServerError); ok {
+			log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "method": method}).Error("signer has refused")
+			return err
+		}
+		r.client.Close()
+		r.client = nil
+		if attempt > 0 {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "method": method}).Error("calling signer")
+			return err
+		}
+	}
+}
+
+// PublicKey returns the public key of the signer
+func (r *Remote) PublicKey() (string, error) {
+	r.mu.Lock()
+	public := r.public
+	r.mu.Unlock()
+	if len(public) > 0 {
+		return public, nil
+	}
+
+	var reply PublicKeyReply
+	if err := r.call("PublicKey", PublicKeyArgs{}, &reply); err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	r.public = reply.PublicKey
+	r.mu.Unlock()
+	return reply.PublicKey, nil
+}
+
+// Sign signs the data by the signer
+func (r *Remote) Sign(data string) ([]byte, error) {
+	var reply SignReply
+	if err := r.call("Sign", SignArgs{Data: data}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+// SignBlock signs the block by the signer
+func (r *Remote) SignBlock(blockID int64, data string) ([]byte, error) {
+	var reply SignReply
+	if err := r.call("SignBlock", SignBlockArgs{BlockID: blockID, Data: data}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+// SignVote signs the finality vote by the signer
+func (r *Remote) SignVote(blockID int64, data string) ([]byte, error) {
+	var reply SignReply
+	if err := r.call("SignVote", SignVoteArgs{BlockID: blockID, Data: data}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+// Close closes the connection to the signer daemon
+func (r *Remote) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		return nil
+	}
+	err := r.client.Close()
+	r.client = nil
+	return err
+}
@@ -0,0 +1,137 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"encoding/hex"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"strconv"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Server is the signer daemon which keeps the private key out of the node process
+type Server struct {
+	privateKey string
+	publicKey  string
+	guard      *Guard
+	votes      *Guard
+	rpc        *rpc.Server
+}
+
+// NewServer returns the signer daemon with the hex private key and the guards of block and vote heights
+func NewServer(privateKey string, guard, votes *Guard) (*Server, error) {
+	key, err := hex.DecodeString(privateKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
+		return nil, err
+	}
+	public, err := crypto.PrivateToPublic(key)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		privateKey: privateKey,
+		publicKey:  hex.EncodeToString(public),
+		guard:      guard,
+		votes:      votes,
+		rpc:        rpc.NewServer(),
+	}
+	if err = s.rpc.RegisterName(serviceName, &service{s}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Serve accepts the connections of nodes until the listener is closed
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.rpc.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// service is the set of RPC methods of the signer
+type service struct {
+	s *Server
+}
+
+func (svc *service) PublicKey(args PublicKeyArgs, reply *PublicKeyReply) error {
+	reply.PublicKey = svc.s.publicKey
+	return nil
+}
+
+func (svc *service) Sign(args SignArgs, reply *SignReply) error {
+	if _, _, ok := parseBlockForSign(args.Data); ok {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("block is signed as data")
+		return ErrBlockData
+	}
+	if _, _, ok := parseVoteForSign(args.Data); ok {
+		log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("vote is signed as data")
+		return ErrVoteData
+	}
+	sign, err := crypto.Sign(svc.s.privateKey, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sign
+	return nil
+}
+
+func (svc *service) SignBlock(args SignBlockArgs, reply *SignReply) error {
+	id, prevHash, ok := parseBlockForSign(args.Data)
+	if !ok || id != args.BlockID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": args.BlockID}).Error("data isn't the block")
+		return ErrBlockData
+	}
+	// the block on other previous block is on other branch, it's signed after the reorg
+	if err := svc.s.guard.Check(args.BlockID, prevHash, args.Data); err != nil {
+		return err
+	}
+	sign, err := crypto.Sign(svc.s.privateKey, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sign
+	return nil
+}
+
+func (svc *service) SignVote(args SignVoteArgs, reply *SignReply) error {
+	id, round, ok := parseVoteForSign(args.Data)
+	if !ok || id != args.BlockID {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "block_id": args.BlockID}).Error("data isn't the vote")
+		return ErrVoteData
+	}
+	// the node votes once in every round of votes at the height
+	if err := svc.s.votes.Check(args.BlockID, strconv.FormatInt(round, 10), args.Data); err != nil {
+		return err
+	}
+	sign, err := crypto.Sign(svc.s.privateKey, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sign
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrEmptyKey is returned if the node private key is empty
+	ErrEmptyKey = errors.New("Node private key is empty")
+	// ErrBlockData is returned if the block is signed as the data or the data isn't the block
+	ErrBlockData = errors.New("Blocks must be signed by SignBlock")
+	// ErrVoteData is returned if the vote is signed as the data or the data isn't the vote
+	ErrVoteData = errors.New("Votes must be signed by SignVote")
+	// ErrDoubleSign is returned if the other block has been already signed at the same height
+	ErrDoubleSign = errors.New("Other block has been already signed at this height")
+	// ErrOldBlock is returned if the block is older than the blocks which are kept by the guard
+	ErrOldBlock = errors.New("Block is too old to be signed")
+)
+
+// Signer signs the data by the node key
+type Signer interface {
+	// PublicKey returns the hex public key of the node
+	PublicKey() (string, error)
+	// Sign signs the data which isn't the block
+	Sign(data string) ([]byte, error)
+	// SignBlock signs the block with the specified id
+	SignBlock(blockID int64, data string) ([]byte, error)
+	// SignVote signs the finality vote for the block with the specified id
+	SignVote(blockID int64, data string) ([]byte, error)
+}
+
+type local struct{}
+
+// NewLocal returns the signer which uses the node private key from KeysDir
+func NewLocal() Signer {
+	return local{}
+}
+
+func (local) PublicKey() (string, error) {
+	_, public, err := utils.GetNodeKeys()
+	return public, err
+}
+
+func (local) Sign(data string) ([]byte, error) {
+	private, _, err := utils.GetNodeKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(private) == 0 {
+		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
+		return nil, ErrEmptyKey
+	}
+	return crypto.Sign(private, data)
+}
+
+func (l local) SignBlock(blockID int64, data string) ([]byte, error) {
+	if id, _, ok := parseBlockForSign(data); !ok || id != blockID {
+		return nil, ErrBlockData
+	}
+	return l.Sign(data)
+}
+
+func (l local) SignVote(blockID int64, data string) ([]byte, error) {
+	if id, _, ok := parseVoteForSign(data); !ok || id != blockID {
+		return nil, ErrVoteData
+	}
+	return l.Sign(data)
+}
+
+var (
+	nodeSigner Signer
+	nodeOnce   sync.Once
+)
+
+// Node returns the signer of the node, it's remote signer if SignerSocket is specified
+func Node() Signer {
+	nodeOnce.Do(func() {
+		if len(conf.Config.SignerSocket) > 0 {
+			nodeSigner = NewRemote(conf.Config.SignerSocket)
+		} else {
+			nodeSigner = NewLocal()
+		}
+	})
+	return nodeSigner
+}
+
+// parseBlockForSign returns the block id and the hash of the previous block if the data is the signed data
+// of the block which looks like 0,block_id,prev_hash,time,ecosystem_id,key_id,node_position,mrkl_root
+func parseBlockForSign(data string) (int64, string, bool) {
+	fields := strings.Split(data, ",")
+	if len(fields) != 8 || fields[0] != "0" {
+		return 0, "", false
+	}
+	blockID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return blockID, fields[2], true
+}
+
+// parseVoteForSign returns the block id and the round if the data is the signed data of the finality vote
+// which looks like vote,block_id,hash,node_position,round
+func parseVoteForSign(data string) (int64, int64, bool) {
+	fields := strings.Split(data, ",")
+	if len(fields) != 5 || fields[0] != "vote" {
+		return 0, 0, false
+	}
+	blockID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	round, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil || round < 0 {
+		return 0, 0, false
+	}
+	return blockID, round, true
+}
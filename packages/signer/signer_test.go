@@ -0,0 +1,254 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+package signer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+func blockForSign(blockID int64, hash string) string {
+	return blockOnPrev(blockID, hash, hash)
+}
+
+func blockOnPrev(blockID int64, prevHash, mrklRoot string) string {
+	return fmt.Sprintf("0,%d,%s,1530000000,1,1,0,%s", blockID, prevHash, mrklRoot)
+}
+
+func voteForSign(blockID int64, hash string, round int64) string {
+	return fmt.Sprintf("vote,%d,%s,0,%d", blockID, hash, round)
+}
+
+func TestGuard(t *testing.T) {
+	g, err := NewGuard("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Check(5, "", blockForSign(5, "aa")); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(5, "", blockForSign(5, "aa")); err != nil {
+		t.Errorf("the same block must be signed again, got %v", err)
+	}
+	if err = g.Check(5, "", blockForSign(5, "bb")); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+	if err = g.Check(20, "", blockForSign(20, "aa")); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(10, "", blockForSign(10, "aa")); err != ErrOldBlock {
+		t.Errorf("expected ErrOldBlock, got %v", err)
+	}
+	if err = g.Check(11, "", blockForSign(11, "aa")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGuardPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	g, err := NewGuard(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Check(7, "", blockForSign(7, "aa")); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err = NewGuard(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Check(7, "", blockForSign(7, "bb")); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign after restart, got %v", err)
+	}
+}
+
+func TestGuardRounds(t *testing.T) {
+	g, err := NewGuard("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Check(5, "0", voteForSign(5, "aa", 0)); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(5, "0", voteForSign(5, "bb", 0)); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+	// the node votes for other block in the next round after the split votes
+	if err = g.Check(5, "1", voteForSign(5, "bb", 1)); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(5, "1", voteForSign(5, "aa", 1)); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+}
+
+func TestGuardReorg(t *testing.T) {
+	g, err := NewGuard("", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.Check(5, "a4", blockOnPrev(5, "a4", "r1")); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(6, "a5", blockOnPrev(6, "a5", "r1")); err != nil {
+		t.Error(err)
+	}
+	if err = g.Check(5, "a4", blockOnPrev(5, "a4", "r2")); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+	// the chain is switched to other branch from the block 3, its blocks are signed at the same heights
+	if err = g.Check(5, "b4", blockOnPrev(5, "b4", "r2")); err != nil {
+		t.Errorf("block of other branch must be signed, got %v", err)
+	}
+	if err = g.Check(6, "b5", blockOnPrev(6, "b5", "r2")); err != nil {
+		t.Errorf("block of other branch must be signed, got %v", err)
+	}
+	if err = g.Check(6, "b5", blockOnPrev(6, "b5", "r3")); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+	// the branch which is abandoned is still protected
+	if err = g.Check(6, "a5", blockOnPrev(6, "a5", "r3")); err != ErrDoubleSign {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+}
+
+func TestParseBlockForSign(t *testing.T) {
+	cases := []struct {
+		data     string
+		blockID  int64
+		prevHash string
+		ok       bool
+	}{
+		{blockOnPrev(3, "aa", "bb"), 3, "aa", true},
+		{"0,x,aa,1,1,1,0,aa", 0, "", false},
+		{"1,3,aa,1,1,1,0,aa", 0, "", false},
+		{"@1NewUser,1,2", 0, "", false},
+	}
+	for _, v := range cases {
+		blockID, prevHash, ok := parseBlockForSign(v.data)
+		if ok != v.ok || blockID != v.blockID || prevHash != v.prevHash {
+			t.Errorf("%s: expected %d %s %v, got %d %s %v", v.data, v.blockID, v.prevHash, v.ok, blockID, prevHash, ok)
+		}
+	}
+}
+
+func TestParseVoteForSign(t *testing.T) {
+	cases := []struct {
+		data    string
+		blockID int64
+		round   int64
+		ok      bool
+	}{
+		{voteForSign(3, "aa", 2), 3, 2, true},
+		{"vote,x,aa,0,0", 0, 0, false},
+		{"vote,3,aa,0,-1", 0, 0, false},
+		{"vote,3,aa,0", 0, 0, false},
+		{blockForSign(3, "aa"), 0, 0, false},
+	}
+	for _, v := range cases {
+		blockID, round, ok := parseVoteForSign(v.data)
+		if ok != v.ok || blockID != v.blockID || round != v.round {
+			t.Errorf("%s: expected %d %d %v, got %d %d %v", v.data, v.blockID, v.round, v.ok, blockID, round, ok)
+		}
+	}
+}
+
+func TestRemote(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	private, public, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard, err := NewGuard(filepath.Join(dir, "state.json"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	votes, err := NewGuard(filepath.Join(dir, "votes.json"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(private, guard, votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go server.Serve(l)
+
+	r := NewRemote(socket)
+	defer r.Close()
+
+	pub, err := r.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub != public {
+		t.Errorf("expected public key %s, got %s", public, pub)
+	}
+
+	data := blockForSign(2, "aa")
+	if _, err = r.Sign(data); err == nil || err.Error() != ErrBlockData.Error() {
+		t.Errorf("expected ErrBlockData, got %v", err)
+	}
+	if _, err = r.SignBlock(3, data); err == nil || err.Error() != ErrBlockData.Error() {
+		t.Errorf("expected ErrBlockData, got %v", err)
+	}
+	if err = guard.Check(2, "aa", data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.SignBlock(2, blockOnPrev(2, "aa", "bb")); err == nil || err.Error() != ErrDoubleSign.Error() {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+
+	vote := voteForSign(2, "aa", 0)
+	if _, err = r.Sign(vote); err == nil || err.Error() != ErrVoteData.Error() {
+		t.Errorf("expected ErrVoteData, got %v", err)
+	}
+	if _, err = r.SignVote(3, vote); err == nil || err.Error() != ErrVoteData.Error() {
+		t.Errorf("expected ErrVoteData, got %v", err)
+	}
+	if _, err = r.SignVote(2, data); err == nil || err.Error() != ErrVoteData.Error() {
+		t.Errorf("expected ErrVoteData, got %v", err)
+	}
+	if err = votes.Check(2, "0", vote); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.SignVote(2, voteForSign(2, "bb", 0)); err == nil || err.Error() != ErrDoubleSign.Error() {
+		t.Errorf("expected ErrDoubleSign, got %v", err)
+	}
+}
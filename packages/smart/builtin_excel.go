@@ -17,8 +17,7 @@ func GetDataFromXLSX(sc *SmartContract, binaryID, startLine, linesCount, sheetNu
 		return nil, err
 	}
 
-	sheetName := book.GetSheetName(int(sheetNum))
-	rows := book.GetRows(sheetName)
+	rows := getSheetRows(book, sheetNum)
 	endLine := startLine + linesCount
 	if endLine > int64(len(rows)) {
 		endLine = int64(len(rows))
@@ -41,8 +40,7 @@ func GetRowsCountXLSX(sc *SmartContract, binaryID, sheetNum int64) (int64, error
 		return -1, err
 	}
 
-	sheetName := book.GetSheetName(int(sheetNum))
-	rows := book.GetRows(sheetName)
+	rows := getSheetRows(book, sheetNum)
 	return int64(len(rows)), nil
 }
 
@@ -61,3 +59,8 @@ func excelBookFromStoredBinary(sc *SmartContract, binaryID int64) (*xl.File, err
 
 	return xl.OpenReader(bytes.NewReader(bin.Data))
 }
+
+// getSheetRows returns all rows of the sheet
+func getSheetRows(book *xl.File, sheetNum int64) [][]string {
+	return book.GetRows(book.GetSheetName(int(sheetNum)))
+}
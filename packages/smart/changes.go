@@ -0,0 +1,118 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// captureChange writes the change of the row into the change data capture log.
+// rollbackInfo contains the previous values of the updated columns and it is empty for the inserted row,
+// values contains the new values of the changed columns.
+func (sc *SmartContract) captureChange(table, tableID, rollbackInfo string, values interface{}) error {
+	if !conf.Config.CDC.Enabled {
+		return nil
+	}
+	logger := sc.GetLogger()
+	event := model.ChangeInsert
+	if len(rollbackInfo) > 0 {
+		event = model.ChangeUpdate
+	}
+	jsonValues, err := json.Marshal(values)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling changed values to json")
+		return err
+	}
+	change := &model.Change{
+		BlockID:   sc.BlockData.BlockID,
+		TxHash:    sc.TxHash,
+		NameTable: table,
+		TableID:   tableID,
+		Event:     event,
+		Old:       rollbackInfo,
+		New:       string(jsonValues),
+	}
+	if err = change.Create(sc.DbTransaction); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating change")
+		return err
+	}
+	return nil
+}
+
+// changedValues returns the new values of the columns which are written by selectiveLoggingAndUpd.
+// old contains the previous values of the updated row and it is nil for the inserted row.
+func changedValues(table string, fields, values []string, old map[string]string) map[string]string {
+	row := make(map[string]string, len(fields))
+	jsonFields := make(map[string]map[string]string)
+	for i, field := range fields {
+		value := values[i]
+		switch {
+		case field[:1] == "+" || field[:1] == "-":
+			if old == nil {
+				row[field[1:]] = value
+				break
+			}
+			prev, _ := decimal.NewFromString(old[field[1:]])
+			delta, _ := decimal.NewFromString(value)
+			if field[:1] == "-" {
+				delta = delta.Neg()
+			}
+			row[field[1:]] = prev.Add(delta).String()
+		case strings.HasPrefix(field, `timestamp `):
+			row[field[len(`timestamp `):]] = value
+		case strings.Contains(field, `->`):
+			colfield := strings.Split(field, `->`)
+			if len(colfield) == 2 {
+				if jsonFields[colfield[0]] == nil {
+					jsonFields[colfield[0]] = make(map[string]string)
+				}
+				jsonFields[colfield[0]][colfield[1]] = value
+			}
+		case converter.IsByteColumn(table, field) && len(value) != 0:
+			row[field] = string(converter.BinToHex([]byte(value)))
+		default:
+			row[field] = strings.TrimPrefix(value, `timestamp `)
+		}
+	}
+	for colname, colvals := range jsonFields {
+		merged := make(map[string]interface{})
+		if prev := old[colname]; len(prev) > 0 && prev != `NULL` {
+			if err := json.Unmarshal([]byte(prev), &merged); err != nil {
+				log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Warn("unmarshalling previous value of jsonb column")
+			}
+		}
+		for k, v := range colvals {
+			merged[k] = v
+		}
+		out, err := json.Marshal(merged)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling value of jsonb column")
+			continue
+		}
+		row[colname] = string(out)
+	}
+	return row
+}
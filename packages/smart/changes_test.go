@@ -0,0 +1,38 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedValues(t *testing.T) {
+	fields := []string{`name`, `+amount`, `-rest`, `timestamp created`, `data->title`}
+	values := []string{`first`, `10`, `3`, `1500000000`, `New`}
+
+	assert.Equal(t, map[string]string{`name`: `first`, `amount`: `10`, `rest`: `3`,
+		`created`: `1500000000`, `data`: `{"title":"New"}`},
+		changedValues(`1_items`, fields, values, nil))
+
+	old := map[string]string{`id`: `5`, `name`: `old`, `amount`: `2.5`, `rest`: `7`,
+		`created`: `2017-07-14`, `data`: `{"title":"Old","count":"1"}`}
+	assert.Equal(t, map[string]string{`name`: `first`, `amount`: `12.5`, `rest`: `4`,
+		`created`: `1500000000`, `data`: `{"count":"1","title":"New"}`},
+		changedValues(`1_items`, fields, values, old))
+}
@@ -0,0 +1,104 @@
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// Types of parameters of template components
+const (
+	ComponentString = `string`
+	ComponentInt    = `int`
+	ComponentMoney  = `money`
+	ComponentBool   = `bool`
+
+	// ComponentBody is the name of the parameter with the content which is passed to the component
+	ComponentBody = `Body`
+)
+
+// ComponentParam describes the parameter of the template component
+type ComponentParam struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default string `json:"default"`
+}
+
+func isComponentName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, ch := range name {
+		if (ch < 'A' || ch > 'Z') && (ch < 'a' || ch > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns an error if the value doesn't match the type of the parameter
+func (p *ComponentParam) Check(value string) error {
+	var err error
+	switch p.Type {
+	case ComponentInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case ComponentMoney:
+		_, err = decimal.NewFromString(value)
+	case ComponentBool:
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf(`parameter %s must be %s`, p.Name, p.Type)
+	}
+	return nil
+}
+
+// ParseComponentParams returns the list of parameters from JSON array
+func ParseComponentParams(params string) ([]ComponentParam, error) {
+	list := make([]ComponentParam, 0)
+	if len(params) == 0 {
+		return list, nil
+	}
+	if err := json.Unmarshal([]byte(params), &list); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for i, par := range list {
+		if !isComponentName(par.Name) || par.Name == ComponentBody || names[par.Name] {
+			return nil, fmt.Errorf(`parameter name %s is not valid`, par.Name)
+		}
+		names[par.Name] = true
+		if len(par.Type) == 0 {
+			list[i].Type = ComponentString
+		}
+		switch list[i].Type {
+		case ComponentString, ComponentInt, ComponentMoney, ComponentBool:
+		default:
+			return nil, fmt.Errorf(`type %s of parameter %s is not valid`, par.Type, par.Name)
+		}
+		if len(par.Default) > 0 {
+			if err := list[i].Check(par.Default); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return list, nil
+}
+
+// ValidateComponent checks the name and the parameters of the template component
+func ValidateComponent(sc *SmartContract, name, params string) error {
+	if !isComponentName(name) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "name": name}).Error("component name is not valid")
+		return fmt.Errorf(`Component name %s is not valid`, name)
+	}
+	if _, err := ParseComponentParams(params); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("component parameters are not valid")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"testing"
+)
+
+func TestParseComponentParams(t *testing.T) {
+	list, err := ParseComponentParams(`[{"name":"Title"},{"name":"Count","type":"int","default":"5"}]`)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(list) != 2 || list[0].Type != ComponentString || list[1].Default != `5` {
+		t.Errorf(`wrong parameters %v`, list)
+	}
+	for _, params := range []string{
+		`[{"name":"Body"}]`,
+		`[{"name":"my_title"}]`,
+		`[{"name":"Title"},{"name":"Title"}]`,
+		`[{"name":"Count","type":"float"}]`,
+		`[{"name":"Count","type":"int","default":"five"}]`,
+		`{"name":"Title"}`,
+	} {
+		if _, err = ParseComponentParams(params); err == nil {
+			t.Errorf(`%s must be wrong`, params)
+		}
+	}
+	if err = ValidateComponent(nil, `My_Card`, ``); err == nil {
+		t.Error(`wrong component name has been accepted`)
+	}
+}
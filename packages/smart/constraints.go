@@ -0,0 +1,162 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	pqForeignKeyViolation = `23503`
+	pqUniqueViolation     = `23505`
+)
+
+var (
+	constraintKey   = regexp.MustCompile(`Key \((.+)\)=\((.*)\)`)
+	constraintTable = regexp.MustCompile(`table "(?:\d+_(?:vde_)?)?([^"]+)"`)
+)
+
+// ColumnOptions are the constraints of the column of the ecosystem table
+type ColumnOptions struct {
+	Unique     bool
+	References string
+}
+
+// getColumnOptions returns the constraints from the description of the column
+func getColumnOptions(data map[string]interface{}) (opts ColumnOptions, err error) {
+	switch v := data[`unique`].(type) {
+	case bool:
+		opts.Unique = v
+	case string:
+		opts.Unique = v == `1` || strings.ToLower(v) == `true`
+	case float64:
+		opts.Unique = v != 0
+	case int64:
+		opts.Unique = v != 0
+	}
+	if data[`references`] == nil {
+		return
+	}
+	ref, ok := data[`references`].(string)
+	if !ok {
+		return opts, fmt.Errorf(`references must be the name of the table`)
+	}
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if off := strings.IndexByte(ref, '.'); off >= 0 {
+		if ref[off:] != `.id` {
+			return opts, fmt.Errorf(`only id column can be referenced`)
+		}
+		ref = ref[:off]
+	}
+	if len(ref) > 0 && !converter.IsLatin(ref) {
+		return opts, fmt.Errorf(eLatin, ref)
+	}
+	opts.References = ref
+	return
+}
+
+// columnOptions returns the constraints from the optional parameters of CreateColumn and ColumnCondition
+// which are unique flag and the referenced table
+func columnOptions(options []interface{}) (ColumnOptions, error) {
+	data := make(map[string]interface{})
+	if len(options) > 0 {
+		data[`unique`] = options[0]
+	}
+	if len(options) > 1 && options[1] != `` {
+		data[`references`] = options[1]
+	}
+	return getColumnOptions(data)
+}
+
+// checkColumnOptions checks that the constraints can be defined for the column
+func (sc *SmartContract) checkColumnOptions(colType string, opts ColumnOptions) error {
+	if opts.Unique && colType == `json` {
+		return fmt.Errorf(`unique constraint cannot be defined for json column`)
+	}
+	if len(opts.References) == 0 {
+		return nil
+	}
+	if colType != `number` {
+		return fmt.Errorf(`column which references %s must be number`, opts.References)
+	}
+	prefix := converter.Int64ToStr(sc.TxSmart.EcosystemID)
+	if sc.VDE {
+		prefix += `_vde`
+	}
+	t := &model.Table{}
+	t.SetTablePrefix(prefix)
+	exists, err := t.ExistsByName(sc.DbTransaction, opts.References)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("table is exists")
+		return err
+	}
+	if !exists {
+		log.WithFields(log.Fields{"table_name": opts.References, "type": consts.NotFound}).Error("referenced table does not exists")
+		return fmt.Errorf(eTableNotFound, opts.References)
+	}
+	return nil
+}
+
+// columnSQL returns the definition of the column with the constraints. The constraints are deferrable
+// so the rollback can restore the rows of the transaction in any order
+func (sc *SmartContract) columnSQL(colType string, opts ColumnOptions) (string, error) {
+	sqlColType, err := columnType(colType)
+	if err != nil {
+		return ``, err
+	}
+	if len(opts.References) > 0 {
+		sqlColType = `bigint REFERENCES "` + getDefTableName(sc, opts.References) + `" (id) DEFERRABLE INITIALLY IMMEDIATE`
+	}
+	if opts.Unique {
+		sqlColType += ` UNIQUE DEFERRABLE INITIALLY IMMEDIATE`
+	}
+	return sqlColType, nil
+}
+
+// constraintError converts the violation of unique or foreign key constraint to the error of the contract
+func constraintError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return err
+	}
+	var column, value, table string
+	if match := constraintKey.FindStringSubmatch(pqErr.Detail); len(match) == 3 {
+		column, value = match[1], match[2]
+	}
+	if match := constraintTable.FindStringSubmatch(pqErr.Detail); len(match) == 2 {
+		table = match[1]
+	}
+	switch string(pqErr.Code) {
+	case pqUniqueViolation:
+		return fmt.Errorf(`Value %s of column %s already exists`, value, column)
+	case pqForeignKeyViolation:
+		if strings.Contains(pqErr.Detail, `is still referenced`) {
+			return fmt.Errorf(`Record %s is referenced from table %s`, value, table)
+		}
+		return fmt.Errorf(`Record %s of table %s referenced by column %s has not been found`, value, table, column)
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestColumnOptions(t *testing.T) {
+	opts, err := getColumnOptions(map[string]interface{}{`unique`: `true`, `references`: ` Goods.id`})
+	if err != nil || !opts.Unique || opts.References != `goods` {
+		t.Errorf(`wrong options %v %v`, opts, err)
+	}
+	if opts, err = columnOptions([]interface{}{false, ``}); err != nil || opts.Unique || len(opts.References) > 0 {
+		t.Errorf(`wrong options %v %v`, opts, err)
+	}
+	for _, data := range []map[string]interface{}{
+		{`references`: `goods.name`},
+		{`references`: 10.0},
+		{`references`: `goods;drop`},
+	} {
+		if _, err = getColumnOptions(data); err == nil {
+			t.Errorf(`%v must be wrong`, data)
+		}
+	}
+}
+
+func TestConstraintError(t *testing.T) {
+	for _, item := range []struct {
+		err  error
+		want string
+	}{
+		{&pq.Error{Code: pqUniqueViolation, Detail: `Key (name)=(apple) already exists.`},
+			`Value apple of column name already exists`},
+		{&pq.Error{Code: pqForeignKeyViolation, Detail: `Key (goods)=(5) is not present in table "1_goods".`},
+			`Record 5 of table goods referenced by column goods has not been found`},
+		{&pq.Error{Code: pqForeignKeyViolation, Detail: `Key (id)=(5) is still referenced from table "1_orders".`},
+			`Record 5 is referenced from table orders`},
+		{errors.New(`unknown`), `unknown`},
+	} {
+		if err := constraintError(item.err); err.Error() != item.want {
+			t.Errorf(`%s != %s`, err, item.want)
+		}
+	}
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/scheduler"
 	"github.com/GenesisKernel/go-genesis/packages/scheduler/contract"
 	"github.com/GenesisKernel/go-genesis/packages/script"
+	"github.com/GenesisKernel/go-genesis/packages/template/syntax"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 	"github.com/GenesisKernel/go-genesis/packages/vdemanager"
@@ -113,10 +114,12 @@ func (sc *SmartContract) AppendStack(contract string) error {
 
 var (
 	funcCallsDB = map[string]struct{}{
+		"DBImport":    {},
 		"DBInsert":    {},
 		"DBSelect":    {},
 		"DBUpdate":    {},
 		"DBUpdateExt": {},
+		"Migrate":     {},
 		"SetPubKey":   {},
 	}
 	extendCost = map[string]int64{
@@ -141,6 +144,7 @@ var (
 		"HMac":                         50,
 		"Join":                         10,
 		"JSONToMap":                    50,
+		"MigrationConditions":          50,
 		"Sha256":                       50,
 		"IdToAddress":                  10,
 		"Len":                          5,
@@ -155,6 +159,10 @@ var (
 		"TrimSpace":                    10,
 		"TableConditions":              100,
 		"ValidateCondition":            30,
+		"ValidateComponent":            10,
+		"MultisigAddress":              50,
+		"MultisigKeys":                 10,
+		"ValidateTemplate":             50,
 		"ValidateEditContractNewValue": 10,
 	}
 	// map for table name to parameter with conditions
@@ -188,6 +196,7 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"ValidateEditContractNewValue": ValidateEditContractNewValue,
 		"CreateColumn":                 CreateColumn,
 		"CreateTable":                  CreateTable,
+		"DBImport":                     DBImport,
 		"DBInsert":                     DBInsert,
 		"DBSelect":                     DBSelect,
 		"DBUpdate":                     DBUpdate,
@@ -224,8 +233,13 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"PubToID":                      PubToID,
 		"HexToBytes":                   HexToBytes,
 		"LangRes":                      LangRes,
+		"LangFormat":                   LangFormat,
 		"HasPrefix":                    strings.HasPrefix,
 		"ValidateCondition":            ValidateCondition,
+		"ValidateComponent":            ValidateComponent,
+		"MultisigAddress":              MultisigAddress,
+		"MultisigKeys":                 MultisigKeys,
+		"ValidateTemplate":             ValidateTemplate,
 		"TrimSpace":                    strings.TrimSpace,
 		"ToLower":                      strings.ToLower,
 		"ToUpper":                      strings.ToUpper,
@@ -236,6 +250,9 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 		"RollbackTable":                RollbackTable,
 		"TableConditions":              TableConditions,
 		"RollbackColumn":               RollbackColumn,
+		"MigrationConditions":          MigrationConditions,
+		"Migrate":                      Migrate,
+		"RollbackMigration":            RollbackMigration,
 		"CreateLanguage":               CreateLanguage,
 		"EditLanguage":                 EditLanguage,
 		"Activate":                     Activate,
@@ -300,6 +317,7 @@ func EmbedFuncs(vm *script.VM, vt script.VMType) {
 	case script.VMTypeSmart:
 		f["GetBlock"] = GetBlock
 		f["UpdateNodesBan"] = UpdateNodesBan
+		f["RotateNodeKey"] = RotateNodeKey
 		f["DBSelectMetrics"] = DBSelectMetrics
 		f["DBCollectMetrics"] = DBCollectMetrics
 		ExtendCost(getCostP)
@@ -580,7 +598,11 @@ func CreateTable(sc *SmartContract, name, columns, permissions string, applicati
 			return fmt.Errorf(`There are the same columns`)
 		}
 
-		sqlColType, err := columnType(data["type"].(string))
+		opts, err := getColumnOptions(data)
+		if err != nil {
+			return err
+		}
+		sqlColType, err := sc.columnSQL(data["type"].(string), opts)
 		if err != nil {
 			return err
 		}
@@ -1103,6 +1125,14 @@ func TableConditions(sc *SmartContract, name, columns, permissions string) (err
 			log.WithFields(log.Fields{"type": consts.InvalidObject}).Error("incorrect type")
 			return fmt.Errorf(`incorrect type`)
 		}
+		opts, err := getColumnOptions(data)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("incorrect column constraints")
+			return err
+		}
+		if err = sc.checkColumnOptions(itype, opts); err != nil {
+			return err
+		}
 		condition := ``
 		switch v := data[`conditions`].(type) {
 		case string:
@@ -1152,8 +1182,17 @@ func ValidateCondition(sc *SmartContract, condition string, state int64) error {
 	return VMCompileEval(sc.VM, condition, uint32(state))
 }
 
+// ValidateTemplate is contract func which checks the syntax of the template
+func ValidateTemplate(sc *SmartContract, input string) error {
+	if err := syntax.Check(input); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("validating template")
+		return err
+	}
+	return nil
+}
+
 // ColumnCondition is contract func
-func ColumnCondition(sc *SmartContract, tableName, name, coltype, permissions string) error {
+func ColumnCondition(sc *SmartContract, tableName, name, coltype, permissions string, options ...interface{}) error {
 	name = converter.EscapeSQL(strings.ToLower(name))
 	tableName = converter.EscapeSQL(strings.ToLower(tableName))
 	if !accessContracts(sc, `NewColumn`, `EditColumn`) {
@@ -1214,6 +1253,14 @@ func ColumnCondition(sc *SmartContract, tableName, name, coltype, permissions st
 		log.WithFields(log.Fields{"column_type": coltype, "type": consts.InvalidObject}).Error("Unknown column type")
 		return fmt.Errorf(`incorrect type`)
 	}
+	opts, err := columnOptions(options)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("incorrect column constraints")
+		return err
+	}
+	if err = sc.checkColumnOptions(coltype, opts); err != nil {
+		return err
+	}
 	return sc.AccessTable(tblName, "new_column")
 }
 
@@ -1270,7 +1317,7 @@ func checkColumnName(name string) error {
 }
 
 // CreateColumn is creating column
-func CreateColumn(sc *SmartContract, tableName, name, colType, permissions string) (err error) {
+func CreateColumn(sc *SmartContract, tableName, name, colType, permissions string, options ...interface{}) (err error) {
 	var (
 		sqlColType string
 		permout    []byte
@@ -1287,7 +1334,11 @@ func CreateColumn(sc *SmartContract, tableName, name, colType, permissions strin
 	tableName = strings.ToLower(tableName)
 	tblname := getDefTableName(sc, tableName)
 
-	sqlColType, err = columnType(colType)
+	opts, err := columnOptions(options)
+	if err != nil {
+		return
+	}
+	sqlColType, err = sc.columnSQL(colType, opts)
 	if err != nil {
 		return
 	}
@@ -1295,7 +1346,7 @@ func CreateColumn(sc *SmartContract, tableName, name, colType, permissions strin
 	err = model.AlterTableAddColumn(sc.DbTransaction, tblname, name, sqlColType)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("adding column to the table")
-		return
+		return constraintError(err)
 	}
 
 	tables := getDefTableName(sc, `tables`)
@@ -1329,19 +1380,30 @@ func CreateColumn(sc *SmartContract, tableName, name, colType, permissions strin
 }
 
 // SetPubKey updates the publis key
-func SetPubKey(sc *SmartContract, id int64, pubKey []byte) (qcost int64, err error) {
+func SetPubKey(sc *SmartContract, id int64, pubKey []byte, keyType ...int64) (qcost int64, err error) {
 	if !accessContracts(sc, `NewUser`) {
 		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("SetPubKey can be only called from NewUser")
 		return 0, fmt.Errorf(`SetPubKey can be only called from NewUser contract`)
 	}
-	if len(pubKey) == consts.PubkeySizeLength*2 {
+	var kt crypto.KeyType
+	if len(keyType) > 0 {
+		kt = crypto.KeyType(keyType[0])
+	}
+	if _, err = kt.Scheme(); err != nil {
+		return
+	}
+	if len(pubKey) == kt.PublicKeySize()*2 {
 		pubKey, err = hex.DecodeString(string(pubKey))
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding public key from hex")
 			return
 		}
 	}
-	qcost, _, err = sc.selectiveLoggingAndUpd([]string{`pub`}, []interface{}{pubKey},
+	fields, values := []string{`pub`}, []interface{}{pubKey}
+	if kt != crypto.KeyECDSA {
+		fields, values = append(fields, `key_type`), append(values, int64(kt))
+	}
+	qcost, _, err = sc.selectiveLoggingAndUpd(fields, values,
 		getDefTableName(sc, `keys`), []string{`id`}, []string{converter.Int64ToStr(id)},
 		!sc.VDE && sc.Rollback, true)
 	return qcost, err
@@ -1691,6 +1753,54 @@ func UpdateNodesBan(smartContract *SmartContract, timestamp int64) error {
 	return nil
 }
 
+// RotateNodeKey replaces the public key of the full node of the sender,
+// the previous key stays valid during node_key_overlap blocks so the node can switch keys without downtime
+func RotateNodeKey(smartContract *SmartContract, publicKey string) error {
+	if smartContract.BlockData == nil {
+		log.WithFields(log.Fields{"type": consts.EmptyObject}).Error("rotating node key out of block")
+		return fmt.Errorf(`Node key can be rotated only in the block`)
+	}
+	pub, err := hex.DecodeString(publicKey)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": publicKey}).Error("decoding public key from hex")
+		return err
+	}
+
+	fullNodes := syspar.GetNodes()
+	position := -1
+	for i, fullNode := range fullNodes {
+		if fullNode.KeyID == smartContract.TxSmart.KeyID {
+			position = i
+		}
+		if bytes.Equal(fullNode.PublicKey, pub) {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "key_id": fullNode.KeyID}).Error("public key is used by full node")
+			return ErrNodeKeyUsed
+		}
+	}
+	if position < 0 {
+		log.WithFields(log.Fields{"type": consts.NotFound, "key_id": smartContract.TxSmart.KeyID}).Error("unknown node id")
+		return ErrUnknownNodeID
+	}
+
+	if err = fullNodes[position].RotateKey(pub, smartContract.BlockData.BlockID, syspar.GetNodeKeyOverlap()); err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("rotating node key")
+		return err
+	}
+
+	data, err := json.Marshal(fullNodes)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling full nodes")
+		return err
+	}
+
+	_, err = UpdateSysParam(smartContract, syspar.FullNodes, string(data), "")
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating full nodes")
+		return err
+	}
+	return nil
+}
+
 func GetBlock(blockID int64) (map[string]int64, error) {
 	block := model.Block{}
 	ok, err := block.Get(blockID)
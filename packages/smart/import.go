@@ -0,0 +1,271 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/model/querycost"
+
+	xl "github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	importMaxRows   = 10000
+	importMaxErrors = 10
+	mimeTypeXLSX    = `application/vnd.openxmlformats-officedocument.spreadsheetml.sheet`
+)
+
+// importRow is the row of imported data which is inserted by model.BatchInsert
+type importRow struct {
+	table  string
+	values map[string]interface{}
+}
+
+// TableName returns name of table
+func (r importRow) TableName() string {
+	return r.table
+}
+
+// FieldValue implementing BatchModel interface
+func (r importRow) FieldValue(fieldName string) (interface{}, error) {
+	val, ok := r.values[strings.Trim(fieldName, `"`)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown field '%s' for %s", fieldName, r.table)
+	}
+	return val, nil
+}
+
+// parseImportData returns rows of CSV or XLSX data
+func parseImportData(data []byte, mimeType string) ([][]string, error) {
+	if mimeType == mimeTypeXLSX || bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		book, err := xl.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return getSheetRows(book, 1), nil
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, BOM)))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// importColumn describes the column of the table which receives the imported values
+type importColumn struct {
+	dataType  string
+	nullable  bool
+	maxLength int
+}
+
+// importTimeFormats are the accepted formats of the values of timestamp columns
+var importTimeFormats = []string{`2006-01-02 15:04:05.999999999`, time.RFC3339Nano, `2006-01-02T15:04:05`, `2006-01-02`}
+
+// checkImportValue checks that the value can be written into the column and returns the value
+// which is inserted. Empty value is written as NULL into the nullable column and it is not allowed
+// for the required one.
+func checkImportValue(col importColumn, value string) (interface{}, error) {
+	if len(value) == 0 {
+		if col.nullable {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`value is required`)
+	}
+	var err error
+	switch col.dataType {
+	case `bigint`, `integer`, `smallint`:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case `numeric`, `double precision`:
+		_, err = decimal.NewFromString(value)
+	case `jsonb`:
+		if !json.Valid([]byte(value)) {
+			err = fmt.Errorf(`invalid json`)
+		}
+	case `bytea`:
+		err = fmt.Errorf(`binary columns are not supported`)
+	case `timestamp without time zone`, `timestamp with time zone`:
+		err = fmt.Errorf(`invalid timestamp`)
+		for _, format := range importTimeFormats {
+			if _, errTime := time.Parse(format, value); errTime == nil {
+				err = nil
+				break
+			}
+		}
+	}
+	if err == nil && col.maxLength > 0 && utf8.RuneCountInString(value) > col.maxLength {
+		err = fmt.Errorf(`value is longer than %d`, col.maxLength)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// importColumns returns the names of the columns from the header of the imported data
+func importColumns(header []string, types map[string]importColumn) ([]string, error) {
+	columns := make([]string, len(header))
+	used := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := types[name]; !ok || name == `id` {
+			return nil, fmt.Errorf(`column %s is not valid`, name)
+		}
+		if used[name] {
+			return nil, fmt.Errorf(`column %s is duplicated`, name)
+		}
+		used[name] = true
+		columns[i] = name
+	}
+	return columns, nil
+}
+
+// DBImport inserts rows of CSV or XLSX data into the table within one transaction.
+// The first row contains the names of columns. It returns the count of inserted rows.
+func DBImport(sc *SmartContract, tblname string, data []byte, mimeType string) (qcost int64, count int64, err error) {
+	if tblname == "system_parameters" {
+		return 0, 0, fmt.Errorf("system parameters access denied")
+	}
+	logger := sc.GetLogger()
+	tblname = getDefTableName(sc, tblname)
+	if err = sc.AccessTable(tblname, "insert"); err != nil {
+		return
+	}
+	rows, err := parseImportData(data, mimeType)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParseError, "error": err}).Error("parsing import data")
+		return 0, 0, fmt.Errorf(`import data is invalid: %s`, err)
+	}
+	if len(rows) < 2 {
+		return 0, 0, fmt.Errorf(`import data is empty`)
+	}
+	if len(rows)-1 > importMaxRows {
+		return 0, 0, fmt.Errorf(`import data contains more than %d rows`, importMaxRows)
+	}
+	colTypes, err := model.GetAllColumnsInfo(tblname)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column types")
+		return
+	}
+	types := make(map[string]importColumn)
+	for _, col := range colTypes {
+		types[col[`column_name`]] = importColumn{dataType: col[`data_type`], nullable: col[`is_nullable`] == `YES`,
+			maxLength: converter.StrToInt(col[`max_length`])}
+	}
+	columns, err := importColumns(rows[0], types)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		rowErrors []string
+		nextID    int64
+	)
+	if nextID, err = model.GetNextID(sc.DbTransaction, tblname); err != nil {
+		return
+	}
+	batch := make([]model.BatchModel, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		if len(row) != len(columns) {
+			rowErrors = append(rowErrors, fmt.Sprintf(`row %d: wrong count of values %d`, i+1, len(row)))
+		} else {
+			item := importRow{table: tblname, values: map[string]interface{}{`id`: nextID + int64(i)}}
+			for j, value := range row {
+				val, err := checkImportValue(types[columns[j]], value)
+				if err != nil {
+					rowErrors = append(rowErrors, fmt.Sprintf(`row %d: column %s: %s`, i+1, columns[j], err))
+					continue
+				}
+				item.values[columns[j]] = val
+			}
+			batch = append(batch, item)
+		}
+		if len(rowErrors) >= importMaxErrors {
+			break
+		}
+	}
+	if len(rowErrors) > 0 {
+		return 0, 0, fmt.Errorf(`import data is invalid: %s`, strings.Join(rowErrors, `; `))
+	}
+
+	fields := make([]string, 0, len(columns)+1)
+	fields = append(fields, `id`)
+	for _, col := range columns {
+		fields = append(fields, `"`+col+`"`)
+	}
+	// The cost is calculated for one row and it is multiplied by the count of rows and indexes
+	oneRow := fmt.Sprintf(`INSERT INTO "%s" (%s) VALUES (%s)`, tblname, strings.Join(fields, `,`),
+		strings.TrimRight(strings.Repeat(`'0',`, len(fields)), `,`))
+	if qcost, err = querycost.GetQueryCoster(querycost.FormulaQueryCosterType).QueryCost(sc.DbTransaction, oneRow); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "query": oneRow}).Error("getting query total cost")
+		return
+	}
+	count = int64(len(batch))
+	qcost *= count
+	ind, err := model.NumIndexes(tblname)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("num indexes")
+		return
+	}
+	if ind > 0 {
+		qcost *= int64(ind)
+	}
+	if err = model.BatchInsert(sc.DbTransaction, batch, fields); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("inserting import data")
+		return 0, 0, constraintError(err)
+	}
+	if sc.VDE || !sc.Rollback {
+		return
+	}
+	rollbacks := make([]model.BatchModel, 0, len(batch))
+	for i := range batch {
+		rollbacks = append(rollbacks, model.RollbackTx{
+			BlockID:   sc.BlockData.BlockID,
+			TxHash:    sc.TxHash,
+			NameTable: tblname,
+			TableID:   converter.Int64ToStr(nextID + int64(i)),
+		})
+	}
+	if err = model.BatchInsert(sc.DbTransaction, rollbacks, []string{`block_id`, `tx_hash`, `table_name`, `table_id`, `data`}); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback tx for import data")
+		return 0, 0, err
+	}
+	for i, item := range batch {
+		row := item.(importRow)
+		values := make(map[string]interface{}, len(row.values))
+		for k, v := range row.values {
+			if v != nil {
+				v = fmt.Sprint(v)
+			}
+			values[k] = v
+		}
+		if err = sc.captureChange(tblname, converter.Int64ToStr(nextID+int64(i)), ``, values); err != nil {
+			return 0, 0, err
+		}
+	}
+	return
+}
@@ -0,0 +1,92 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"bytes"
+	"testing"
+
+	xl "github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportData(t *testing.T) {
+	rows, err := parseImportData(append(BOM, []byte("name,amount\nfirst,10\nsecond,\"2,5\"\n")...), `text/csv`)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"name", "amount"}, {"first", "10"}, {"second", "2,5"}}, rows)
+
+	book := xl.NewFile()
+	book.SetCellValue("Sheet1", "A1", "name")
+	book.SetCellValue("Sheet1", "A2", "first")
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+	rows, err = parseImportData(buf.Bytes(), mimeTypeXLSX)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"name"}, {"first"}}, rows)
+}
+
+func TestCheckImportValue(t *testing.T) {
+	valid := func(dataType, value string) {
+		val, err := checkImportValue(importColumn{dataType: dataType}, value)
+		require.NoError(t, err, value)
+		require.Equal(t, value, val)
+	}
+	invalid := func(dataType, value string) {
+		_, err := checkImportValue(importColumn{dataType: dataType}, value)
+		require.Error(t, err, value)
+	}
+	valid(`bigint`, `100`)
+	invalid(`bigint`, `1.5`)
+	valid(`numeric`, `1.5`)
+	invalid(`numeric`, `abc`)
+	valid(`jsonb`, `{"a":1}`)
+	invalid(`jsonb`, `{`)
+	invalid(`bytea`, `00`)
+	valid(`text`, `any`)
+	valid(`timestamp without time zone`, `2018-03-01 10:20:30`)
+	valid(`timestamp without time zone`, `2018-03-01T10:20:30Z`)
+	valid(`timestamp without time zone`, `2018-03-01`)
+	invalid(`timestamp without time zone`, `yesterday`)
+	invalid(`timestamp without time zone`, `2018-13-01`)
+
+	_, err := checkImportValue(importColumn{dataType: `character`, maxLength: 1}, `ab`)
+	require.Error(t, err)
+	_, err = checkImportValue(importColumn{dataType: `character varying`, maxLength: 3}, `абв`)
+	require.NoError(t, err)
+
+	val, err := checkImportValue(importColumn{dataType: `timestamp without time zone`, nullable: true}, ``)
+	require.NoError(t, err)
+	require.Nil(t, val)
+	_, err = checkImportValue(importColumn{dataType: `bigint`}, ``)
+	require.Error(t, err)
+	_, err = checkImportValue(importColumn{dataType: `character`, maxLength: 1}, ``)
+	require.Error(t, err)
+}
+
+func TestImportColumns(t *testing.T) {
+	types := map[string]importColumn{`id`: {dataType: `bigint`}, `name`: {dataType: `text`},
+		`amount`: {dataType: `bigint`}}
+	columns, err := importColumns([]string{` Name`, `AMOUNT `}, types)
+	require.NoError(t, err)
+	require.Equal(t, []string{`name`, `amount`}, columns)
+	_, err = importColumns([]string{`name`, `amount`, `Name`}, types)
+	require.EqualError(t, err, `column name is duplicated`)
+	_, err = importColumns([]string{`id`, `name`}, types)
+	require.Error(t, err)
+	_, err = importColumns([]string{`unknown`}, types)
+	require.Error(t, err)
+}
@@ -0,0 +1,525 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/model/querycost"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Actions of the table migrations
+const (
+	MigrationRename = `rename`
+	MigrationDrop   = `drop`
+	MigrationType   = `type`
+	MigrationIndex  = `index`
+)
+
+// Conversions of the values which can be used by the migration of the column type. The values are
+// cast to the new type if the conversion is not specified.
+const (
+	ConversionNumber    = `to_number`
+	ConversionTimestamp = `to_timestamp`
+)
+
+// MigrationParams is the description of the table migration which is stored in migrations table
+type MigrationParams struct {
+	Column     string `json:"column"`
+	NewName    string `json:"new_name,omitempty"`
+	Type       string `json:"type,omitempty"`
+	OldType    string `json:"old_type,omitempty"`
+	Conversion string `json:"conversion,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Index      string `json:"index,omitempty"`
+}
+
+var (
+	// the structure of these tables is used by the platform so it can't be migrated
+	migrationLocked = map[string]bool{
+		`contracts`: true, `keys`: true, `history`: true, `languages`: true, `menu`: true,
+		`pages`: true, `blocks`: true, `signatures`: true, `members`: true, `roles`: true,
+		`roles_participants`: true, `notifications`: true, `sections`: true, `applications`: true,
+		`binaries`: true, `parameters`: true, `app_params`: true, `buffer_data`: true,
+		`components`: true, `tables`: true, `migrations`: true,
+	}
+	// functions which convert the values by the migration of the column type
+	conversions = map[string]bool{ConversionNumber: true, ConversionTimestamp: true}
+)
+
+// checkConversion checks that the conversion is allowed and the format is specified for it
+func checkConversion(conversion, format string) error {
+	if len(conversion) == 0 {
+		if len(format) > 0 {
+			return fmt.Errorf(`format can be specified only with the conversion`)
+		}
+		return nil
+	}
+	if !conversions[conversion] {
+		return fmt.Errorf(`conversion %s is not allowed`, conversion)
+	}
+	if len(format) == 0 {
+		return fmt.Errorf(`format of %s conversion is empty`, conversion)
+	}
+	return nil
+}
+
+// migrationIndex returns the name of the index on the columns
+func migrationIndex(tblname string, columns []string) string {
+	return tblname + `_` + strings.Join(columns, `_`)
+}
+
+func splitColumns(list string) []string {
+	columns := make([]string, 0)
+	for _, column := range strings.Split(list, `,`) {
+		if column = strings.TrimSpace(strings.ToLower(column)); len(column) > 0 {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// MigrationConditions checks the migration of the ecosystem table before it is applied by MigrateTable contract
+func MigrationConditions(sc *SmartContract, tableName string, version int64, action, column, newName,
+	colType, conversion, format string) error {
+	if !accessContracts(sc, `MigrateTable`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("MigrationConditions can be only called from @1MigrateTable")
+		return fmt.Errorf(`MigrationConditions can be only called from MigrateTable`)
+	}
+	if sc.VDE {
+		return fmt.Errorf(`Migrations of tables are not supported in VDE`)
+	}
+	tableName = strings.ToLower(tableName)
+	if migrationLocked[tableName] {
+		log.WithFields(log.Fields{"type": consts.AccessDenied, "table": tableName}).Error("migration of system table")
+		return fmt.Errorf(`Table %s cannot be migrated`, tableName)
+	}
+	t := &model.Table{}
+	t.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID))
+	exists, err := t.ExistsByName(sc.DbTransaction, tableName)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("table is exists")
+		return err
+	}
+	if !exists {
+		log.WithFields(log.Fields{"table_name": tableName, "type": consts.NotFound}).Error("table does not exists")
+		return fmt.Errorf(eTableNotFound, tableName)
+	}
+	m := &model.TableMigration{}
+	m.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID))
+	last, err := m.LastVersion(sc.DbTransaction, tableName)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last version of migration")
+		return err
+	}
+	if version != last+1 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "version": version, "last": last}).Error("wrong version of migration")
+		return fmt.Errorf(`Version %d of %s migration is expected`, last+1, tableName)
+	}
+
+	tblname := getDefTableName(sc, tableName)
+	types, err := model.GetAllColumnTypes(tblname)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column types")
+		return err
+	}
+	columns := make(map[string]string)
+	for _, item := range types {
+		columns[item[`column_name`]] = item[`data_type`]
+	}
+	checkColumn := func(name string) error {
+		if _, ok := columns[name]; !ok {
+			log.WithFields(log.Fields{"column_name": name, "type": consts.NotFound}).Error("column does not exists")
+			return fmt.Errorf(`column %s doesn't exists`, name)
+		}
+		if name == `id` && action != MigrationIndex {
+			return fmt.Errorf(`column id cannot be migrated`)
+		}
+		return nil
+	}
+
+	column = strings.ToLower(column)
+	switch action {
+	case MigrationRename:
+		if err = checkColumn(column); err != nil {
+			return err
+		}
+		newName = strings.ToLower(newName)
+		if err = checkColumnName(newName); err != nil {
+			return err
+		}
+		if _, ok := columns[newName]; ok {
+			log.WithFields(log.Fields{"column_name": newName, "type": consts.Found}).Error("column exists")
+			return fmt.Errorf(`column %s exists`, newName)
+		}
+	case MigrationDrop, MigrationType:
+		if err = checkColumn(column); err != nil {
+			return err
+		}
+		// the constraints can't be restored by the rollback of the migration
+		if exists, err = model.IsConstrainedColumn(sc.DbTransaction, tblname, column); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking constraints of column")
+			return err
+		}
+		if exists {
+			return fmt.Errorf(`column %s has unique or foreign key constraints`, column)
+		}
+		if action == MigrationDrop {
+			break
+		}
+		if _, err = columnType(colType); err != nil {
+			return err
+		}
+		if err = checkConversion(conversion, format); err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking conversion")
+			return err
+		}
+	case MigrationIndex:
+		list := splitColumns(column)
+		if len(list) == 0 {
+			return errEmptyColumn
+		}
+		for _, name := range list {
+			if err = checkColumn(name); err != nil {
+				return err
+			}
+		}
+		ind, err := model.NumIndexes(tblname)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("num indexes")
+			return err
+		}
+		if ind+len(list) > syspar.GetMaxIndexes() {
+			log.WithFields(log.Fields{"size": ind, "max_size": syspar.GetMaxIndexes(), "type": consts.ParameterExceeded}).Error("Too many indexes")
+			return fmt.Errorf(`Too many indexes. Limit is %d`, syspar.GetMaxIndexes())
+		}
+		if exists, err = model.IsIndexName(migrationIndex(tblname, list) + `_index`); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking index")
+			return err
+		}
+		if exists {
+			return fmt.Errorf(`index on %s exists`, strings.Join(list, `,`))
+		}
+		return sc.AccessTable(tblname, `new_column`)
+	default:
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "action": action}).Error("unknown action of migration")
+		return fmt.Errorf(`Unknown action %s of migration`, action)
+	}
+	if err = sc.AccessTable(tblname, `update`); err != nil {
+		if err = sc.AccessRights(`changing_tables`, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateColumnPermissions changes the permissions of the columns in tables table
+func (sc *SmartContract) updateColumnPermissions(tableName string, update func(map[string]string)) error {
+	tables := getDefTableName(sc, `tables`)
+	temp := &model.Table{}
+	temp.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID))
+	if _, err := temp.Get(sc.DbTransaction, tableName); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("querying columns by table name")
+		return err
+	}
+	var perm map[string]string
+	if err := json.Unmarshal([]byte(temp.Columns), &perm); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling columns permissions from json")
+		return err
+	}
+	update(perm)
+	permout, err := json.Marshal(perm)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling column permissions to json")
+		return err
+	}
+	_, _, err = sc.selectiveLoggingAndUpd([]string{`columns`}, []interface{}{string(permout)},
+		tables, []string{`name`}, []string{tableName}, !sc.VDE && sc.Rollback, false)
+	return err
+}
+
+// columnValues returns the text values of the column of all rows of the table.
+// The rows with NULL value have isnull field equal to 1.
+func columnValues(transaction *model.DbTransaction, tblname, column string) ([]map[string]string, error) {
+	value := `"` + column + `"::text`
+	if itype, err := model.GetColumnType(tblname, column); err == nil && itype == `bytea` {
+		value = `'\x' || encode("` + column + `", 'hex')`
+	}
+	return model.GetAllTransaction(transaction, `SELECT id, `+value+` AS value, ("`+column+
+		`" IS NULL)::int AS isnull FROM "`+tblname+`" ORDER BY id`, -1)
+}
+
+// columnValue returns the value of the row which has been selected by columnValues, NULL is returned as nil
+func columnValue(row map[string]string) interface{} {
+	if row[`isnull`] == `1` {
+		return nil
+	}
+	return row[`value`]
+}
+
+// backupColumn writes the values of the column into the rollback records so they are restored
+// when the migration is rolled back. NULL values are written as JSON null. It returns the cost
+// and the rollback data of the rows.
+func (sc *SmartContract) backupColumn(tblname, column string) (int64, map[string]string, error) {
+	logger := sc.GetLogger()
+	qcost, err := querycost.GetQueryCoster(querycost.FormulaQueryCosterType).QueryCost(sc.DbTransaction,
+		`UPDATE "`+tblname+`" SET "`+column+`" = NULL`)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting query total cost")
+		return 0, nil, err
+	}
+	rows, err := columnValues(sc.DbTransaction, tblname, column)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": tblname}).Error("selecting column values")
+		return 0, nil, err
+	}
+	qcost += int64(len(rows))
+	backup := make(map[string]string, len(rows))
+	rollbacks := make([]model.BatchModel, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(map[string]interface{}{column: columnValue(row)})
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling rollback info to json")
+			return 0, nil, err
+		}
+		backup[row[`id`]] = string(data)
+		if sc.Rollback && sc.BlockData != nil {
+			rollbacks = append(rollbacks, model.RollbackTx{
+				BlockID:   sc.BlockData.BlockID,
+				TxHash:    sc.TxHash,
+				NameTable: tblname,
+				TableID:   row[`id`],
+				Data:      string(data),
+			})
+		}
+	}
+	if len(rollbacks) > 0 {
+		if err = model.BatchInsert(sc.DbTransaction, rollbacks, []string{`block_id`, `tx_hash`, `table_name`, `table_id`, `data`}); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback tx for column values")
+			return 0, nil, err
+		}
+	}
+	return qcost, backup, nil
+}
+
+// Migrate applies the migration of the ecosystem table and writes it into migrations table
+func Migrate(sc *SmartContract, tableName string, version int64, action, column, newName,
+	colType, conversion, format string) (qcost int64, err error) {
+	if !accessContracts(sc, `MigrateTable`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("Migrate can be only called from @1MigrateTable")
+		return 0, fmt.Errorf(`Migrate can be only called from MigrateTable`)
+	}
+	tableName = strings.ToLower(tableName)
+	tblname := getDefTableName(sc, tableName)
+	params := MigrationParams{Column: strings.ToLower(column)}
+	switch action {
+	case MigrationRename:
+		params.NewName = strings.ToLower(newName)
+		if err = model.AlterTableRenameColumn(sc.DbTransaction, tblname, params.Column, params.NewName); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("renaming column")
+			return
+		}
+		err = sc.updateColumnPermissions(tableName, func(perm map[string]string) {
+			perm[params.NewName] = perm[params.Column]
+			delete(perm, params.Column)
+		})
+	case MigrationDrop, MigrationType:
+		if params.OldType, err = model.GetColumnDefinition(sc.DbTransaction, tblname, params.Column); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column definition")
+			return
+		}
+		var backup map[string]string
+		if qcost, backup, err = sc.backupColumn(tblname, params.Column); err != nil {
+			return
+		}
+		if action == MigrationDrop {
+			if err = model.AlterTableDropColumn(sc.DbTransaction, tblname, params.Column); err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("dropping column")
+				return
+			}
+			err = sc.updateColumnPermissions(tableName, func(perm map[string]string) {
+				delete(perm, params.Column)
+			})
+			break
+		}
+		params.Type, params.Conversion, params.Format = colType, conversion, format
+		if err = checkConversion(conversion, format); err != nil {
+			return
+		}
+		var sqlColType string
+		if sqlColType, err = columnType(colType); err != nil {
+			return
+		}
+		if len(conversion) > 0 {
+			if err = model.ConvertTableColumn(sc.DbTransaction, tblname, params.Column, conversion, format); err != nil {
+				log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("converting values of column")
+				return
+			}
+		}
+		sqlType, _, _ := model.SplitColumnType(sqlColType)
+		if err = model.AlterTableColumnType(sc.DbTransaction, tblname, params.Column, sqlType, false); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("changing type of column")
+			return
+		}
+		if err = model.AlterTableColumnConstraints(sc.DbTransaction, tblname, params.Column, sqlColType); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting constraints of column")
+			return
+		}
+		if !conf.Config.CDC.Enabled {
+			break
+		}
+		var rows []map[string]string
+		if rows, err = columnValues(sc.DbTransaction, tblname, params.Column); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting converted column values")
+			return
+		}
+		for _, row := range rows {
+			if err = sc.captureChange(tblname, row[`id`], backup[row[`id`]],
+				map[string]interface{}{params.Column: columnValue(row)}); err != nil {
+				return
+			}
+		}
+	case MigrationIndex:
+		list := splitColumns(column)
+		params.Column = strings.Join(list, `,`)
+		params.Index = migrationIndex(tblname, list)
+		if err = model.CreateIndex(sc.DbTransaction, params.Index, tblname, `"`+strings.Join(list, `","`)+`"`); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating index")
+			return
+		}
+	default:
+		return 0, fmt.Errorf(`Unknown action %s of migration`, action)
+	}
+	if err != nil {
+		return
+	}
+	out, err := json.Marshal(params)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling migration params")
+		return
+	}
+	var blockID int64
+	if sc.BlockData != nil {
+		blockID = sc.BlockData.BlockID
+	}
+	cost, _, err := sc.selectiveLoggingAndUpd([]string{`table_name`, `version`, `action`, `params`, `block_id`},
+		[]interface{}{tableName, version, action, string(out), blockID}, getDefTableName(sc, `migrations`),
+		nil, nil, !sc.VDE && sc.Rollback, false)
+	return qcost + cost, err
+}
+
+// restoreColumn writes the values of the column from the rollback records of the transaction
+func (sc *SmartContract) restoreColumn(tblname, column string) error {
+	rollbackTx := &model.RollbackTx{}
+	txs, err := rollbackTx.GetRollbackTransactions(sc.DbTransaction, sc.TxHash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rollback transactions")
+		return err
+	}
+	for _, tx := range txs {
+		if tx[`table_name`] != tblname {
+			continue
+		}
+		var data map[string]*string
+		if err = json.Unmarshal([]byte(tx[`data`]), &data); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollback data from json")
+			return err
+		}
+		value, ok := data[column]
+		if !ok {
+			continue
+		}
+		sqlValue := `NULL`
+		if value != nil {
+			sqlValue = `'` + strings.Replace(*value, `'`, `''`, -1) + `'`
+		}
+		if err = model.Update(sc.DbTransaction, tblname, `"`+column+`" = `+sqlValue,
+			`WHERE id='`+converter.EscapeSQL(tx[`table_id`])+`'`); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("restoring column value")
+			return err
+		}
+	}
+	return nil
+}
+
+// RollbackMigration is rolling back the migration of the table. The rows and the permissions
+// are restored by the rollback records after it
+func RollbackMigration(sc *SmartContract, tableName string, version int64) error {
+	if !accessContracts(sc, `MigrateTable`) {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("RollbackMigration can be only called from @1MigrateTable")
+		return fmt.Errorf(`RollbackMigration can be only called from MigrateTable`)
+	}
+	tableName = strings.ToLower(tableName)
+	migrations := getDefTableName(sc, `migrations`)
+	rollbackTx := &model.RollbackTx{}
+	found, err := rollbackTx.Get(sc.DbTransaction, sc.TxHash, migrations)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting migration from rollback table")
+		return err
+	}
+	if !found {
+		log.WithFields(log.Fields{"type": consts.NotFound}).Error("migration record in rollback table")
+		// if there is not such hash then MigrateTable was faulty. Do nothing.
+		return nil
+	}
+	m := &model.TableMigration{}
+	m.SetTablePrefix(converter.Int64ToStr(sc.TxSmart.EcosystemID))
+	if found, err = m.Get(sc.DbTransaction, tableName, version); err != nil || !found {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "version": version}).Error("getting migration")
+		return fmt.Errorf(`Migration %d of %s has not been found`, version, tableName)
+	}
+	var params MigrationParams
+	if err = json.Unmarshal([]byte(m.Params), &params); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling migration params")
+		return err
+	}
+	tblname := getDefTableName(sc, tableName)
+	sqlType, _, _ := model.SplitColumnType(params.OldType)
+	switch m.Action {
+	case MigrationRename:
+		err = model.AlterTableRenameColumn(sc.DbTransaction, tblname, params.NewName, params.Column)
+	case MigrationDrop:
+		err = model.AlterTableAddColumn(sc.DbTransaction, tblname, params.Column, sqlType)
+	case MigrationType:
+		err = model.AlterTableColumnType(sc.DbTransaction, tblname, params.Column, sqlType, true)
+	case MigrationIndex:
+		err = model.DropIndex(sc.DbTransaction, params.Index)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "action": m.Action}).Error("rolling back migration")
+		return err
+	}
+	if m.Action == MigrationDrop || m.Action == MigrationType {
+		if err = sc.restoreColumn(tblname, params.Column); err != nil {
+			return err
+		}
+		if err = model.AlterTableColumnConstraints(sc.DbTransaction, tblname, params.Column, params.OldType); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("setting constraints of column")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+func TestCheckConversion(t *testing.T) {
+	for conversion, format := range map[string]string{
+		``:                  ``,
+		ConversionNumber:    `999G999D99`,
+		ConversionTimestamp: `YYYY-MM-DD HH24:MI:SS`,
+	} {
+		if err := checkConversion(conversion, format); err != nil {
+			t.Errorf(`%s: %v`, conversion, err)
+		}
+	}
+	for conversion, format := range map[string]string{
+		``:                                   `9999`,
+		ConversionNumber:                     ``,
+		`amount); DROP TABLE keys; --`:       `9`,
+		`e'\'' ); DROP TABLE "1_keys"; --'`:  ``,
+		`(SELECT pub FROM "1_keys" LIMIT 1)`: ``,
+		`pg_sleep`:                           `10`,
+	} {
+		if err := checkConversion(conversion, format); err == nil {
+			t.Errorf(`%s must be wrong`, conversion)
+		}
+	}
+}
+
+func TestSplitColumnType(t *testing.T) {
+	for input, want := range map[string][3]string{
+		`bigint NOT NULL DEFAULT '0'`:          {`bigint`, `true`, `'0'`},
+		`character varying(102400)`:            {`character varying(102400)`, `false`, ``},
+		`text DEFAULT ''::text`:                {`text`, `false`, `''::text`},
+		`timestamp without time zone NOT NULL`: {`timestamp without time zone`, `true`, ``},
+	} {
+		sqlType, notNull, def := model.SplitColumnType(input)
+		if sqlType != want[0] || (notNull && want[1] != `true`) || def != want[2] {
+			t.Errorf(`%s: %s %v %s`, input, sqlType, notNull, def)
+		}
+	}
+	if list := splitColumns(` Name, amount,,`); len(list) != 2 || migrationIndex(`1_goods`, list) != `1_goods_name_amount` {
+		t.Errorf(`wrong index columns %v`, list)
+	}
+}
+
+func TestColumnValue(t *testing.T) {
+	if value := columnValue(map[string]string{`value`: ``, `isnull`: `1`}); value != nil {
+		t.Errorf(`NULL must be nil, not %v`, value)
+	}
+	if value := columnValue(map[string]string{`value`: `NULL`, `isnull`: `0`}); value != `NULL` {
+		t.Errorf(`wrong value %v`, value)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxMultisigKeys is the maximum count of keys of the multisig wallet
+const MaxMultisigKeys = 32
+
+var (
+	// ErrMultisigKeys is returned if the keys of the multisig wallet are not valid
+	ErrMultisigKeys = errors.New(`Keys of multisig wallet are not valid`)
+	// ErrMultisigThreshold is returned if the threshold is out of the count of keys
+	ErrMultisigThreshold = errors.New(`Threshold must be between 1 and the count of keys`)
+	// ErrMultisigSigners is returned if the signers of the transaction aren't the keys of the multisig wallet
+	ErrMultisigSigners = errors.New(`Signers are not valid keys of multisig wallet`)
+	// ErrMultisigNotEnough is returned if the transaction has less signatures than the threshold
+	ErrMultisigNotEnough = errors.New(`Not enough signatures of multisig wallet`)
+)
+
+// parseMultisigKeys returns the sorted list of keys, the keys can be specified as addresses or ids
+func parseMultisigKeys(keys []interface{}) ([]int64, error) {
+	if len(keys) == 0 || len(keys) > MaxMultisigKeys {
+		return nil, ErrMultisigKeys
+	}
+	list := make([]int64, 0, len(keys))
+	used := make(map[int64]bool)
+	for _, key := range keys {
+		id := converter.StringToAddress(strings.TrimSpace(fmt.Sprint(key)))
+		if id == 0 || used[id] {
+			return nil, ErrMultisigKeys
+		}
+		used[id] = true
+		list = append(list, id)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list, nil
+}
+
+func joinMultisigKeys(keys []int64) string {
+	list := make([]string, len(keys))
+	for i, id := range keys {
+		list[i] = converter.Int64ToStr(id)
+	}
+	return strings.Join(list, `,`)
+}
+
+// MultisigWalletID returns the id of the wallet of the keys with the threshold.
+// The id doesn't depend on the order of keys
+func MultisigWalletID(keys []int64, threshold int64) int64 {
+	sorted := append([]int64{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return crypto.Address([]byte(fmt.Sprintf(`multisig,%d,%s`, threshold, joinMultisigKeys(sorted))))
+}
+
+// MultisigAddress checks the keys and the threshold of the multisig wallet and returns its id.
+// All keys must have public keys in the ecosystem
+func MultisigAddress(sc *SmartContract, keys []interface{}, threshold int64) (int64, error) {
+	list, err := parseMultisigKeys(keys)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "keys": keys}).Error("parsing keys of multisig wallet")
+		return 0, err
+	}
+	if threshold < 1 || threshold > int64(len(list)) {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "threshold": threshold}).Error("threshold of multisig wallet")
+		return 0, ErrMultisigThreshold
+	}
+	for _, id := range list {
+		key := &model.Key{}
+		key.SetTablePrefix(sc.TxSmart.EcosystemID)
+		found, err := key.Get(id)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting key of multisig wallet")
+			return 0, err
+		}
+		if !found || len(key.PublicKey) == 0 || key.Deleted != 0 {
+			log.WithFields(log.Fields{"type": consts.NotFound, "key_id": id}).Error("key of multisig wallet has not been found")
+			return 0, fmt.Errorf(`Key %s has not been found`, converter.AddressToString(id))
+		}
+	}
+	return MultisigWalletID(list, threshold), nil
+}
+
+// MultisigKeys returns the sorted list of ids of keys for the multisig_wallets table
+func MultisigKeys(keys []interface{}) (string, error) {
+	list, err := parseMultisigKeys(keys)
+	if err != nil {
+		return ``, err
+	}
+	return joinMultisigKeys(list), nil
+}
+
+// SplitSignatures returns the signatures which have been joined with EncodeLengthPlusData
+func SplitSignatures(data []byte) ([][]byte, error) {
+	signs := make([][]byte, 0)
+	for len(data) > 0 {
+		length, err := converter.DecodeLength(&data)
+		if err != nil {
+			return nil, err
+		}
+		if length <= 0 || length > int64(len(data)) {
+			return nil, fmt.Errorf(`wrong length of signature`)
+		}
+		signs = append(signs, converter.BytesShift(&data, length))
+	}
+	return signs, nil
+}
+
+// JoinSignatures joins the signatures with EncodeLengthPlusData
+func JoinSignatures(signs [][]byte) []byte {
+	data := make([]byte, 0)
+	for _, sign := range signs {
+		data = append(data, converter.EncodeLengthPlusData(sign)...)
+	}
+	return data
+}
+
+// MultisigForSign returns the data which are signed by the keys of the multisig wallet.
+// The signatures of co-signers cover the hash of the parameters of the contract
+func MultisigForSign(smartTx *tx.SmartContract) (string, error) {
+	hash, err := crypto.Hash(smartTx.Data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("hashing data of multisig transaction")
+		return ``, err
+	}
+	return fmt.Sprintf(`%s,%x`, smartTx.ForSign(), hash), nil
+}
+
+// getMultisigWallet returns the multisig wallet of the transaction or nil if the key isn't multisig wallet
+func (sc *SmartContract) getMultisigWallet() (*model.MultisigWallet, error) {
+	if sc.VDE {
+		return nil, nil
+	}
+	wallet := &model.MultisigWallet{}
+	wallet.SetTablePrefix(sc.TxSmart.EcosystemID)
+	// the ecosystem without the table of multisig wallets has no multisig wallets
+	if !model.IsTable(wallet.TableName()) {
+		return nil, nil
+	}
+	found, err := wallet.Get(sc.DbTransaction, sc.TxSmart.KeyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting multisig wallet")
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return wallet, nil
+}
+
+// checkMultisig checks the signatures of the keys of the multisig wallet.
+// It returns the public key of the first signer
+func (sc *SmartContract) checkMultisig(wallet *model.MultisigWallet) ([]byte, error) {
+	logger := sc.GetLogger()
+	if sc.TxSmart.SignedBy != 0 {
+		return nil, ErrMultisigSigners
+	}
+	signs, err := SplitSignatures(sc.TxSmart.BinSignatures)
+	if err != nil || len(signs) != len(sc.TxSmart.Signers) {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("signatures of multisig transaction")
+		return nil, ErrMultisigSigners
+	}
+	forsign, err := MultisigForSign(&sc.TxSmart)
+	if err != nil {
+		return nil, err
+	}
+	signed := make(map[int64]bool)
+	var public []byte
+	for i, keyID := range sc.TxSmart.Signers {
+		if signed[keyID] || !wallet.HasKey(keyID) {
+			logger.WithFields(log.Fields{"type": consts.InvalidObject, "key_id": keyID}).Error("signer of multisig transaction")
+			return nil, ErrMultisigSigners
+		}
+		key := &model.Key{}
+		key.SetTablePrefix(sc.TxSmart.EcosystemID)
+		found, err := key.Get(keyID)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting key of signer")
+			return nil, err
+		}
+		if !found || len(key.PublicKey) == 0 || key.Deleted != 0 {
+			return nil, ErrMultisigSigners
+		}
+		ok, err := crypto.KeyType(key.KeyType).CheckSign(key.PublicKey, forsign, signs[i])
+		if err != nil || !ok {
+			logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "key_id": keyID}).Error("incorrect sign of multisig transaction")
+			return nil, ErrIncorrectSign
+		}
+		signed[keyID] = true
+		if public == nil {
+			public = key.PublicKey
+		}
+	}
+	if int64(len(signed)) < wallet.Threshold {
+		return nil, ErrMultisigNotEnough
+	}
+	return public, nil
+}
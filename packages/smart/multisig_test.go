@@ -0,0 +1,67 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package smart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
+
+func TestMultisigWalletID(t *testing.T) {
+	id := MultisigWalletID([]int64{3, 1, 2}, 2)
+	if id == 0 || id != MultisigWalletID([]int64{1, 2, 3}, 2) {
+		t.Errorf(`id of wallet depends on the order of keys`)
+	}
+	if id == MultisigWalletID([]int64{1, 2, 3}, 3) {
+		t.Errorf(`id of wallet doesn't depend on the threshold`)
+	}
+
+	first, second := crypto.Address([]byte(`first`)), crypto.Address([]byte(`second`))
+	if first > second {
+		first, second = second, first
+	}
+	keys, err := MultisigKeys([]interface{}{converter.AddressToString(second), converter.Int64ToStr(first)})
+	if err != nil || keys != converter.Int64ToStr(first)+`,`+converter.Int64ToStr(second) {
+		t.Errorf(`wrong keys %s %v`, keys, err)
+	}
+	for _, wrong := range [][]interface{}{
+		{},
+		{first, first},
+		{`wrong`},
+	} {
+		if _, err = MultisigKeys(wrong); err != ErrMultisigKeys {
+			t.Errorf(`keys %v must be refused`, wrong)
+		}
+	}
+}
+
+func TestSplitSignatures(t *testing.T) {
+	signs := [][]byte{bytes.Repeat([]byte{1}, 64), bytes.Repeat([]byte{2}, 200)}
+	list, err := SplitSignatures(JoinSignatures(signs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || !bytes.Equal(list[0], signs[0]) || !bytes.Equal(list[1], signs[1]) {
+		t.Errorf(`wrong signatures %v`, list)
+	}
+	if _, err = SplitSignatures([]byte{10, 1, 2}); err == nil {
+		t.Errorf(`short signature must be refused`)
+	}
+}
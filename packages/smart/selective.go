@@ -43,6 +43,7 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []inter
 		err             error
 		cost            int64
 		rollbackInfoStr string
+		newValues       map[string]string
 	)
 	logger := sc.GetLogger()
 
@@ -196,9 +197,10 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []inter
 		err = model.Update(sc.DbTransaction, table, addSQLUpdate, addSQLWhere)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "sql": addSQLUpdate}).Error("getting update query")
-			return 0, tableID, err
+			return 0, tableID, constraintError(err)
 		}
 		tableID = logData[`id`]
+		newValues = changedValues(table, fields, values, logData)
 	} else {
 		isID := false
 		addSQLIns0 := []string{}
@@ -276,9 +278,17 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []inter
 			return 0, tableID, err
 		}
 		cost += insertCost
+		newValues = changedValues(table, fields, values, nil)
+		if whereFields != nil && whereValues != nil {
+			for i := 0; i < len(whereFields); i++ {
+				newValues[whereFields[i]] = whereValues[i]
+			}
+		}
+		newValues[`id`] = tableID
 		err = model.GetDB(sc.DbTransaction).Exec(insertQuery).Error
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "query": insertQuery}).Error("executing insert query")
+			err = constraintError(err)
 		}
 	}
 	if err != nil {
@@ -299,6 +309,9 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []inter
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback tx")
 			return 0, tableID, err
 		}
+		if err = sc.captureChange(table, tableID, rollbackInfoStr, newValues); err != nil {
+			return 0, tableID, err
+		}
 	}
 	return cost, tableID, nil
 }
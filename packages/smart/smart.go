@@ -29,6 +29,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/script"
 	"github.com/GenesisKernel/go-genesis/packages/utils"
@@ -75,6 +76,8 @@ var (
 	ErrFuelRate       = errors.New(`Fuel rate must be greater than 0`)
 	ErrIncorrectSign  = errors.New(`incorrect sign`)
 	ErrInvalidValue   = errors.New(`Invalid value`)
+	ErrKeyType        = errors.New(`Key type doesn't match the key`)
+	ErrNodeKeyUsed    = errors.New(`Public key is already used by full node`)
 	ErrUnknownNodeID  = errors.New(`Unknown node id`)
 	ErrWrongPriceFunc = errors.New(`Wrong type of price function`)
 	ErrNegPrice       = errors.New(`Price value is negative`)
@@ -862,32 +865,54 @@ func (sc *SmartContract) CallContract(flags int) (string, error) {
 		if wallet.Deleted == 1 {
 			return retError(ErrDeletedKey)
 		}
-		if len(wallet.PublicKey) > 0 {
-			public = wallet.PublicKey
-		}
-		if sc.TxSmart.Type == 258 { // UpdFullNodes
-			node := syspar.GetNode(sc.TxSmart.KeyID)
-			if node == nil {
-				logger.WithFields(log.Fields{"user_id": sc.TxSmart.KeyID, "type": consts.NotFound}).Error("unknown node id")
-				return retError(ErrUnknownNodeID)
-			}
-			public = node.PublicKey
-		}
-		if len(public) == 0 {
-			logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty public key")
-			return retError(ErrEmptyPublicKey)
-		}
-		sc.PublicKeys = append(sc.PublicKeys, public)
-
-		var CheckSignResult bool
-		CheckSignResult, err = utils.CheckSign(sc.PublicKeys, sc.TxData[`forsign`].(string), sc.TxSmart.BinSignatures, false)
+		multisig, err := sc.getMultisigWallet()
 		if err != nil {
-			logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("checking tx data sign")
 			return retError(err)
 		}
-		if !CheckSignResult {
-			logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("incorrect sign")
-			return retError(ErrIncorrectSign)
+		if multisig != nil {
+			// the transaction of the multisig wallet is signed by the keys of the wallet
+			if public, err = sc.checkMultisig(multisig); err != nil {
+				return retError(err)
+			}
+			sc.PublicKeys = append(sc.PublicKeys, public)
+		} else {
+			if len(sc.TxSmart.Signers) > 0 {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("signers of not multisig wallet")
+				return retError(ErrMultisigSigners)
+			}
+			keyType := sc.TxSmart.KeyType
+			if len(wallet.PublicKey) > 0 {
+				public = wallet.PublicKey
+				if wallet.KeyType != keyType {
+					logger.WithFields(log.Fields{"type": consts.InvalidObject, "key_type": keyType}).Error("key type doesn't match the key")
+					return retError(ErrKeyType)
+				}
+			}
+			if sc.TxSmart.Type == 258 { // UpdFullNodes
+				node := syspar.GetNode(sc.TxSmart.KeyID)
+				if node == nil {
+					logger.WithFields(log.Fields{"user_id": sc.TxSmart.KeyID, "type": consts.NotFound}).Error("unknown node id")
+					return retError(ErrUnknownNodeID)
+				}
+				public = node.PublicKey
+			}
+			if len(public) == 0 {
+				logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("empty public key")
+				return retError(ErrEmptyPublicKey)
+			}
+			sc.PublicKeys = append(sc.PublicKeys, public)
+
+			var CheckSignResult bool
+			CheckSignResult, err = utils.CheckSignType(crypto.KeyType(keyType), sc.PublicKeys,
+				sc.TxData[`forsign`].(string), sc.TxSmart.BinSignatures, false)
+			if err != nil {
+				logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("checking tx data sign")
+				return retError(err)
+			}
+			if !CheckSignResult {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject}).Error("incorrect sign")
+				return retError(ErrIncorrectSign)
+			}
 		}
 		if sc.TxSmart.EcosystemID > 0 && !sc.VDE && !conf.Config.IsPrivateBlockchain() {
 			if sc.TxSmart.TokenEcosystem == 0 {
@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
@@ -40,11 +41,13 @@ import (
 
 var (
 	funcCallsDBP = map[string]struct{}{
+		"DBImport":         {},
 		"DBInsert":         {},
 		"DBUpdate":         {},
 		"DBUpdateSysParam": {},
 		"DBUpdateExt":      {},
 		"DBSelect":         {},
+		"Migrate":          {},
 	}
 
 	extendCostSysParams = map[string]string{
@@ -156,7 +159,7 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 		case `rb_blocks_1`, `number_of_nodes`:
 			ok = ival > 0 && ival < 1000
 		case `ecosystem_price`, `contract_price`, `column_price`, `table_price`, `menu_price`,
-			`page_price`, `commission_size`:
+			`page_price`, `commission_size`, `node_key_overlap`:
 			ok = ival >= 0
 		case `max_block_size`, `max_tx_size`, `max_tx_count`, `max_columns`, `max_indexes`,
 			`max_block_user_tx`, `max_fuel_tx`, `max_fuel_block`, `max_forsign_size`:
@@ -178,6 +181,8 @@ func UpdateSysParam(sc *SmartContract, name, value, conditions string) (int64, e
 				}
 			}
 			checked = true
+		case syspar.Consensus:
+			checked = consensus.IsValidName(value)
 		case syspar.FullNodes:
 			fnodes := []syspar.FullNode{}
 			if err := json.Unmarshal([]byte(value), &fnodes); err != nil {
@@ -316,12 +321,15 @@ func Sha256(text string) string {
 }
 
 // PubToID returns a numeric identifier for the public key specified in the hexadecimal form.
-func PubToID(hexkey string) int64 {
+func PubToID(hexkey string, keyType ...int64) int64 {
 	pubkey, err := hex.DecodeString(hexkey)
 	if err != nil {
 		log.WithFields(log.Fields{"value": hexkey, "error": err, "type": consts.CryptoError}).Error("decoding hexkey to string")
 		return 0
 	}
+	if len(keyType) > 0 {
+		return crypto.KeyType(keyType[0]).Address(pubkey)
+	}
 	return crypto.Address(pubkey)
 }
 
@@ -336,6 +344,16 @@ func LangRes(sc *SmartContract, appID int64, idRes, lang string) string {
 	return ret
 }
 
+// LangFormat returns the language resource formatted with the parameters
+func LangFormat(sc *SmartContract, appID int64, idRes, lang string, params map[string]interface{}) string {
+	values := make(map[string]string, len(params))
+	for key, val := range params {
+		values[key] = fmt.Sprint(val)
+	}
+	ret, _ := language.LangFormat(idRes, int(sc.TxSmart.EcosystemID), int(appID), lang, sc.VDE, values)
+	return ret
+}
+
 // NewLang creates new language
 func CreateLanguage(sc *SmartContract, name, trans string, appID int64) (id int64, err error) {
 	if !accessContracts(sc, "NewLang", "NewLangJoint", "Import") {
@@ -353,9 +371,9 @@ func CreateLanguage(sc *SmartContract, name, trans string, appID int64) (id int6
 
 // EditLanguage edits language
 func EditLanguage(sc *SmartContract, id int64, name, trans string, appID int64) error {
-	if !accessContracts(sc, "EditLang", "EditLangJoint", "Import") {
-		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("EditLanguage can be only called from @1EditLang, @1EditLangJoint and @1Import")
-		return fmt.Errorf(`EditLanguage can be only called from @1EditLang, @1EditLangJoint and @1Import`)
+	if !accessContracts(sc, "EditLang", "EditLangs", "EditLangJoint", "Import") {
+		log.WithFields(log.Fields{"type": consts.IncorrectCallingContract}).Error("EditLanguage can be only called from @1EditLang, @1EditLangs, @1EditLangJoint and @1Import")
+		return fmt.Errorf(`EditLanguage can be only called from @1EditLang, @1EditLangs, @1EditLangJoint and @1Import`)
 	}
 	idStr := converter.Int64ToStr(sc.TxSmart.EcosystemID)
 	if _, err := DBUpdate(sc, `@`+idStr+"_languages", id, "name,res,app_id", name, trans, appID); err != nil {
@@ -667,7 +685,7 @@ func RollbackColumn(sc *SmartContract, tableName, name string) error {
 		// if there is not such hash then NewColumn was faulty. Do nothing.
 		return nil
 	}
-	return model.AlterTableDropColumn(getDefTableName(sc, tableName), name)
+	return model.AlterTableDropColumn(sc.DbTransaction, getDefTableName(sc, tableName), name)
 }
 
 // Size returns the length of the string
@@ -742,7 +760,7 @@ func CheckSignature(i *map[string]interface{}, name string) error {
 		forsign += fmt.Sprintf(`,%v`, val)
 	}
 
-	CheckSignResult, err := utils.CheckSign(sc.PublicKeys, forsign, hexsign, true)
+	CheckSignResult, err := utils.CheckSignType(crypto.KeyType(sc.TxSmart.KeyType), sc.PublicKeys, forsign, hexsign, true)
 	if err != nil {
 		return err
 	}
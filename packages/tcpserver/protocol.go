@@ -20,6 +20,8 @@ const (
 	RequestTypeNotFullNode     = 2
 	RequestTypeStopNetwork     = 3
 	RequestTypeConfirmation    = 4
+	RequestTypeVote            = 5
+	RequestTypeAttestation     = 6
 	RequestTypeBlockCollection = 7
 	RequestTypeMaxBlock        = 10
 )
@@ -59,6 +61,28 @@ type ConfirmResponse struct {
 	Hash     []byte `size:"32"`
 }
 
+// VoteRequest contains the finality vote of the node for the block
+type VoteRequest struct {
+	BlockID      uint32
+	Hash         []byte `size:"32"`
+	NodePosition uint32
+	Round        uint32
+	Sign         []byte
+}
+
+// AttestationRequest contains the identifier of the block which should be attested
+type AttestationRequest struct {
+	BlockID uint32
+}
+
+// AttestationResponse contains the finality attestation of the node for its block
+type AttestationResponse struct {
+	Hash         []byte `size:"32"`
+	NodePosition uint32
+	Round        uint32
+	Sign         []byte
+}
+
 // DisRequest contains request data
 type DisRequest struct {
 	Data []byte
@@ -84,6 +84,24 @@ func HandleTCPRequest(rw net.Conn) {
 			response, err = Type4(req)
 		}
 
+	case RequestTypeVote:
+		if service.IsNodePaused() {
+			return
+		}
+		req := &VoteRequest{}
+		if err = ReadRequest(req, rw); err == nil {
+			err = Type5(req)
+		}
+
+	case RequestTypeAttestation:
+		if service.IsNodePaused() {
+			return
+		}
+		req := &AttestationRequest{}
+		if err = ReadRequest(req, rw); err == nil {
+			response, err = Type6(req)
+		}
+
 	case RequestTypeBlockCollection:
 		req := &GetBodiesRequest{}
 		err = ReadRequest(req, rw)
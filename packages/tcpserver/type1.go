@@ -227,7 +227,7 @@ func saveNewTransactions(r *DisRequest) error {
 		queue = append(queue, &model.QueueTx{Hash: hash, Data: txBinData, FromGate: 1})
 	}
 
-	if err := model.BatchInsert(queue, []string{"hash", "data", "from_gate"}); err != nil {
+	if err := model.BatchInsert(nil, queue, []string{"hash", "data", "from_gate"}); err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("error creating QueueTx")
 		return err
 	}
@@ -0,0 +1,33 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package tcpserver
+
+import (
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
+)
+
+// Type5 registers the finality vote of other node
+// The request is sent by the node which has applied the block
+func Type5(r *VoteRequest) error {
+	return consensus.ReceiveVote(&consensus.Vote{
+		BlockID:      int64(r.BlockID),
+		Hash:         r.Hash,
+		NodePosition: int64(r.NodePosition),
+		Round:        int64(r.Round),
+		Sign:         r.Sign,
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package tcpserver
+
+import (
+	"github.com/GenesisKernel/go-genesis/packages/consensus"
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Type6 writes the signed finality attestation of the node for the specified block
+// The request is sent by 'confirmations' daemon
+func Type6(r *AttestationRequest) (*AttestationResponse, error) {
+	resp := &AttestationResponse{}
+	vote, err := consensus.Attest(int64(r.BlockID))
+	if err != nil || len(vote.Hash) != consts.HashSize {
+		log.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": r.BlockID}).Warning("attesting block")
+		hash := [consts.HashSize]byte{}
+		resp.Hash = hash[:]
+		return resp, nil
+	}
+	resp.Hash = vote.Hash
+	resp.NodePosition = uint32(vote.NodePosition)
+	resp.Round = uint32(vote.Round)
+	resp.Sign = vote.Sign
+	return resp, nil
+}
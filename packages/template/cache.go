@@ -0,0 +1,237 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"container/list"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/conf"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
+
+// maxParsedTemplates is the max count of parsed templates in the memory
+const maxParsedTemplates = 1000
+
+// parsedItem is a text or a call of the function in the parsed template
+type parsedItem struct {
+	text   string
+	macro  bool // text must be processed by macro
+	fn     *tplFunc
+	params *[][]rune
+	tails  *[]*[][]rune
+}
+
+// lruCache keeps the limited count of the recently used values
+type lruCache struct {
+	mutex sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+type lruItem struct {
+	key   string
+	value interface{}
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{size: size, items: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the value and marks it as recently used
+func (c *lruCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	item, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(item)
+	return item.Value.(*lruItem).value, true
+}
+
+// Add puts the value to the cache and removes the least recently used value if the cache is full
+func (c *lruCache) Add(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if item, ok := c.items[key]; ok {
+		item.Value.(*lruItem).value = value
+		c.order.MoveToFront(item)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruItem{key: key, value: value})
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*lruItem).key)
+	}
+}
+
+// blockState is the last block which is loaded once for the rendering of the template
+type blockState struct {
+	loaded bool
+	info   *model.InfoBlock
+}
+
+var (
+	parsed = newLRUCache(maxParsedTemplates)
+
+	queryCache     *lruCache
+	queryCacheOnce sync.Once
+)
+
+// parse returns the list of texts and function calls of the template. The result doesn't
+// depend on variables so it is parsed only once for the same set of components.
+func parse(input string, components *componentSet) []parsedItem {
+	key := input
+	if components != nil {
+		key = components.hash + key
+	}
+	if items, ok := parsed.Get(key); ok {
+		return items.([]parsedItem)
+	}
+	items := parseTemplate(input, components)
+	parsed.Add(key, items)
+	return items
+}
+
+func parseTemplate(input string, components *componentSet) []parsedItem {
+	var (
+		nameOff, shift int
+		params         *[][]rune
+		tailpars       *[]*[][]rune
+	)
+	items := make([]parsedItem, 0, 16)
+	name := make([]rune, 0, 128)
+	for off, ch := range input {
+		if shift > 0 {
+			shift--
+			continue
+		}
+		if ch == '(' {
+			curFunc, isFunc := funcs[string(name[nameOff:])]
+			if !isFunc && components != nil {
+				curFunc, isFunc = components.funcs[string(name[nameOff:])]
+			}
+			if isFunc {
+				items = append(items, parsedItem{text: string(name[:nameOff]), macro: true})
+				name = name[:0]
+				nameOff = 0
+				params, shift, tailpars = getFunc(input[off:], curFunc)
+				items = append(items, parsedItem{fn: &curFunc, params: params, tails: tailpars})
+				for off+shift+3 < len(input) && input[off+shift+1:off+shift+3] == `.(` {
+					var next int
+					params, next, tailpars = getFunc(input[off+shift+2:], curFunc)
+					items = append(items, parsedItem{fn: &curFunc, params: params, tails: tailpars})
+					shift += next + 2
+				}
+				continue
+			}
+		}
+		if (ch < 'A' || ch > 'Z') && (ch < 'a' || ch > 'z') {
+			nameOff = len(name) + 1
+		}
+		name = append(name, ch)
+	}
+	return append(items, parsedItem{text: string(name)})
+}
+
+// lastBlock returns the last block or nil if it is unknown
+func (w *Workspace) lastBlock() *model.InfoBlock {
+	if w.block == nil || w.SmartContract.VDE || model.DBConn == nil {
+		return nil
+	}
+	if !w.block.loaded {
+		w.block.loaded = true
+		info := &model.InfoBlock{}
+		if found, err := info.Get(); err == nil && found {
+			w.block.info = info
+		}
+	}
+	return w.block.info
+}
+
+// blockKey returns the identifier and the hash of the last block. The results of DBFind queries
+// are cached for this block only, so the cache is never checked for the changes of the tables.
+// It returns the empty string if the results can't be cached.
+func (w *Workspace) blockKey() string {
+	if conf.Config.TemplateCacheSize <= 0 {
+		return ``
+	}
+	info := w.lastBlock()
+	if info == nil {
+		return ``
+	}
+	return converter.Int64ToStr(info.BlockID) + `:` + hex.EncodeToString(info.Hash)
+}
+
+func getQueryCache() *lruCache {
+	queryCacheOnce.Do(func() {
+		queryCache = newLRUCache(conf.Config.TemplateCacheSize)
+	})
+	return queryCache
+}
+
+func copyRows(rows []map[string]string) []map[string]string {
+	ret := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		ret[i] = make(map[string]string, len(row))
+		for k, v := range row {
+			ret[i][k] = v
+		}
+	}
+	return ret
+}
+
+// queryRows returns the rows of DBFind query. The rows are copied, so the caller can modify them.
+func (w *Workspace) queryRows(query string, limit int, args []interface{}) ([]map[string]string, error) {
+	block := w.blockKey()
+	if len(block) == 0 {
+		return model.GetAll(query, limit, args...)
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%#v", block, query, limit, args)
+	if rows, ok := getQueryCache().Get(key); ok {
+		return copyRows(rows.([]map[string]string)), nil
+	}
+	rows, err := model.GetAll(query, limit, args...)
+	if err != nil {
+		return nil, err
+	}
+	getQueryCache().Add(key, copyRows(rows))
+	return rows, nil
+}
+
+// queryCount returns the count of rows of the table which match the condition
+func (w *Workspace) queryCount(table, where string, args []interface{}) (int64, error) {
+	var count int64
+	block := w.blockKey()
+	key := fmt.Sprintf("%s\x00count\x00%s\x00%s\x00%#v", block, table, where, args)
+	if len(block) > 0 {
+		if cached, ok := getQueryCache().Get(key); ok {
+			return cached.(int64), nil
+		}
+	}
+	err := model.GetDB(nil).Table(table).Where(where, args...).Count(&count).Error
+	if err == nil && len(block) > 0 {
+		getQueryCache().Add(key, count)
+	}
+	return count, err
+}
@@ -0,0 +1,53 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `Div(myclass){Span(#name#)}.Style(div {})text`
+	items := parse(input, nil)
+	if len(items) != 3 || items[1].fn == nil || items[1].fn.Tag != `div` || items[2].text != `text` {
+		t.Errorf(`wrong parsed template %v`, items)
+	}
+	if again := parse(input, nil); &again[0] != &items[0] {
+		t.Error(`template has been parsed twice`)
+	}
+
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache(2)
+	cache.Add(`a`, 1)
+	cache.Add(`b`, 2)
+	if _, ok := cache.Get(`a`); !ok {
+		t.Error(`value has not been found`)
+	}
+	cache.Add(`c`, 3)
+	if _, ok := cache.Get(`b`); ok {
+		t.Error(`least recently used value has not been removed`)
+	}
+	if val, ok := cache.Get(`a`); !ok || val.(int) != 1 {
+		t.Error(`recently used value has been removed`)
+	}
+	cache.Add(`c`, 4)
+	if val, _ := cache.Get(`c`); val.(int) != 4 || cache.order.Len() != 2 {
+		t.Error(`value has not been replaced`)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// componentSet contains the compiled components of the ecosystem
+type componentSet struct {
+	hash  string // hash of the sources of components
+	funcs map[string]tplFunc
+	// the last block when the components have been checked
+	blockID   int64
+	blockHash []byte
+}
+
+var (
+	componentSets  = make(map[string]*componentSet)
+	componentMutex = &sync.RWMutex{}
+)
+
+// isActual returns true if the components table hasn't been changed since the block of the set.
+// The table is checked only once for every new block.
+func (set *componentSet) isActual(ecosystem string, info *model.InfoBlock) bool {
+	componentMutex.RLock()
+	blockID, blockHash := set.blockID, set.blockHash
+	componentMutex.RUnlock()
+	if blockID == info.BlockID && bytes.Equal(blockHash, info.Hash) {
+		return true
+	}
+	if blockID > info.BlockID {
+		return false
+	}
+	// the block of the set has been rolled back
+	block := &model.Block{}
+	if found, err := block.Get(blockID); err != nil || !found || !bytes.Equal(block.Hash, blockHash) {
+		return false
+	}
+	changed, err := (&model.RollbackTx{}).IsChangedAfterBlock([]string{ecosystem + `_components`}, blockID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("checking changes of components")
+		return false
+	}
+	if changed {
+		return false
+	}
+	componentMutex.Lock()
+	set.blockID, set.blockHash = info.BlockID, info.Hash
+	componentMutex.Unlock()
+	return true
+}
+
+// getComponents returns the components of the ecosystem. They are loaded and compiled again
+// only if the components table has been changed after the last block when they have been checked.
+// info is the last block, the components are not cached if it is nil.
+func getComponents(ecosystem string, info *model.InfoBlock) *componentSet {
+	if model.DBConn == nil {
+		return nil
+	}
+	componentMutex.RLock()
+	set, ok := componentSets[ecosystem]
+	componentMutex.RUnlock()
+	if ok && info != nil && set.isActual(ecosystem, info) {
+		if len(set.funcs) == 0 {
+			return nil
+		}
+		return set
+	}
+	component := &model.Component{}
+	component.SetTablePrefix(ecosystem)
+	list, err := component.GetAll()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting components")
+		return nil
+	}
+	hash := sha256.New()
+	for _, item := range list {
+		fmt.Fprintf(hash, "%d\x00%s\x00%s\x00%s\x00", item.ID, item.Name, item.Params, item.Value)
+	}
+	set = newComponentSet(hex.EncodeToString(hash.Sum(nil)), list)
+	if info != nil {
+		set.blockID, set.blockHash = info.BlockID, info.Hash
+		componentMutex.Lock()
+		componentSets[ecosystem] = set
+		componentMutex.Unlock()
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return set
+}
+
+// newComponentSet compiles components into template functions. Components can't replace
+// the built-in functions.
+func newComponentSet(hash string, list []model.Component) *componentSet {
+	set := &componentSet{hash: hash, funcs: make(map[string]tplFunc)}
+	for _, item := range list {
+		if _, ok := funcs[item.Name]; ok {
+			continue
+		}
+		params, err := smart.ParseComponentParams(item.Params)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "name": item.Name}).Error("parsing parameters of component")
+			continue
+		}
+		names := make([]string, 0, len(params)+1)
+		for _, par := range params {
+			names = append(names, par.Name)
+		}
+		names = append(names, smart.ComponentBody)
+		set.funcs[item.Name] = tplFunc{componentTag(item.Name, item.Value, params), componentFull(item.Name),
+			`component`, strings.Join(names, `,`)}
+	}
+	return set
+}
+
+// componentTag returns the function which renders the component with its own variables.
+// The variables of the caller are visible inside the component but the changes are not.
+func componentTag(name, value string, params []smart.ComponentParam) nodeFunc {
+	return func(par parFunc) string {
+		if len((*par.Workspace.Vars)[`_include`]) >= 5 {
+			return ``
+		}
+		vars := make(map[string]string, len(*par.Workspace.Vars)+len(params))
+		for key, val := range *par.Workspace.Vars {
+			vars[key] = val
+		}
+		for _, item := range params {
+			val := macro((*par.Pars)[item.Name], par.Workspace.Vars)
+			if len(val) == 0 {
+				val = item.Default
+			}
+			if len(val) > 0 {
+				if err := item.Check(val); err != nil {
+					return fmt.Sprintf(`Component %s: %s`, name, err)
+				}
+			}
+			vars[item.Name] = val
+		}
+		vars[`_include`] += `1`
+		workspace := &Workspace{
+			Vars:          &vars,
+			Timeout:       par.Workspace.Timeout,
+			SmartContract: par.Workspace.SmartContract,
+			block:         par.Workspace.block,
+			components:    par.Workspace.components,
+			slot:          par.Node.Children,
+		}
+		if par.Workspace.Sources != nil {
+			for key, source := range *par.Workspace.Sources {
+				workspace.SetSource(key, &source)
+			}
+		}
+		root := node{}
+		process(value, &root, workspace)
+		for _, item := range root.Children {
+			if item.Tag == tagText {
+				item.Text = macro(item.Text, &vars)
+			}
+			par.Owner.Children = append(par.Owner.Children, item)
+		}
+		return ``
+	}
+}
+
+func componentFull(name string) nodeFunc {
+	return func(par parFunc) string {
+		defaultTag(par)
+		par.Node.Attr[`component`] = name
+		return ``
+	}
+}
+
+// slotTag inserts the content which has been passed to the component
+func slotTag(par parFunc) string {
+	for _, item := range par.Workspace.slot {
+		par.Owner.Children = append(par.Owner.Children, item)
+	}
+	return ``
+}
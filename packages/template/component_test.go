@@ -0,0 +1,69 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+)
+
+func TestComponents(t *testing.T) {
+	set := newComponentSet(`test`, []model.Component{
+		{Name: `Card`, Params: `[{"name":"Title"},{"name":"Count","type":"int","default":"1"}]`,
+			Value: `SetVar(inner, #Title#)Div(card){Strong(#Title# #Count#)Slot()}`},
+		{Name: `Div`, Value: `Span(replaced)`},
+		{Name: `Wrong`, Params: `[{"name":"Body"}]`},
+	})
+	if _, ok := set.funcs[`Div`]; ok {
+		t.Error(`component has replaced the built-in function`)
+	}
+	if _, ok := set.funcs[`Wrong`]; ok {
+		t.Error(`component with wrong parameters has been compiled`)
+	}
+
+	var timeout bool
+	for _, item := range forComponentTest {
+		vars := map[string]string{`_full`: `0`, `Title`: `outer`}
+		root := node{}
+		process(item.input, &root, &Workspace{Vars: &vars, Timeout: &timeout, components: set,
+			SmartContract: &smart.SmartContract{}})
+		out, err := json.Marshal(root.Children)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if string(out) != item.want {
+			t.Errorf("wrong json \r\n%s != \r\n%s", out, item.want)
+			return
+		}
+		if vars[`Title`] != `outer` || len(vars[`inner`]) > 0 {
+			t.Errorf(`variables of component have leaked %v`, vars)
+		}
+	}
+}
+
+var forComponentTest = tplList{
+	{`Card(Title: My card){Span(#Title#)}`,
+		`[{"tag":"div","attr":{"class":"card"},"children":[{"tag":"strong","children":[{"tag":"text","text":"My card 1"}]},{"tag":"span","children":[{"tag":"text","text":"outer"}]}]}]`},
+	{`Card(Second, 7)`,
+		`[{"tag":"div","attr":{"class":"card"},"children":[{"tag":"strong","children":[{"tag":"text","text":"Second 7"}]}]}]`},
+	{`Card(Title: Wrong, Count: seven)`,
+		`[{"tag":"text","text":"Component Card: parameter Count must be int"}]`},
+}
@@ -2,9 +2,16 @@ package template
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/GenesisKernel/go-genesis/packages/conf/syspar"
 	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/model/querycost"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -53,3 +60,204 @@ func (vl *valueLink) marshal() (string, error) {
 	}
 	return string(b), nil
 }
+
+const (
+	aggregateCount = `count`
+	aggregateSum   = `sum`
+	aggregateAvg   = `avg`
+	aggregateMin   = `min`
+	aggregateMax   = `max`
+)
+
+var (
+	errFilterColumn   = errors.New(`wrong filter column`)
+	errFilterOperator = errors.New(`wrong filter operator`)
+	errGroupColumn    = errors.New(`wrong group by column`)
+	errAggregate      = errors.New(`wrong aggregate column`)
+	errAggregateAlias = errors.New(`wrong aggregate alias`)
+	errQueryCost      = errors.New(`query is too expensive`)
+	errAccessDenied   = errors.New(`Access denied`)
+
+	dbfindOperators = map[string]string{
+		`=`: `=`, `!=`: `<>`, `<>`: `<>`, `<`: `<`, `<=`: `<=`, `>`: `>`, `>=`: `>=`,
+		`like`: `like`, `ilike`: `ilike`,
+	}
+	dbfindNumeric = map[string]bool{
+		`bigint`: true, `integer`: true, `smallint`: true, `numeric`: true,
+		`double precision`: true, `real`: true,
+	}
+	dbfindName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+type dbfindFilter struct {
+	column   string
+	operator string
+	value    string
+}
+
+type dbfindAggregate struct {
+	function string
+	column   string
+	alias    string
+}
+
+// filterTag appends Filter(Column, Operator, Value) condition to DBFind, the value is passed as a bound parameter
+func filterTag(par parFunc) string {
+	setAllAttr(par)
+	if len((*par.Pars)[`Column`]) == 0 {
+		return ``
+	}
+	operator := strings.ToLower(strings.TrimSpace(macro((*par.Pars)[`Operator`], par.Workspace.Vars)))
+	if len(operator) == 0 {
+		operator = `=`
+	}
+	filters, _ := par.Owner.Attr[`filters`].([]dbfindFilter)
+	par.Owner.Attr[`filters`] = append(filters, dbfindFilter{
+		column:   strings.ToLower(strings.TrimSpace(macro((*par.Pars)[`Column`], par.Workspace.Vars))),
+		operator: operator,
+		value:    macro((*par.Pars)[`Value`], par.Workspace.Vars),
+	})
+	return ``
+}
+
+// aggregateTag appends Sum, Avg, Min or Max(Column, As) column to DBFind
+func aggregateTag(par parFunc) string {
+	setAllAttr(par)
+	if len((*par.Pars)[`Column`]) == 0 {
+		return ``
+	}
+	aggregates, _ := par.Owner.Attr[`aggregates`].([]dbfindAggregate)
+	par.Owner.Attr[`aggregates`] = append(aggregates, dbfindAggregate{
+		function: par.Node.Tag,
+		column:   strings.ToLower(strings.TrimSpace(macro((*par.Pars)[`Column`], par.Workspace.Vars))),
+		alias:    strings.ToLower(strings.TrimSpace(macro((*par.Pars)[`As`], par.Workspace.Vars))),
+	})
+	return ``
+}
+
+// isAggregation returns true if DBFind has GroupBy or aggregate functions. In this case
+// Count(Name) adds count(*) column with the specified name instead of the count variable.
+func isAggregation(attr map[string]interface{}) bool {
+	return attr[`groupby`] != nil || attr[`aggregates`] != nil
+}
+
+// dbfindFilters returns the condition built from Filter tails, its bound parameters and the used columns
+func dbfindFilters(attr map[string]interface{}, columnTypes map[string]string) (string, []interface{}, []string, error) {
+	filters, _ := attr[`filters`].([]dbfindFilter)
+	if len(filters) == 0 {
+		return ``, nil, nil, nil
+	}
+	conds := make([]string, len(filters))
+	args := make([]interface{}, len(filters))
+	columns := make([]string, len(filters))
+	for i, item := range filters {
+		if _, ok := columnTypes[item.column]; !ok || !dbfindName.MatchString(item.column) {
+			return ``, nil, nil, errFilterColumn
+		}
+		operator, ok := dbfindOperators[item.operator]
+		if !ok {
+			return ``, nil, nil, errFilterOperator
+		}
+		conds[i] = fmt.Sprintf(`"%s" %s ?`, item.column, operator)
+		args[i] = item.value
+		columns[i] = item.column
+	}
+	return strings.Join(conds, ` and `), args, columns, nil
+}
+
+// dbfindAggregation returns the select list, the names of the result columns, the source columns
+// and the group by clause of the aggregating DBFind
+func dbfindAggregation(attr map[string]interface{}, columnTypes map[string]string) (string,
+	[]string, []string, string, error) {
+	var (
+		fields, names, columns, groups []string
+		groupBy                        string
+	)
+	used := make(map[string]bool)
+	if attr[`groupby`] != nil {
+		for _, col := range strings.Split(attr[`groupby`].(string), `,`) {
+			col = strings.ToLower(strings.TrimSpace(col))
+			if len(col) == 0 {
+				continue
+			}
+			ctype, ok := columnTypes[col]
+			if !ok || ctype == `bytea` || !dbfindName.MatchString(col) || used[col] {
+				return ``, nil, nil, ``, errGroupColumn
+			}
+			used[col] = true
+			fields = append(fields, `"`+col+`"`)
+			names = append(names, col)
+			columns = append(columns, col)
+			groups = append(groups, `"`+col+`"`)
+		}
+		if len(groups) > 0 {
+			groupBy = ` group by ` + strings.Join(groups, `, `)
+		}
+	}
+	aggregates, _ := attr[`aggregates`].([]dbfindAggregate)
+	if attr[`countvar`] != nil {
+		aggregates = append(aggregates, dbfindAggregate{function: aggregateCount,
+			alias: strings.ToLower(strings.TrimSpace(attr[`countvar`].(string)))})
+	}
+	for _, item := range aggregates {
+		expr := `*`
+		if item.function != aggregateCount || len(item.column) > 0 {
+			ctype, ok := columnTypes[item.column]
+			if !ok || ctype == `bytea` || !dbfindName.MatchString(item.column) {
+				return ``, nil, nil, ``, errAggregate
+			}
+			if (item.function == aggregateSum || item.function == aggregateAvg) && !dbfindNumeric[ctype] {
+				return ``, nil, nil, ``, errAggregate
+			}
+			expr = `"` + item.column + `"`
+			columns = append(columns, item.column)
+		}
+		alias := item.alias
+		if len(alias) == 0 {
+			alias = item.function
+			if len(item.column) > 0 {
+				alias += `_` + item.column
+			}
+		}
+		if !dbfindName.MatchString(alias) || used[alias] {
+			return ``, nil, nil, ``, errAggregateAlias
+		}
+		used[alias] = true
+		fields = append(fields, fmt.Sprintf(`%s(%s) as "%s"`, item.function, expr, alias))
+		names = append(names, alias)
+	}
+	if len(fields) == 0 {
+		return ``, nil, nil, ``, errAggregate
+	}
+	return strings.Join(fields, `, `), names, columns, groupBy, nil
+}
+
+// dbfindAccessColumns checks that all columns used in filters and aggregates are readable
+func dbfindAccessColumns(sc *smart.SmartContract, table string, columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	list := make([]string, len(columns))
+	copy(list, columns)
+	if err := sc.AccessColumns(table, &list, false); err != nil {
+		return err
+	}
+	if len(list) != len(columns) {
+		return errAccessDenied
+	}
+	return nil
+}
+
+// dbfindQueryCost prices the query through querycost, every group by column and aggregate function
+// adds the cost of scanning the table. The query is rejected if its cost exceeds max_fuel_tx.
+func dbfindQueryCost(query string, args []interface{}, scans int) (int64, error) {
+	cost, err := querycost.GetQueryCoster(querycost.FormulaQueryCosterType).QueryCost(nil, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	cost *= int64(scans + 1)
+	if maxCost := syspar.GetMaxTxFuel(); maxCost > 0 && cost > maxCost {
+		return cost, errQueryCost
+	}
+	return cost, nil
+}
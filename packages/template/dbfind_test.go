@@ -0,0 +1,76 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"testing"
+)
+
+var dbfindTestColumns = map[string]string{`id`: `bigint`, `name`: `character varying`,
+	`amount`: `numeric`, `data`: `bytea`}
+
+func TestDBFindFilters(t *testing.T) {
+	cond, args, cols, err := dbfindFilters(map[string]interface{}{`filters`: []dbfindFilter{
+		{column: `amount`, operator: `>=`, value: `10`},
+		{column: `name`, operator: `!=`, value: `x' or '1'='1`},
+	}}, dbfindTestColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond != `"amount" >= ? and "name" <> ?` || len(args) != 2 || args[1] != `x' or '1'='1` ||
+		len(cols) != 2 {
+		t.Errorf(`wrong filter %s %v %v`, cond, args, cols)
+	}
+	for _, item := range []dbfindFilter{{column: `unknown`, operator: `=`},
+		{column: `amount`, operator: `; drop`}} {
+		_, _, _, err = dbfindFilters(map[string]interface{}{`filters`: []dbfindFilter{item}}, dbfindTestColumns)
+		if err == nil {
+			t.Errorf(`filter %v must be rejected`, item)
+		}
+	}
+}
+
+func TestDBFindAggregation(t *testing.T) {
+	attr := map[string]interface{}{`groupby`: `Name`, `countvar`: `cnt`, `aggregates`: []dbfindAggregate{
+		{function: aggregateSum, column: `amount`, alias: `total`},
+		{function: aggregateMax, column: `id`},
+	}}
+	if !isAggregation(attr) {
+		t.Fatal(`aggregation has not been detected`)
+	}
+	fields, names, cols, groupBy, err := dbfindAggregation(attr, dbfindTestColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields != `"name", sum("amount") as "total", max("id") as "max_id", count(*) as "cnt"` {
+		t.Errorf(`wrong fields %s`, fields)
+	}
+	if groupBy != ` group by "name"` || len(names) != 4 || names[3] != `cnt` || len(cols) != 3 {
+		t.Errorf(`wrong aggregation %s %v %v`, groupBy, names, cols)
+	}
+	for _, item := range []map[string]interface{}{
+		{`groupby`: `data`},
+		{`groupby`: `name, name`},
+		{`aggregates`: []dbfindAggregate{{function: aggregateAvg, column: `name`}}},
+		{`aggregates`: []dbfindAggregate{{function: aggregateMin, column: `amount`, alias: `a"b`}}},
+		{`groupby`: `name`, `countvar`: `name`},
+	} {
+		if _, _, _, _, err = dbfindAggregation(item, dbfindTestColumns); err == nil {
+			t.Errorf(`aggregation %v must be rejected`, item)
+		}
+	}
+}
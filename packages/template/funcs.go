@@ -33,6 +33,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/language"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/template/syntax"
 
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
@@ -47,143 +48,165 @@ type Composite struct {
 var (
 	funcs = make(map[string]tplFunc)
 	tails = make(map[string]forTails)
-	modes = [][]rune{{'(', ')'}, {'{', '}'}}
 )
 
+// funcHandlers are the process functions of the built-in functions of templates,
+// the tags and the parameters of the functions are described by syntax.Funcs
+var funcHandlers = map[string]tplHandler{
+	`Lower`:              {lowerTag, defaultTag},
+	`AddToolButton`:      {defaultTailTag, defaultTailTag},
+	`Address`:            {addressTag, defaultTag},
+	`AppParam`:           {appparTag, defaultTag},
+	`Calculate`:          {calculateTag, defaultTag},
+	`CmpTime`:            {cmpTimeTag, defaultTag},
+	`Code`:               {defaultTag, defaultTag},
+	`CodeAsIs`:           {defaultTag, defaultTag},
+	`DateTime`:           {dateTimeTag, defaultTag},
+	`EcosysParam`:        {ecosysparTag, defaultTag},
+	`Em`:                 {defaultTag, defaultTag},
+	`GetVar`:             {getvarTag, defaultTag},
+	`GetContractHistory`: {getContractHistoryTag, defaultTag},
+	`GetMenuHistory`:     {getMenuHistoryTag, defaultTag},
+	`GetBlockHistory`:    {getBlockHistoryTag, defaultTag},
+	`GetPageHistory`:     {getPageHistoryTag, defaultTag},
+	`Hint`:               {defaultTag, defaultTag},
+	`ImageInput`:         {defaultTag, defaultTag},
+	`InputErr`:           {defaultTag, defaultTag},
+	`JsonToSource`:       {jsontosourceTag, defaultTag},
+	`ArrayToSource`:      {arraytosourceTag, defaultTag},
+	`LangRes`:            {langresTag, defaultTag},
+	`MenuGroup`:          {menugroupTag, defaultTag},
+	`MenuItem`:           {defaultTag, defaultTag},
+	`Now`:                {defaultTag, defaultTag},
+	`Money`:              {moneyTag, defaultTag},
+	`Range`:              {rangeTag, defaultTag},
+	`SetTitle`:           {defaultTag, defaultTag},
+	`SetVar`:             {setvarTag, defaultTag},
+	`Slot`:               {slotTag, defaultTag},
+	`Strong`:             {defaultTag, defaultTag},
+	`SysParam`:           {sysparTag, defaultTag},
+	`Button`:             {buttonTag, buttonTag},
+	`Div`:                {defaultTailTag, defaultTailTag},
+	`ForList`:            {forlistTag, defaultTag},
+	`Form`:               {defaultTailTag, defaultTailTag},
+	`If`:                 {ifTag, ifFull},
+	`Image`:              {imageTag, defaultTailTag},
+	`Include`:            {includeTag, defaultTag},
+	`Input`:              {defaultTailTag, defaultTailTag},
+	`Label`:              {defaultTailTag, defaultTailTag},
+	`LinkPage`:           {defaultTailTag, defaultTailTag},
+	`Data`:               {dataTag, defaultTailTag},
+	`DBFind`:             {dbfindTag, defaultTailTag},
+	`And`:                {andTag, defaultTag},
+	`Or`:                 {orTag, defaultTag},
+	`P`:                  {defaultTailTag, defaultTailTag},
+	`RadioGroup`:         {defaultTailTag, defaultTailTag},
+	`Span`:               {defaultTailTag, defaultTailTag},
+	`QRcode`:             {defaultTag, defaultTag},
+	`Table`:              {tableTag, defaultTailTag},
+	`Select`:             {defaultTailTag, defaultTailTag},
+	`Chart`:              {chartTag, defaultTailTag},
+	`InputMap`:           {defaultTailTag, defaultTailTag},
+	`Map`:                {defaultTag, defaultTag},
+	`Binary`:             {binaryTag, defaultTag},
+	`GetColumnType`:      {columntypeTag, defaultTag},
+}
+
+// tailHandlers are the process functions of the tail functions which are described by syntax.Tails
+var tailHandlers = map[string]map[string]tplHandler{
+	`addtoolbutton`: {
+		`Popup`: {popupTag, defaultTailFull},
+	},
+	`button`: {
+		`Alert`:             {alertTag, defaultTailFull},
+		`Popup`:             {popupTag, defaultTailFull},
+		`Style`:             {tailTag, defaultTailFull},
+		`CompositeContract`: {compositeTag, defaultTailFull},
+	},
+	`div`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`form`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`if`: {
+		`Else`:   {elseTag, elseFull},
+		`ElseIf`: {elseifTag, elseifFull},
+	},
+	`image`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`input`: {
+		`Validate`: {validateTag, validateFull},
+		`Style`:    {tailTag, defaultTailFull},
+	},
+	`label`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`linkpage`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`data`: {
+		`Custom`: {customTag, customTagFull},
+	},
+	`dbfind`: {
+		`Columns`:   {tailTag, defaultTailFull},
+		`Count`:     {tailTag, defaultTailFull},
+		`Where`:     {tailTag, defaultTailFull},
+		`WhereId`:   {tailTag, defaultTailFull},
+		`Order`:     {tailTag, defaultTailFull},
+		`Limit`:     {tailTag, defaultTailFull},
+		`Offset`:    {tailTag, defaultTailFull},
+		`Ecosystem`: {tailTag, defaultTailFull},
+		`Custom`:    {customTag, customTagFull},
+		`Vars`:      {tailTag, defaultTailFull},
+		`Cutoff`:    {tailTag, defaultTailFull},
+		`Filter`:    {filterTag, defaultTailFull},
+		`GroupBy`:   {tailTag, defaultTailFull},
+		`Sum`:       {aggregateTag, defaultTailFull},
+		`Avg`:       {aggregateTag, defaultTailFull},
+		`Min`:       {aggregateTag, defaultTailFull},
+		`Max`:       {aggregateTag, defaultTailFull},
+	},
+	`p`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`radiogroup`: {
+		`Validate`: {validateTag, validateFull},
+		`Style`:    {tailTag, defaultTailFull},
+	},
+	`span`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`table`: {
+		`Style`: {tailTag, defaultTailFull},
+	},
+	`select`: {
+		`Validate`: {validateTag, validateFull},
+		`Style`:    {tailTag, defaultTailFull},
+	},
+	`inputMap`: {
+		`Validate`: {validateTag, validateFull},
+	},
+	`binary`: {
+		`ById`:      {tailTag, defaultTailFull},
+		`Ecosystem`: {tailTag, defaultTailFull},
+	},
+}
+
 func init() {
-	funcs[`Lower`] = tplFunc{lowerTag, defaultTag, `lower`, `Text`}
-	funcs[`AddToolButton`] = tplFunc{defaultTailTag, defaultTailTag, `addtoolbutton`, `Title,Icon,Page,PageParams`}
-	funcs[`Address`] = tplFunc{addressTag, defaultTag, `address`, `Wallet`}
-	funcs[`AppParam`] = tplFunc{appparTag, defaultTag, `apppar`, `Name,App,Index,Source`}
-	funcs[`Calculate`] = tplFunc{calculateTag, defaultTag, `calculate`, `Exp,Type,Prec`}
-	funcs[`CmpTime`] = tplFunc{cmpTimeTag, defaultTag, `cmptime`, `Time1,Time2`}
-	funcs[`Code`] = tplFunc{defaultTag, defaultTag, `code`, `Text`}
-	funcs[`CodeAsIs`] = tplFunc{defaultTag, defaultTag, `code`, `#Text`}
-	funcs[`DateTime`] = tplFunc{dateTimeTag, defaultTag, `datetime`, `DateTime,Format`}
-	funcs[`EcosysParam`] = tplFunc{ecosysparTag, defaultTag, `ecosyspar`, `Name,Index,Source`}
-	funcs[`Em`] = tplFunc{defaultTag, defaultTag, `em`, `Body,Class`}
-	funcs[`GetVar`] = tplFunc{getvarTag, defaultTag, `getvar`, `Name`}
-	funcs[`GetContractHistory`] = tplFunc{getContractHistoryTag, defaultTag, `getcontracthistory`,
-		`Source,Id,RollbackId`}
-	funcs[`GetMenuHistory`] = tplFunc{getMenuHistoryTag, defaultTag, `getmenuhistory`,
-		`Source,Id,RollbackId`}
-	funcs[`GetBlockHistory`] = tplFunc{getBlockHistoryTag, defaultTag, `getblockhistory`,
-		`Source,Id,RollbackId`}
-	funcs[`GetPageHistory`] = tplFunc{getPageHistoryTag, defaultTag, `getpagehistory`,
-		`Source,Id,RollbackId`}
-	funcs[`Hint`] = tplFunc{defaultTag, defaultTag, `hint`, `Icon,Title,Text`}
-	funcs[`ImageInput`] = tplFunc{defaultTag, defaultTag, `imageinput`, `Name,Width,Ratio,Format`}
-	funcs[`InputErr`] = tplFunc{defaultTag, defaultTag, `inputerr`, `*`}
-	funcs[`JsonToSource`] = tplFunc{jsontosourceTag, defaultTag, `jsontosource`, `Source,Data`}
-	funcs[`ArrayToSource`] = tplFunc{arraytosourceTag, defaultTag, `arraytosource`, `Source,Data`}
-	funcs[`LangRes`] = tplFunc{langresTag, defaultTag, `langres`, `Name,Lang`}
-	funcs[`MenuGroup`] = tplFunc{menugroupTag, defaultTag, `menugroup`, `Title,Body,Icon`}
-	funcs[`MenuItem`] = tplFunc{defaultTag, defaultTag, `menuitem`, `Title,Page,PageParams,Icon,Vde`}
-	funcs[`Now`] = tplFunc{defaultTag, defaultTag, `now`, `Format,Interval`}
-	funcs[`Money`] = tplFunc{moneyTag, defaultTag, `money`, `Exp,Digit`}
-	funcs[`Range`] = tplFunc{rangeTag, defaultTag, `range`, `Source,From,To,Step`}
-	funcs[`SetTitle`] = tplFunc{defaultTag, defaultTag, `settitle`, `Title`}
-	funcs[`SetVar`] = tplFunc{setvarTag, defaultTag, `setvar`, `Name,Value`}
-	funcs[`Strong`] = tplFunc{defaultTag, defaultTag, `strong`, `Body,Class`}
-	funcs[`SysParam`] = tplFunc{sysparTag, defaultTag, `syspar`, `Name`}
-	funcs[`Button`] = tplFunc{buttonTag, buttonTag, `button`, `Body,Page,Class,Contract,Params,PageParams`}
-	funcs[`Div`] = tplFunc{defaultTailTag, defaultTailTag, `div`, `Class,Body`}
-	funcs[`ForList`] = tplFunc{forlistTag, defaultTag, `forlist`, `Source,Data,Index`}
-	funcs[`Form`] = tplFunc{defaultTailTag, defaultTailTag, `form`, `Class,Body`}
-	funcs[`If`] = tplFunc{ifTag, ifFull, `if`, `Condition,Body`}
-	funcs[`Image`] = tplFunc{imageTag, defaultTailTag, `image`, `Src,Alt,Class`}
-	funcs[`Include`] = tplFunc{includeTag, defaultTag, `include`, `Name`}
-	funcs[`Input`] = tplFunc{defaultTailTag, defaultTailTag, `input`, `Name,Class,Placeholder,Type,Value,Disabled`}
-	funcs[`Label`] = tplFunc{defaultTailTag, defaultTailTag, `label`, `Body,Class,For`}
-	funcs[`LinkPage`] = tplFunc{defaultTailTag, defaultTailTag, `linkpage`, `Body,Page,Class,PageParams`}
-	funcs[`Data`] = tplFunc{dataTag, defaultTailTag, `data`, `Source,Columns,Data`}
-	funcs[`DBFind`] = tplFunc{dbfindTag, defaultTailTag, `dbfind`, `Name,Source`}
-	funcs[`And`] = tplFunc{andTag, defaultTag, `and`, `*`}
-	funcs[`Or`] = tplFunc{orTag, defaultTag, `or`, `*`}
-	funcs[`P`] = tplFunc{defaultTailTag, defaultTailTag, `p`, `Body,Class`}
-	funcs[`RadioGroup`] = tplFunc{defaultTailTag, defaultTailTag, `radiogroup`, `Name,Source,NameColumn,ValueColumn,Value,Class`}
-	funcs[`Span`] = tplFunc{defaultTailTag, defaultTailTag, `span`, `Body,Class`}
-	funcs[`QRcode`] = tplFunc{defaultTag, defaultTag, `qrcode`, `Text`}
-	funcs[`Table`] = tplFunc{tableTag, defaultTailTag, `table`, `Source,Columns`}
-	funcs[`Select`] = tplFunc{defaultTailTag, defaultTailTag, `select`, `Name,Source,NameColumn,ValueColumn,Value,Class`}
-	funcs[`Chart`] = tplFunc{chartTag, defaultTailTag, `chart`, `Type,Source,FieldLabel,FieldValue,Colors`}
-	funcs[`InputMap`] = tplFunc{defaultTailTag, defaultTailTag, "inputMap", "Name,@Value,Type,MapType"}
-	funcs[`Map`] = tplFunc{defaultTag, defaultTag, "map", "@Value,MapType,Hmap"}
-	funcs[`Binary`] = tplFunc{binaryTag, defaultTag, "binary", "AppID,Name,MemberID"}
-	funcs[`GetColumnType`] = tplFunc{columntypeTag, defaultTag, `columntype`, `Table,Column`}
-
-	tails[`addtoolbutton`] = forTails{map[string]tailInfo{
-		`Popup`: {tplFunc{popupTag, defaultTailFull, `popup`, `Width,Header`}, true},
-	}}
-	tails[`button`] = forTails{map[string]tailInfo{
-		`Alert`:             {tplFunc{alertTag, defaultTailFull, `alert`, `Text,ConfirmButton,CancelButton,Icon`}, true},
-		`Popup`:             {tplFunc{popupTag, defaultTailFull, `popup`, `Width,Header`}, true},
-		`Style`:             {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-		`CompositeContract`: {tplFunc{compositeTag, defaultTailFull, `composite`, `Name,Data`}, false},
-	}}
-	tails[`div`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`form`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`if`] = forTails{map[string]tailInfo{
-		`Else`:   {tplFunc{elseTag, elseFull, `else`, `Body`}, true},
-		`ElseIf`: {tplFunc{elseifTag, elseifFull, `elseif`, `Condition,Body`}, false},
-	}}
-	tails[`image`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`input`] = forTails{map[string]tailInfo{
-		`Validate`: {tplFunc{validateTag, validateFull, `validate`, `*`}, false},
-		`Style`:    {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`label`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`linkpage`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`data`] = forTails{map[string]tailInfo{
-		`Custom`: {tplFunc{customTag, customTagFull, `custom`, `Column,Body`}, false},
-	}}
-	tails[`dbfind`] = forTails{map[string]tailInfo{
-		`Columns`:   {tplFunc{tailTag, defaultTailFull, `columns`, `Columns`}, false},
-		`Count`:     {tplFunc{tailTag, defaultTailFull, `count`, `CountVar`}, false},
-		`Where`:     {tplFunc{tailTag, defaultTailFull, `where`, `Where`}, false},
-		`WhereId`:   {tplFunc{tailTag, defaultTailFull, `whereid`, `WhereId`}, false},
-		`Order`:     {tplFunc{tailTag, defaultTailFull, `order`, `Order`}, false},
-		`Limit`:     {tplFunc{tailTag, defaultTailFull, `limit`, `Limit`}, false},
-		`Offset`:    {tplFunc{tailTag, defaultTailFull, `offset`, `Offset`}, false},
-		`Ecosystem`: {tplFunc{tailTag, defaultTailFull, `ecosystem`, `Ecosystem`}, false},
-		`Custom`:    {tplFunc{customTag, customTagFull, `custom`, `Column,Body`}, false},
-		`Vars`:      {tplFunc{tailTag, defaultTailFull, `vars`, `Prefix`}, false},
-		`Cutoff`:    {tplFunc{tailTag, defaultTailFull, `cutoff`, `Cutoff`}, false},
-	}}
-	tails[`p`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`radiogroup`] = forTails{map[string]tailInfo{
-		`Validate`: {tplFunc{validateTag, validateFull, `validate`, `*`}, false},
-		`Style`:    {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`span`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`table`] = forTails{map[string]tailInfo{
-		`Style`: {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`select`] = forTails{map[string]tailInfo{
-		`Validate`: {tplFunc{validateTag, validateFull, `validate`, `*`}, false},
-		`Style`:    {tplFunc{tailTag, defaultTailFull, `style`, `Style`}, false},
-	}}
-	tails[`inputMap`] = forTails{map[string]tailInfo{
-		`Validate`: {tplFunc{validateTag, validateFull, `validate`, `*`}, false},
-	}}
-	tails[`binary`] = forTails{map[string]tailInfo{
-		`ById`:      {tplFunc{tailTag, defaultTailFull, `id`, `id`}, false},
-		`Ecosystem`: {tplFunc{tailTag, defaultTailFull, `ecosystem`, `ecosystem`}, false},
-	}}
+	for name, sign := range syntax.Funcs {
+		handler := funcHandlers[name]
+		funcs[name] = tplFunc{handler.Func, handler.Full, sign.Tag, sign.Params}
+	}
+	for tag, list := range syntax.Tails {
+		tail := forTails{make(map[string]tailInfo, len(list))}
+		for name, sign := range list {
+			handler := tailHandlers[tag][name]
+			tail.Tails[name] = tailInfo{tplFunc{handler.Func, handler.Full, sign.Tag, sign.Params}, sign.Last}
+		}
+		tails[tag] = tail
+	}
 }
 
 func defaultTag(par parFunc) string {
@@ -396,13 +419,28 @@ func langresTag(par parFunc) string {
 	if len(lang) == 0 {
 		lang = (*par.Workspace.Vars)[`lang`]
 	}
-	ret, _ := language.LangText((*par.Pars)[`Name`],
-		int(converter.StrToInt64((*par.Workspace.Vars)[`ecosystem_id`])),
-		converter.StrToInt((*par.Workspace.Vars)[`app_id`]),
-		lang, par.Workspace.SmartContract.VDE)
+	state := int(converter.StrToInt64((*par.Workspace.Vars)[`ecosystem_id`]))
+	appID := converter.StrToInt((*par.Workspace.Vars)[`app_id`])
+	if len((*par.Pars)[`Params`]) == 0 {
+		ret, _ := language.LangText((*par.Pars)[`Name`], state, appID, lang, par.Workspace.SmartContract.VDE)
+		return ret
+	}
+	ret, _ := language.LangFormat((*par.Pars)[`Name`], state, appID, lang, par.Workspace.SmartContract.VDE,
+		langParams((*par.Pars)[`Params`], par.Workspace.Vars))
 	return ret
 }
 
+// langParams parses the parameters of LangRes like 'count=#count#, name=John'
+func langParams(input string, vars *map[string]string) map[string]string {
+	params := make(map[string]string)
+	for _, item := range strings.Split(input, `,`) {
+		if off := strings.IndexByte(item, '='); off > 0 {
+			params[strings.TrimSpace(item[:off])] = macro(strings.TrimSpace(item[off+1:]), vars)
+		}
+	}
+	return params
+}
+
 func sysparTag(par parFunc) (ret string) {
 	if len((*par.Pars)[`Name`]) > 0 {
 		ret = syspar.SysString(macro((*par.Pars)[`Name`], par.Workspace.Vars))
@@ -602,6 +640,20 @@ func dbfindTag(par parFunc) string {
 	if err != nil || sc.AccessColumns(tblname, &fieldsList, false) != nil {
 		return `Access denied`
 	}
+	filter, args, filterColumns, err := dbfindFilters(par.Node.Attr, columnTypes)
+	if err != nil {
+		return err.Error()
+	}
+	if dbfindAccessColumns(sc, tblname, filterColumns) != nil {
+		return `Access denied`
+	}
+	if len(filter) > 0 {
+		if len(where) > 0 {
+			where = ` where (` + strings.TrimPrefix(where, ` where `) + `) and ` + filter
+		} else {
+			where = ` where ` + filter
+		}
+	}
 	fields = strings.Join(fieldsList, `,`)
 
 	if fields != "*" {
@@ -644,9 +696,29 @@ func dbfindTag(par parFunc) string {
 		}
 		columnNames[i] = strings.TrimSpace(columnNames[i])
 	}
+	var (
+		groupBy string
+		scans   int
+	)
+	if isAggregation(par.Node.Attr) {
+		var aggColumns []string
+		fields, columnNames, aggColumns, groupBy, err = dbfindAggregation(par.Node.Attr, columnTypes)
+		if err != nil {
+			return err.Error()
+		}
+		if dbfindAccessColumns(sc, tblname, aggColumns) != nil {
+			return `Access denied`
+		}
+		extendedColumns = make(map[string]string)
+		scans = len(columnNames)
+		delete(par.Node.Attr, `countvar`)
+	}
+	query := `select ` + fields + ` from "` + tblname + `"` + where + groupBy + order + offset
+	if _, err = dbfindQueryCost(query, args, scans); err != nil {
+		return err.Error()
+	}
 	if par.Node.Attr[`countvar`] != nil {
-		var count int64
-		err = model.GetDB(nil).Table(tblname).Where(strings.Replace(where, `where`, ``, 1)).Count(&count).Error
+		count, err := par.Workspace.queryCount(tblname, strings.Replace(where, `where`, ``, 1), args)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting count from table in DBFind")
 		}
@@ -655,7 +727,7 @@ func dbfindTag(par parFunc) string {
 		(*par.Workspace.Vars)[par.Node.Attr[`countvar`].(string)] = countStr
 		delete(par.Node.Attr, `countvar`)
 	}
-	list, err := model.GetAll(`select `+fields+` from "`+tblname+`"`+where+order+offset, limit)
+	list, err := par.Workspace.queryRows(query, limit, args)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all from db")
 		return err.Error()
@@ -765,6 +837,8 @@ func dbfindTag(par parFunc) string {
 	delete(par.Node.Attr, `customs`)
 	delete(par.Node.Attr, `custombody`)
 	delete(par.Node.Attr, `prefix`)
+	delete(par.Node.Attr, `filters`)
+	delete(par.Node.Attr, `aggregates`)
 	par.Node.Attr[`columns`] = &columnNames
 	par.Node.Attr[`types`] = &types
 	par.Node.Attr[`data`] = &data
@@ -0,0 +1,479 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultTheme is the name of the theme which is used if the theme is not specified
+	DefaultTheme = `default`
+	// PlainTheme is the name of the theme without any styles
+	PlainTheme = `plain`
+
+	columnTypeTags = `tags`
+)
+
+// Theme defines the appearance of the rendered HTML
+type Theme interface {
+	// Name returns the name of the theme
+	Name() string
+	// Head returns the markup which is inserted into the head of HTML document
+	Head() string
+	// Class returns CSS classes for the element of the template tag
+	Class(tag string) string
+}
+
+// ClassTheme is a theme which assigns CSS classes to the elements
+type ClassTheme struct {
+	ThemeName string
+	HeadHTML  string
+	Classes   map[string]string
+}
+
+// Name returns the name of the theme
+func (t *ClassTheme) Name() string {
+	return t.ThemeName
+}
+
+// Head returns the markup of the head
+func (t *ClassTheme) Head() string {
+	return t.HeadHTML
+}
+
+// Class returns CSS classes of the tag
+func (t *ClassTheme) Class(tag string) string {
+	return t.Classes[tag]
+}
+
+var (
+	themes     = make(map[string]Theme)
+	themeMutex = &sync.RWMutex{}
+)
+
+func init() {
+	RegisterTheme(&ClassTheme{ThemeName: PlainTheme})
+	RegisterTheme(&ClassTheme{
+		ThemeName: DefaultTheme,
+		HeadHTML: `<style>body{font-family:sans-serif;margin:1em}table{border-collapse:collapse}` +
+			`th,td{border:1px solid #ccc;padding:4px 8px;text-align:left}.hint{color:#666}</style>`,
+		Classes: map[string]string{
+			`table`:  `table table-striped`,
+			`form`:   `form`,
+			`button`: `btn btn-default`,
+			`chart`:  `chart`,
+			`input`:  `form-control`,
+			`select`: `form-control`,
+			`hint`:   `hint`,
+		},
+	})
+}
+
+// RegisterTheme adds the theme for HTML rendering. The theme with the same name is replaced.
+func RegisterTheme(theme Theme) {
+	themeMutex.Lock()
+	defer themeMutex.Unlock()
+	themes[theme.Name()] = theme
+}
+
+// GetTheme returns the theme with the specified name or nil if it doesn't exist
+func GetTheme(name string) Theme {
+	if len(name) == 0 {
+		name = DefaultTheme
+	}
+	themeMutex.RLock()
+	defer themeMutex.RUnlock()
+	return themes[name]
+}
+
+// htmlSource is the data source which can be displayed by Table, Chart, Select and RadioGroup
+type htmlSource struct {
+	columns []string
+	types   []string
+	data    [][]string
+}
+
+func (s *htmlSource) column(name string) int {
+	for i, col := range s.columns {
+		if col == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type htmlRenderer struct {
+	out     *bytes.Buffer
+	theme   Theme
+	sources map[string]*htmlSource
+	title   string
+}
+
+// htmlTags maps the template tags to HTML elements which have only classes and children
+var htmlTags = map[string]string{
+	`div`:    `div`,
+	`p`:      `p`,
+	`span`:   `span`,
+	`em`:     `em`,
+	`strong`: `strong`,
+	`form`:   `form`,
+}
+
+func attrString(n *node, name string) string {
+	if v, ok := n.Attr[name].(string); ok {
+		return v
+	}
+	return ``
+}
+
+// safeURL returns the link if it is relative or has http(s) scheme and an empty string otherwise
+func safeURL(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil {
+		return ``
+	}
+	switch strings.ToLower(u.Scheme) {
+	case ``, `http`, `https`:
+		return link
+	}
+	return ``
+}
+
+// safeStyle drops the styles which can run scripts or load external resources
+func safeStyle(style string) string {
+	lower := strings.ToLower(style)
+	for _, bad := range []string{`url(`, `expression`, `javascript:`, `@import`, `<`, `>`, `\`} {
+		if strings.Contains(lower, bad) {
+			return ``
+		}
+	}
+	return style
+}
+
+// pageURL returns the link to HTML version of the page
+func pageURL(page string, pars interface{}) string {
+	values := url.Values{}
+	values.Set(`format`, `html`)
+	if imap, ok := pars.(map[string]interface{}); ok {
+		for key, v := range imap {
+			if par, ok := v.(map[string]interface{}); ok {
+				if text, ok := par[`text`].(string); ok {
+					values.Set(key, text)
+				}
+			}
+		}
+	}
+	return url.PathEscape(page) + `?` + values.Encode()
+}
+
+func (r *htmlRenderer) write(s ...string) {
+	for _, item := range s {
+		r.out.WriteString(item)
+	}
+}
+
+func (r *htmlRenderer) text(s string) {
+	r.out.WriteString(html.EscapeString(s))
+}
+
+// open writes the start tag with the theme classes, the class and the style of the node and
+// the additional attributes as name-value pairs
+func (r *htmlRenderer) open(tag, themeTag string, n *node, attrs ...string) {
+	r.write(`<`, tag)
+	classes := make([]string, 0, 2)
+	if class := r.theme.Class(themeTag); len(class) > 0 {
+		classes = append(classes, class)
+	}
+	if n != nil {
+		if class := attrString(n, `class`); len(class) > 0 {
+			classes = append(classes, class)
+		}
+	}
+	if len(classes) > 0 {
+		attrs = append(attrs, `class`, strings.Join(classes, ` `))
+	}
+	if n != nil {
+		if style := safeStyle(attrString(n, `style`)); len(style) > 0 {
+			attrs = append(attrs, `style`, style)
+		}
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if len(attrs[i+1]) == 0 {
+			continue
+		}
+		r.write(` `, attrs[i], `="`, html.EscapeString(attrs[i+1]), `"`)
+	}
+	r.write(`>`)
+}
+
+func (r *htmlRenderer) element(tag, themeTag string, n *node, attrs ...string) {
+	r.open(tag, themeTag, n, attrs...)
+	r.nodes(n.Children)
+	r.write(`</`, tag, `>`)
+}
+
+func (r *htmlRenderer) nodes(list []*node) {
+	for _, n := range list {
+		r.node(n)
+	}
+}
+
+func (r *htmlRenderer) node(n *node) {
+	if tag, ok := htmlTags[n.Tag]; ok {
+		r.element(tag, n.Tag, n)
+		return
+	}
+	switch n.Tag {
+	case tagText:
+		r.text(n.Text)
+	case `settitle`:
+		r.title = attrString(n, `title`)
+	case `code`:
+		r.open(`pre`, n.Tag, n)
+		r.write(`<code>`)
+		r.text(attrString(n, `text`))
+		r.write(`</code></pre>`)
+	case `label`:
+		r.element(`label`, n.Tag, n, `for`, attrString(n, `for`))
+	case `linkpage`:
+		r.element(`a`, n.Tag, n, `href`, pageURL(attrString(n, `page`), n.Attr[`pageparams`]))
+	case `button`:
+		if page := attrString(n, `page`); len(page) > 0 && n.Attr[`contract`] == nil {
+			r.element(`a`, n.Tag, n, `href`, pageURL(page, n.Attr[`pageparams`]), `role`, `button`)
+		} else {
+			// contracts can't be executed without the client so the button is displayed disabled
+			r.open(`button`, n.Tag, n, `type`, `button`, `disabled`, `disabled`)
+			r.nodes(n.Children)
+			r.write(`</button>`)
+		}
+	case `image`:
+		r.open(`img`, n.Tag, n, `src`, safeURL(attrString(n, `src`)), `alt`, attrString(n, `alt`))
+	case `input`:
+		r.open(`input`, n.Tag, n, `name`, attrString(n, `name`), `id`, attrString(n, `name`),
+			`type`, attrString(n, `type`), `value`, attrString(n, `value`),
+			`placeholder`, attrString(n, `placeholder`), `aria-label`, attrString(n, `placeholder`),
+			`disabled`, attrString(n, `disabled`))
+	case `select`:
+		r.selectTag(n)
+	case `radiogroup`:
+		r.radioGroup(n)
+	case `table`:
+		r.table(n)
+	case `chart`:
+		r.chart(n)
+	case `hint`:
+		r.open(`div`, n.Tag, n, `role`, `note`)
+		if title := attrString(n, `title`); len(title) > 0 {
+			r.write(`<strong>`)
+			r.text(title)
+			r.write(`</strong> `)
+		}
+		r.text(attrString(n, `text`))
+		r.write(`</div>`)
+	case `if`, `else`, `elseif`, `include`:
+		r.nodes(n.Children)
+		r.nodes(n.Tail)
+	default:
+		if n.Attr[`source`] != nil && n.Attr[`data`] != nil {
+			r.addSource(n)
+			return
+		}
+		// menu items, maps, popups and other interactive elements are skipped
+		// but their contents are displayed
+		r.nodes(n.Children)
+	}
+}
+
+func (r *htmlRenderer) addSource(n *node) {
+	source := &htmlSource{}
+	if cols, ok := n.Attr[`columns`].(*[]string); ok {
+		source.columns = *cols
+	}
+	if types, ok := n.Attr[`types`].(*[]string); ok {
+		source.types = *types
+	}
+	if data, ok := n.Attr[`data`].(*[][]string); ok {
+		source.data = *data
+	}
+	r.sources[attrString(n, `source`)] = source
+}
+
+// value writes the value of the source cell. The values of tags type contain JSON tree of nodes.
+func (r *htmlRenderer) value(source *htmlSource, row []string, col int) {
+	if col < 0 || col >= len(row) {
+		return
+	}
+	if col < len(source.types) && source.types[col] == columnTypeTags {
+		var list []*node
+		if err := json.Unmarshal([]byte(row[col]), &list); err == nil {
+			r.nodes(list)
+			return
+		}
+	}
+	r.text(row[col])
+}
+
+func (r *htmlRenderer) table(n *node) {
+	source := r.sources[attrString(n, `source`)]
+	if source == nil {
+		return
+	}
+	type column struct {
+		title string
+		index int
+	}
+	columns := make([]column, 0, len(source.columns))
+	if list, ok := n.Attr[`columns`].([]map[string]string); ok {
+		for _, item := range list {
+			columns = append(columns, column{item[`Title`], source.column(item[`Name`])})
+		}
+	} else {
+		for i, name := range source.columns {
+			columns = append(columns, column{name, i})
+		}
+	}
+	r.open(`table`, n.Tag, n)
+	r.write(`<thead><tr>`)
+	for _, col := range columns {
+		r.write(`<th scope="col">`)
+		r.text(col.title)
+		r.write(`</th>`)
+	}
+	r.write(`</tr></thead><tbody>`)
+	for _, row := range source.data {
+		r.write(`<tr>`)
+		for _, col := range columns {
+			r.write(`<td>`)
+			r.value(source, row, col.index)
+			r.write(`</td>`)
+		}
+		r.write(`</tr>`)
+	}
+	r.write(`</tbody></table>`)
+}
+
+// chart is displayed as a table of labels and values because charts require scripts
+func (r *htmlRenderer) chart(n *node) {
+	source := r.sources[attrString(n, `source`)]
+	if source == nil {
+		return
+	}
+	label := source.column(attrString(n, `fieldlabel`))
+	value := source.column(attrString(n, `fieldvalue`))
+	r.open(`figure`, n.Tag, n)
+	r.write(`<table><caption>`)
+	r.text(attrString(n, `type`))
+	r.write(`</caption><tbody>`)
+	for _, row := range source.data {
+		r.write(`<tr><th scope="row">`)
+		r.value(source, row, label)
+		r.write(`</th><td>`)
+		r.value(source, row, value)
+		r.write(`</td></tr>`)
+	}
+	r.write(`</tbody></table></figure>`)
+}
+
+// options calls fn for the name and the value of every row of the source
+func (r *htmlRenderer) options(n *node, fn func(name, value string)) {
+	source := r.sources[attrString(n, `source`)]
+	if source == nil {
+		return
+	}
+	nameCol := source.column(attrString(n, `namecolumn`))
+	valueCol := source.column(attrString(n, `valuecolumn`))
+	for _, row := range source.data {
+		var name, value string
+		if nameCol >= 0 && nameCol < len(row) {
+			name = row[nameCol]
+		}
+		if valueCol >= 0 && valueCol < len(row) {
+			value = row[valueCol]
+		}
+		fn(name, value)
+	}
+}
+
+func (r *htmlRenderer) selectTag(n *node) {
+	selected := attrString(n, `value`)
+	r.open(`select`, n.Tag, n, `name`, attrString(n, `name`), `id`, attrString(n, `name`))
+	r.options(n, func(name, value string) {
+		r.write(`<option value="`, html.EscapeString(value), `"`)
+		if value == selected {
+			r.write(` selected`)
+		}
+		r.write(`>`)
+		r.text(name)
+		r.write(`</option>`)
+	})
+	r.write(`</select>`)
+}
+
+func (r *htmlRenderer) radioGroup(n *node) {
+	selected := attrString(n, `value`)
+	name := attrString(n, `name`)
+	r.open(`fieldset`, n.Tag, n)
+	r.options(n, func(title, value string) {
+		r.write(`<label><input type="radio" name="`, html.EscapeString(name), `" value="`,
+			html.EscapeString(value), `"`)
+		if value == selected {
+			r.write(` checked`)
+		}
+		r.write(`> `)
+		r.text(title)
+		r.write(`</label>`)
+	})
+	r.write(`</fieldset>`)
+}
+
+// nodes2HTML renders the tree of nodes. It returns HTML and the title of the page if it has been set.
+func nodes2HTML(list []*node, theme Theme) ([]byte, string) {
+	r := &htmlRenderer{out: &bytes.Buffer{}, theme: theme, sources: make(map[string]*htmlSource)}
+	r.nodes(list)
+	return r.out.Bytes(), r.title
+}
+
+// Template2HTML converts templates to HTML document with the specified theme
+func Template2HTML(input string, timeout *bool, vars *map[string]string, theme Theme) []byte {
+	root := template2Node(input, timeout, vars)
+	body, title := nodes2HTML(root.Children, theme)
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, `<!DOCTYPE html><html lang="%s"><head><meta charset="utf-8">`+
+		`<meta name="viewport" content="width=device-width, initial-scale=1"><title>%s</title>%s</head><body>`,
+		html.EscapeString(htmlLang((*vars)[`lang`])), html.EscapeString(title), theme.Head())
+	out.Write(body)
+	out.WriteString(`</body></html>`)
+	return out.Bytes()
+}
+
+// htmlLang returns the main language from the list of languages like Accept-Language header
+func htmlLang(lang string) string {
+	lang = strings.SplitN(lang, `,`, 2)[0]
+	lang = strings.TrimSpace(strings.SplitN(lang, `;`, 2)[0])
+	if len(lang) == 0 {
+		return `en`
+	}
+	return lang
+}
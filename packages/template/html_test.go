@@ -0,0 +1,66 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	var timeout bool
+	vars := map[string]string{`_full`: `0`, `lang`: `ru-RU,ru;q=0.8`}
+	theme := GetTheme(PlainTheme)
+	for _, item := range forHTMLTest {
+		out := string(Template2HTML(item.input, &timeout, &vars, theme))
+		if !strings.HasPrefix(out, `<!DOCTYPE html><html lang="ru-RU">`) || !strings.HasSuffix(out, `</body></html>`) {
+			t.Errorf(`wrong html document %s`, out)
+			return
+		}
+		body := out[strings.Index(out, `<body>`)+6 : len(out)-len(`</body></html>`)]
+		if body != item.want {
+			t.Errorf("wrong html \r\n%s != \r\n%s", body, item.want)
+			return
+		}
+	}
+	if GetTheme(``) == nil || GetTheme(`unknown`) != nil {
+		t.Error(`wrong themes`)
+	}
+	out := string(Template2HTML(`SetTitle(My <page>)Form(){Button(Body: Save, Contract: Save)}`, &timeout, &vars, GetTheme(``)))
+	if !strings.Contains(out, `<title>My &lt;page&gt;</title>`) ||
+		!strings.Contains(out, `<form class="form"><button type="button" disabled="disabled" class="btn btn-default">Save</button></form>`) {
+		t.Errorf(`wrong default theme %s`, out)
+	}
+}
+
+var forHTMLTest = tplList{
+	{`Div(myclass, Simple text +=<b>bold</b>)`, `<div class="myclass">Simple text +=&lt;b&gt;bold&lt;/b&gt;</div>`},
+	{`LinkPage(My page,mypage,,"myvar1=Value 1")`, `<a href="mypage?format=html&amp;myvar1=Value+1">My page</a>`},
+	{`Image(javascript:alert(1),Photo)Image(/img.png,Photo).Style(width:100px;)`,
+		`<img alt="Photo"><img src="/img.png" alt="Photo" style="width:100px;">`},
+	{`Data(mysrc,"id,name"){
+		1,first
+		2,<second>
+	}Table(mysrc,"Name=name")`,
+		`<table><thead><tr><th scope="col">Name</th></tr></thead><tbody><tr><td>first</td></tr><tr><td>&lt;second&gt;</td></tr></tbody></table>`},
+	{`Data(mysrc,"id,name"){
+		1,first
+		2,second
+	}Select(myselect,mysrc,name,id,2)`,
+		`<select name="myselect" id="myselect"><option value="1">first</option><option value="2" selected>second</option></select>`},
+	{`If(true){Strong(yes)}.Else{no}`, `<strong>yes</strong>`},
+}
@@ -0,0 +1,343 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package syntax describes the signatures of the template functions and checks the source
+// of templates. It doesn't depend on the database and the other packages of the node, so it
+// can be used both by the template engine and by the contracts.
+package syntax
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Func describes the signature of the template function
+type Func struct {
+	Tag    string // HTML tag
+	Params string // names of parameters
+}
+
+// Tail describes the function which can follow the main function after the point
+type Tail struct {
+	Func
+	Last bool
+}
+
+var modes = [][]rune{{'(', ')'}, {'{', '}'}}
+
+// Funcs are the built-in functions of templates. The template engine takes their signatures from here
+// and adds the process functions to them
+var Funcs = map[string]Func{
+	`Lower`:              {`lower`, `Text`},
+	`AddToolButton`:      {`addtoolbutton`, `Title,Icon,Page,PageParams`},
+	`Address`:            {`address`, `Wallet`},
+	`AppParam`:           {`apppar`, `Name,App,Index,Source`},
+	`Calculate`:          {`calculate`, `Exp,Type,Prec`},
+	`CmpTime`:            {`cmptime`, `Time1,Time2`},
+	`Code`:               {`code`, `Text`},
+	`CodeAsIs`:           {`code`, `#Text`},
+	`DateTime`:           {`datetime`, `DateTime,Format`},
+	`EcosysParam`:        {`ecosyspar`, `Name,Index,Source`},
+	`Em`:                 {`em`, `Body,Class`},
+	`GetVar`:             {`getvar`, `Name`},
+	`GetContractHistory`: {`getcontracthistory`, `Source,Id,RollbackId`},
+	`GetMenuHistory`:     {`getmenuhistory`, `Source,Id,RollbackId`},
+	`GetBlockHistory`:    {`getblockhistory`, `Source,Id,RollbackId`},
+	`GetPageHistory`:     {`getpagehistory`, `Source,Id,RollbackId`},
+	`Hint`:               {`hint`, `Icon,Title,Text`},
+	`ImageInput`:         {`imageinput`, `Name,Width,Ratio,Format`},
+	`InputErr`:           {`inputerr`, `*`},
+	`JsonToSource`:       {`jsontosource`, `Source,Data`},
+	`ArrayToSource`:      {`arraytosource`, `Source,Data`},
+	`LangRes`:            {`langres`, `Name,Lang,Params`},
+	`MenuGroup`:          {`menugroup`, `Title,Body,Icon`},
+	`MenuItem`:           {`menuitem`, `Title,Page,PageParams,Icon,Vde`},
+	`Now`:                {`now`, `Format,Interval`},
+	`Money`:              {`money`, `Exp,Digit`},
+	`Range`:              {`range`, `Source,From,To,Step`},
+	`SetTitle`:           {`settitle`, `Title`},
+	`SetVar`:             {`setvar`, `Name,Value`},
+	`Slot`:               {`slot`, ``},
+	`Strong`:             {`strong`, `Body,Class`},
+	`SysParam`:           {`syspar`, `Name`},
+	`Button`:             {`button`, `Body,Page,Class,Contract,Params,PageParams`},
+	`Div`:                {`div`, `Class,Body`},
+	`ForList`:            {`forlist`, `Source,Data,Index`},
+	`Form`:               {`form`, `Class,Body`},
+	`If`:                 {`if`, `Condition,Body`},
+	`Image`:              {`image`, `Src,Alt,Class`},
+	`Include`:            {`include`, `Name`},
+	`Input`:              {`input`, `Name,Class,Placeholder,Type,Value,Disabled`},
+	`Label`:              {`label`, `Body,Class,For`},
+	`LinkPage`:           {`linkpage`, `Body,Page,Class,PageParams`},
+	`Data`:               {`data`, `Source,Columns,Data`},
+	`DBFind`:             {`dbfind`, `Name,Source`},
+	`And`:                {`and`, `*`},
+	`Or`:                 {`or`, `*`},
+	`P`:                  {`p`, `Body,Class`},
+	`RadioGroup`:         {`radiogroup`, `Name,Source,NameColumn,ValueColumn,Value,Class`},
+	`Span`:               {`span`, `Body,Class`},
+	`QRcode`:             {`qrcode`, `Text`},
+	`Table`:              {`table`, `Source,Columns`},
+	`Select`:             {`select`, `Name,Source,NameColumn,ValueColumn,Value,Class`},
+	`Chart`:              {`chart`, `Type,Source,FieldLabel,FieldValue,Colors`},
+	`InputMap`:           {`inputMap`, `Name,@Value,Type,MapType`},
+	`Map`:                {`map`, `@Value,MapType,Hmap`},
+	`Binary`:             {`binary`, `AppID,Name,MemberID`},
+	`GetColumnType`:      {`columntype`, `Table,Column`},
+}
+
+// Tails are the tail functions by the tags of the main functions, the template engine takes them from here
+var Tails = map[string]map[string]Tail{
+	`addtoolbutton`: {
+		`Popup`: {Func{`popup`, `Width,Header`}, true},
+	},
+	`button`: {
+		`Alert`:             {Func{`alert`, `Text,ConfirmButton,CancelButton,Icon`}, true},
+		`Popup`:             {Func{`popup`, `Width,Header`}, true},
+		`Style`:             {Func{`style`, `Style`}, false},
+		`CompositeContract`: {Func{`composite`, `Name,Data`}, false},
+	},
+	`div`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`form`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`if`: {
+		`Else`:   {Func{`else`, `Body`}, true},
+		`ElseIf`: {Func{`elseif`, `Condition,Body`}, false},
+	},
+	`image`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`input`: {
+		`Validate`: {Func{`validate`, `*`}, false},
+		`Style`:    {Func{`style`, `Style`}, false},
+	},
+	`label`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`linkpage`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`data`: {
+		`Custom`: {Func{`custom`, `Column,Body`}, false},
+	},
+	`dbfind`: {
+		`Columns`:   {Func{`columns`, `Columns`}, false},
+		`Count`:     {Func{`count`, `CountVar`}, false},
+		`Where`:     {Func{`where`, `Where`}, false},
+		`WhereId`:   {Func{`whereid`, `WhereId`}, false},
+		`Order`:     {Func{`order`, `Order`}, false},
+		`Limit`:     {Func{`limit`, `Limit`}, false},
+		`Offset`:    {Func{`offset`, `Offset`}, false},
+		`Ecosystem`: {Func{`ecosystem`, `Ecosystem`}, false},
+		`Custom`:    {Func{`custom`, `Column,Body`}, false},
+		`Vars`:      {Func{`vars`, `Prefix`}, false},
+		`Cutoff`:    {Func{`cutoff`, `Cutoff`}, false},
+		`Filter`:    {Func{`filter`, `Column,Operator,Value`}, false},
+		`GroupBy`:   {Func{`groupby`, `GroupBy`}, false},
+		`Sum`:       {Func{`sum`, `Column,As`}, false},
+		`Avg`:       {Func{`avg`, `Column,As`}, false},
+		`Min`:       {Func{`min`, `Column,As`}, false},
+		`Max`:       {Func{`max`, `Column,As`}, false},
+	},
+	`p`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`radiogroup`: {
+		`Validate`: {Func{`validate`, `*`}, false},
+		`Style`:    {Func{`style`, `Style`}, false},
+	},
+	`span`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`table`: {
+		`Style`: {Func{`style`, `Style`}, false},
+	},
+	`select`: {
+		`Validate`: {Func{`validate`, `*`}, false},
+		`Style`:    {Func{`style`, `Style`}, false},
+	},
+	`inputMap`: {
+		`Validate`: {Func{`validate`, `*`}, false},
+	},
+	`binary`: {
+		`ById`:      {Func{`id`, `id`}, false},
+		`Ecosystem`: {Func{`ecosystem`, `ecosystem`}, false},
+	},
+}
+
+// GetFunc parses the parameters and the tails of the function which begin at the start of input.
+// It returns the parameters, the count of the parsed runes and the parameters of tails.
+func GetFunc(input string, curFunc Func) (*[][]rune, int, *[]*[][]rune) {
+	var (
+		curp, skip, off, mode, lenParams int
+		quote                            bool
+		pair, ch                         rune
+		tailpar                          *[]*[][]rune
+	)
+	var params [][]rune
+	sizeParam := 32 + len(input)/2
+	params = append(params, make([]rune, 0, sizeParam))
+	if curFunc.Params == `*` {
+		lenParams = 0xff
+	} else {
+		lenParams = len(strings.Split(curFunc.Params, `,`))
+	}
+	level := 1
+	if input[0] == '{' {
+		mode = 1
+	}
+	skip = 1
+main:
+	for off, ch = range input {
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if pair > 0 {
+			if ch != pair {
+				params[curp] = append(params[curp], ch)
+			} else {
+				if off+1 == len(input) || rune(input[off+1]) != pair {
+					pair = 0
+					if quote {
+						params[curp] = append(params[curp], ch)
+						quote = false
+					}
+				} else {
+					params[curp] = append(params[curp], ch)
+					skip = 1
+				}
+			}
+			continue
+		}
+		if len(params[curp]) == 0 && mode == 0 && ch != modes[mode][1] && ch != ',' {
+			if ch >= '!' {
+				if ch == '"' || ch == '`' {
+					pair = ch
+				} else {
+					if ch == modes[mode][0] {
+						level++
+					}
+					params[curp] = append(params[curp], ch)
+				}
+			}
+			continue
+		}
+
+		switch ch {
+		case '"', '`':
+			if mode == 0 {
+				pair = ch
+				quote = true
+			}
+		case ',':
+			if mode == 0 && level == 1 && len(params) < lenParams {
+				params = append(params, make([]rune, 0, sizeParam))
+				curp++
+				continue
+			}
+		case modes[mode][0]:
+			level++
+		case modes[mode][1]:
+			if level > 0 {
+				level--
+			}
+			if level == 0 {
+				if mode == 0 && (strings.Contains(curFunc.Params, `Body`) || strings.Contains(curFunc.Params, `Data`)) {
+					var isBody bool
+					next := off + 1
+					for next < len(input) {
+						if rune(input[next]) == modes[1][0] {
+							isBody = true
+							break
+						}
+						if rune(input[next]) == ' ' || rune(input[next]) == '\t' {
+							next++
+							continue
+						}
+						break
+					}
+					if isBody {
+						mode = 1
+						for _, keyp := range []string{`Body`, `Data`} {
+							if strings.Contains(curFunc.Params, keyp) {
+								irune := make([]rune, 0, sizeParam)
+								s := keyp + `:`
+								params = append(params, append(irune, []rune(s)...))
+								break
+							}
+						}
+						curp++
+						skip = next - off
+						level = 1
+						continue
+					}
+				}
+				for tail, ok := Tails[curFunc.Tag]; ok && off+2 < len(input) && input[off+1] == '.'; {
+					var found bool
+					for key, tailFunc := range tail {
+						next := off + 2
+						if next < len(input) && strings.HasPrefix(input[next:], key) {
+							var isTail bool
+							next += len(key)
+							for next < len(input) {
+								if rune(input[next]) == '(' || rune(input[next]) == '{' {
+									isTail = true
+									break
+								}
+								if rune(input[next]) == ' ' || rune(input[next]) == '\t' {
+									next++
+									continue
+								}
+								break
+							}
+							if isTail {
+								parTail, shift, _ := GetFunc(input[next:], tailFunc.Func)
+								off = next
+								for ; shift > 0; shift-- {
+									_, size := utf8.DecodeRuneInString(input[off:])
+									off += size
+								}
+								if tailpar == nil {
+									fortail := make([]*[][]rune, 0)
+									tailpar = &fortail
+								}
+								*parTail = append(*parTail, []rune(key))
+								*tailpar = append(*tailpar, parTail)
+								found = true
+								if tailFunc.Last {
+									break main
+								}
+								break
+							}
+						}
+					}
+					if !found {
+						break
+					}
+				}
+				break main
+			}
+		}
+		params[curp] = append(params[curp], ch)
+		continue
+	}
+	return &params, utf8.RuneCountInString(input[:off]), tailpar
+}
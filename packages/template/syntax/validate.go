@@ -0,0 +1,209 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package syntax
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Levels of validation messages
+const (
+	LevelError   = `error`
+	LevelWarning = `warning`
+)
+
+// ValidationError describes the problem in the source of the template
+type ValidationError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Options are the additional parameters of the validation
+type Options struct {
+	// Funcs are the functions which are defined in addition to the built-in ones, e.g. components
+	Funcs map[string]Func
+	// Check returns the warnings about the call of the function with the values of its parameters
+	Check func(name string, pars map[string]string) []string
+}
+
+type validator struct {
+	source string
+	errors []ValidationError
+	opt    Options
+}
+
+// Validate checks the source of the template
+func Validate(input string, opt Options) []ValidationError {
+	v := &validator{source: input, errors: make([]ValidationError, 0), opt: opt}
+	v.validate(input, 0)
+	return v.errors
+}
+
+// Check returns the first error of the template source
+func Check(input string) error {
+	for _, item := range Validate(input, Options{}) {
+		if item.Level == LevelError {
+			return fmt.Errorf(`%d:%d: %s`, item.Line, item.Column, item.Message)
+		}
+	}
+	return nil
+}
+
+// add appends the message for the position of the source
+func (v *validator) add(pos int, level, format string, args ...interface{}) {
+	if pos > len(v.source) {
+		pos = len(v.source)
+	}
+	line := strings.Count(v.source[:pos], "\n") + 1
+	column := utf8.RuneCountInString(v.source[strings.LastIndexByte(v.source[:pos], '\n')+1:pos]) + 1
+	v.errors = append(v.errors, ValidationError{Line: line, Column: column, Level: level,
+		Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) lookup(name string) (Func, bool) {
+	curFunc, ok := Funcs[name]
+	if !ok {
+		curFunc, ok = v.opt.Funcs[name]
+	}
+	return curFunc, ok
+}
+
+func isLetter(ch rune) bool {
+	return (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z')
+}
+
+func isIdentifier(name string) bool {
+	for i, ch := range name {
+		if !isLetter(ch) && (i == 0 || ch < '0' || ch > '9') {
+			return false
+		}
+	}
+	return len(name) > 0
+}
+
+// validate checks the template which begins at base offset of the source
+func (v *validator) validate(input string, base int) {
+	start := -1
+	skip := 0
+	for off, ch := range input {
+		if off < skip {
+			continue
+		}
+		if ch == '(' && start >= 0 {
+			name := input[start:off]
+			start = -1
+			if curFunc, ok := v.lookup(name); ok {
+				skip = v.call(name, curFunc, input, off, base)
+				for skip+2 < len(input) && input[skip:skip+2] == `.(` {
+					skip = v.call(name, curFunc, input, skip+1, base)
+				}
+				continue
+			}
+			if name[0] >= 'A' && name[0] <= 'Z' {
+				v.add(base+off-len(name), LevelWarning, `Unknown function %s`, name)
+			}
+			continue
+		}
+		if isLetter(ch) {
+			if start < 0 {
+				start = off
+			}
+		} else {
+			start = -1
+		}
+	}
+}
+
+// call checks the call of the function which parameters begin at off. It returns the offset
+// of the input after the call.
+func (v *validator) call(name string, curFunc Func, input string, off, base int) int {
+	// the sentinel stops the parsing if the function is not closed
+	params, shift, tailpars := GetFunc(input[off:]+"\x00", curFunc)
+	end := off
+	for ; shift > 0 && end < len(input); shift-- {
+		_, size := utf8.DecodeRuneInString(input[end:])
+		end += size
+	}
+	if end >= len(input) || (input[end] != ')' && input[end] != '}') {
+		v.add(base+off-len(name), LevelError, `Function %s is not closed`, name)
+		return len(input)
+	}
+	pars := v.params(name, curFunc, params, input[off:end], base+off)
+	if tailpars != nil {
+		for _, tail := range *tailpars {
+			tailName := string((*tail)[len(*tail)-1])
+			tailFunc := Tails[curFunc.Tag][tailName].Func
+			tailPars := (*tail)[:len(*tail)-1]
+			v.params(tailName, tailFunc, &tailPars, input[off:end], base+off)
+			if tailName == `Columns` && len(tailPars) > 0 {
+				pars[`Columns`] = strings.Trim(strings.TrimSpace(string(tailPars[0])), "\"`")
+			}
+		}
+	}
+	if v.opt.Check != nil {
+		for _, warning := range v.opt.Check(name, pars) {
+			v.add(base+off-len(name), LevelWarning, `%s`, warning)
+		}
+	}
+	return end + 1
+}
+
+// params checks the parameters of the function and validates the body. It returns
+// the values of parameters.
+func (v *validator) params(name string, curFunc Func, params *[][]rune, source string, base int) map[string]string {
+	pars := make(map[string]string)
+	names := strings.Split(curFunc.Params, `,`)
+	cursor := 0
+	for i, par := range *params {
+		val := strings.TrimSpace(string(par))
+		parName := ``
+		if i < len(names) {
+			parName = names[i]
+		}
+		if off := strings.IndexByte(val, ':'); off > 0 && isIdentifier(val[:off]) {
+			if curFunc.Params == `*` || strings.Contains(curFunc.Params, val[:off]) {
+				parName = val[:off]
+				val = strings.TrimSpace(val[off+1:])
+			} else if len(names) > 1 {
+				// the only parameter can contain any text
+				at := cursor
+				if pos := strings.Index(source[cursor:], val[:off]); pos >= 0 {
+					at += pos
+				}
+				v.add(base+at, LevelWarning, `Unknown parameter %s of %s`, val[:off], name)
+			}
+		}
+		pars[parName] = strings.Trim(val, "\"`")
+		if len(val) == 0 {
+			continue
+		}
+		// the body is searched in the source to get the position of nested functions
+		at := base
+		if pos := strings.Index(source[cursor:], val); pos >= 0 {
+			at += cursor + pos
+			cursor += pos + len(val)
+		}
+		if parName == `Body` && strings.IndexByte(val, '(') >= 0 {
+			v.validate(val, at)
+		}
+	}
+	return pars
+}
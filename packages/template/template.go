@@ -27,6 +27,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/language"
 	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/template/syntax"
 	"github.com/GenesisKernel/go-genesis/packages/utils/tx"
 
 	"github.com/shopspring/decimal"
@@ -59,6 +60,10 @@ type Workspace struct {
 	Vars          *map[string]string
 	SmartContract *smart.SmartContract
 	Timeout       *bool
+
+	block      *blockState   // the last block which identifies the cached results of DBFind
+	components *componentSet // components of the ecosystem
+	slot       []*node       // content which has been passed to the component
 }
 
 // SetSource sets source to workspace
@@ -88,6 +93,12 @@ type tplFunc struct {
 	Params string   // names of parameters
 }
 
+// tplHandler contains the process functions of the template function
+type tplHandler struct {
+	Func nodeFunc // process function
+	Full nodeFunc // full process function
+}
+
 type tailInfo struct {
 	tplFunc
 	Last bool
@@ -473,202 +484,24 @@ func callFunc(curFunc *tplFunc, owner *node, workspace *Workspace, params *[][]r
 }
 
 func getFunc(input string, curFunc tplFunc) (*[][]rune, int, *[]*[][]rune) {
-	var (
-		curp, skip, off, mode, lenParams int
-		quote                            bool
-		pair, ch                         rune
-		tailpar                          *[]*[][]rune
-	)
-	var params [][]rune
-	sizeParam := 32 + len(input)/2
-	params = append(params, make([]rune, 0, sizeParam))
-	if curFunc.Params == `*` {
-		lenParams = 0xff
-	} else {
-		lenParams = len(strings.Split(curFunc.Params, `,`))
-	}
-	level := 1
-	if input[0] == '{' {
-		mode = 1
-	}
-	skip = 1
-main:
-	for off, ch = range input {
-		if skip > 0 {
-			skip--
-			continue
-		}
-		if pair > 0 {
-			if ch != pair {
-				params[curp] = append(params[curp], ch)
-			} else {
-				if off+1 == len(input) || rune(input[off+1]) != pair {
-					pair = 0
-					if quote {
-						params[curp] = append(params[curp], ch)
-						quote = false
-					}
-				} else {
-					params[curp] = append(params[curp], ch)
-					skip = 1
-				}
-			}
-			continue
-		}
-		if len(params[curp]) == 0 && mode == 0 && ch != modes[mode][1] && ch != ',' {
-			if ch >= '!' {
-				if ch == '"' || ch == '`' {
-					pair = ch
-				} else {
-					if ch == modes[mode][0] {
-						level++
-					}
-					params[curp] = append(params[curp], ch)
-				}
-			}
-			continue
-		}
-
-		switch ch {
-		case '"', '`':
-			if mode == 0 {
-				pair = ch
-				quote = true
-			}
-		case ',':
-			if mode == 0 && level == 1 && len(params) < lenParams {
-				params = append(params, make([]rune, 0, sizeParam))
-				curp++
-				continue
-			}
-		case modes[mode][0]:
-			level++
-		case modes[mode][1]:
-			if level > 0 {
-				level--
-			}
-			if level == 0 {
-				if mode == 0 && (strings.Contains(curFunc.Params, `Body`) || strings.Contains(curFunc.Params, `Data`)) {
-					var isBody bool
-					next := off + 1
-					for next < len(input) {
-						if rune(input[next]) == modes[1][0] {
-							isBody = true
-							break
-						}
-						if rune(input[next]) == ' ' || rune(input[next]) == '\t' {
-							next++
-							continue
-						}
-						break
-					}
-					if isBody {
-						mode = 1
-						for _, keyp := range []string{`Body`, `Data`} {
-							if strings.Contains(curFunc.Params, keyp) {
-								irune := make([]rune, 0, sizeParam)
-								s := keyp + `:`
-								params = append(params, append(irune, []rune(s)...))
-								break
-							}
-						}
-						curp++
-						skip = next - off
-						level = 1
-						continue
-					}
-				}
-				for tail, ok := tails[curFunc.Tag]; ok && off+2 < len(input) && input[off+1] == '.'; {
-					var found bool
-					for key, tailFunc := range tail.Tails {
-						next := off + 2
-						if next < len(input) && strings.HasPrefix(input[next:], key) {
-							var isTail bool
-							next += len(key)
-							for next < len(input) {
-								if rune(input[next]) == '(' || rune(input[next]) == '{' {
-									isTail = true
-									break
-								}
-								if rune(input[next]) == ' ' || rune(input[next]) == '\t' {
-									next++
-									continue
-								}
-								break
-							}
-							if isTail {
-								parTail, shift, _ := getFunc(input[next:], tailFunc.tplFunc)
-								off = next
-								for ; shift > 0; shift-- {
-									_, size := utf8.DecodeRuneInString(input[off:])
-									off += size
-								}
-								if tailpar == nil {
-									fortail := make([]*[][]rune, 0)
-									tailpar = &fortail
-								}
-								*parTail = append(*parTail, []rune(key))
-								*tailpar = append(*tailpar, parTail)
-								found = true
-								if tailFunc.Last {
-									break main
-								}
-								break
-							}
-						}
-					}
-					if !found {
-						break
-					}
-				}
-				break main
-			}
-		}
-		params[curp] = append(params[curp], ch)
-		continue
-	}
-	return &params, utf8.RuneCountInString(input[:off]), tailpar
+	return syntax.GetFunc(input, syntax.Func{Tag: curFunc.Tag, Params: curFunc.Params})
 }
 
 func process(input string, owner *node, workspace *Workspace) {
-	var (
-		nameOff, shift int
-		curFunc        tplFunc
-		isFunc         bool
-		params         *[][]rune
-		tailpars       *[]*[][]rune
-	)
-	name := make([]rune, 0, 128)
-	for off, ch := range input {
-		if shift > 0 {
-			shift--
-			continue
-		}
-		if ch == '(' {
-			if curFunc, isFunc = funcs[string(name[nameOff:])]; isFunc {
-				if *workspace.Timeout {
-					return
-				}
-				appendText(owner, macro(string(name[:nameOff]), workspace.Vars))
-				name = name[:0]
-				nameOff = 0
-				params, shift, tailpars = getFunc(input[off:], curFunc)
-				callFunc(&curFunc, owner, workspace, params, tailpars)
-				for off+shift+3 < len(input) && input[off+shift+1:off+shift+3] == `.(` {
-					var next int
-					params, next, tailpars = getFunc(input[off+shift+2:], curFunc)
-					callFunc(&curFunc, owner, workspace, params, tailpars)
-					shift += next + 2
-				}
-				continue
+	for _, item := range parse(input, workspace.components) {
+		if item.fn == nil {
+			if item.macro {
+				appendText(owner, macro(item.text, workspace.Vars))
+			} else {
+				appendText(owner, item.text)
 			}
+			continue
 		}
-		if (ch < 'A' || ch > 'Z') && (ch < 'a' || ch > 'z') {
-			nameOff = len(name) + 1
+		if *workspace.Timeout {
+			return
 		}
-		name = append(name, ch)
+		callFunc(item.fn, owner, workspace, item.params, item.tails)
 	}
-	appendText(owner, string(name))
 }
 
 func parseArg(arg string, workspace *Workspace) (val string) {
@@ -686,8 +519,8 @@ func parseArg(arg string, workspace *Workspace) (val string) {
 	return
 }
 
-// Template2JSON converts templates to JSON data
-func Template2JSON(input string, timeout *bool, vars *map[string]string) []byte {
+// template2Node processes the template and returns the root node of the tree
+func template2Node(input string, timeout *bool, vars *map[string]string) *node {
 	root := node{}
 	isvde := (*vars)[`vde`] == `true` || (*vars)[`vde`] == `1`
 	sc := smart.SmartContract{
@@ -702,15 +535,28 @@ func Template2JSON(input string, timeout *bool, vars *map[string]string) []byte
 			},
 		},
 	}
-	process(input, &root, &Workspace{Vars: vars, Timeout: timeout, SmartContract: &sc})
-	if root.Children == nil || *timeout {
-		return []byte(`[]`)
+	workspace := &Workspace{Vars: vars, Timeout: timeout, SmartContract: &sc, block: &blockState{}}
+	if !isvde {
+		workspace.components = getComponents((*vars)[`ecosystem_id`], workspace.lastBlock())
+	}
+	process(input, &root, workspace)
+	if *timeout {
+		root.Children = nil
 	}
 	for i, v := range root.Children {
 		if v.Tag == `text` {
 			root.Children[i].Text = macro(v.Text, vars)
 		}
 	}
+	return &root
+}
+
+// Template2JSON converts templates to JSON data
+func Template2JSON(input string, timeout *bool, vars *map[string]string) []byte {
+	root := template2Node(input, timeout, vars)
+	if root.Children == nil {
+		return []byte(`[]`)
+	}
 	out, err := json.Marshal(root.Children)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling template data to json")
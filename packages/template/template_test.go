@@ -299,3 +299,11 @@ var forFullTest = tplList{
 			}.Else {Fourth}If(0).Else{ALL right}.What`,
 		`[{"tag":"if","attr":{"condition":"true"},"children":[{"tag":"text","text":"OK"}],"tail":[{"tag":"else","children":[{"tag":"text","text":"false"}]}]},{"tag":"if","attr":{"condition":"false"},"children":[{"tag":"text","text":"FALSE"}],"tail":[{"tag":"elseif","attr":{"condition":"1"},"children":[{"tag":"text","text":"Else OK"}]},{"tag":"else","children":[{"tag":"text","text":"Fourth"}]}]},{"tag":"if","attr":{"condition":"0"},"tail":[{"tag":"else","children":[{"tag":"text","text":"ALL right"}]}]},{"tag":"text","text":".What"}]`},
 }
+
+func TestLangParams(t *testing.T) {
+	vars := map[string]string{`count`: `5`, `lang`: `en`}
+	params := langParams(`count=#count#, name = John, wrong`, &vars)
+	if len(params) != 2 || params[`count`] != `5` || params[`name`] != `John` {
+		t.Errorf(`wrong parameters %v`, params)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/smart"
+	"github.com/GenesisKernel/go-genesis/packages/template/syntax"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Levels of validation messages
+const (
+	LevelError   = syntax.LevelError
+	LevelWarning = syntax.LevelWarning
+)
+
+// ValidationError describes the problem in the source of the template
+type ValidationError = syntax.ValidationError
+
+type validator struct {
+	ecosystem int64
+}
+
+// ValidateTemplate checks the source of the template. If ecosystem is not zero then
+// the components of the ecosystem are known and the names of tables, columns, pages
+// and blocks are checked in the ecosystem.
+func ValidateTemplate(input string, ecosystem int64) []ValidationError {
+	var opt syntax.Options
+	if ecosystem != 0 && model.DBConn != nil {
+		if components := getComponents(converter.Int64ToStr(ecosystem), nil); components != nil {
+			opt.Funcs = make(map[string]syntax.Func, len(components.funcs))
+			for name, item := range components.funcs {
+				opt.Funcs[name] = syntax.Func{Tag: item.Tag, Params: item.Params}
+			}
+		}
+		opt.Check = (&validator{ecosystem: ecosystem}).references
+	}
+	return syntax.Validate(input, opt)
+}
+
+// references checks the names of tables, columns, pages and blocks which don't contain macros
+func (v *validator) references(name string, pars map[string]string) (warnings []string) {
+	isConst := func(val string) bool {
+		return len(val) > 0 && !strings.ContainsAny(val, `#(`)
+	}
+	prefix := converter.Int64ToStr(v.ecosystem)
+	switch name {
+	case `LinkPage`, `Button`:
+		if page := pars[`Page`]; isConst(page) {
+			p := &model.Page{}
+			p.SetTablePrefix(prefix)
+			if found, err := p.Get(page); err == nil && !found {
+				warnings = append(warnings, fmt.Sprintf(`Page %s has not been found`, page))
+			}
+		}
+	case `Include`:
+		if block := pars[`Name`]; isConst(block) {
+			bi := &model.BlockInterface{}
+			bi.SetTablePrefix(prefix)
+			if found, err := bi.Get(block); err == nil && !found {
+				warnings = append(warnings, fmt.Sprintf(`Block %s has not been found`, block))
+			}
+		}
+	case `DBFind`:
+		table := pars[`Name`]
+		if !isConst(table) {
+			return
+		}
+		tblname := smart.GetTableName(nil, strings.Trim(converter.EscapeName(table), `"`), v.ecosystem)
+		rows, err := model.GetAllColumnTypes(tblname)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting column types from db")
+			return
+		}
+		if len(rows) == 0 {
+			warnings = append(warnings, fmt.Sprintf(`Table %s has not been found`, table))
+			return
+		}
+		columns := pars[`Columns`]
+		if !isConst(columns) || columns == `*` {
+			return
+		}
+		exists := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			exists[row[`column_name`]] = true
+		}
+		for _, col := range strings.Split(columns, `,`) {
+			col = strings.TrimSpace(col)
+			if off := strings.Index(col, `->`); off > 0 {
+				col = col[:off]
+			}
+			if len(col) > 0 && !exists[strings.ToLower(col)] {
+				warnings = append(warnings, fmt.Sprintf(`Column %s has not been found in %s`, col, table))
+			}
+		}
+	}
+	return
+}
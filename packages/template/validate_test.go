@@ -0,0 +1,73 @@
+// Copyright 2016 The go-daylight Authors
+// This file is part of the go-daylight library.
+//
+// The go-daylight library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-daylight library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-daylight library. If not, see <http://www.gnu.org/licenses/>.
+
+package template
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GenesisKernel/go-genesis/packages/template/syntax"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	for _, item := range forValidateTest {
+		var out string
+		for _, err := range ValidateTemplate(item.input, 0) {
+			out += fmt.Sprintf(`%d:%d %s %s;`, err.Line, err.Column, err.Level, err.Message)
+		}
+		if out != item.want {
+			t.Errorf("wrong validation of %s\r\n%s != \r\n%s", item.input, out, item.want)
+		}
+	}
+}
+
+var forValidateTest = tplList{
+	{`Div(myclass){Span(Text)}.Style(div {})Note (1)`, ``},
+	{`Div(myclass){
+	Span(Text)
+	Spam(Wrong)
+}`, `3:2 warning Unknown function Spam;`},
+	{`Div(Class: my, Styles: red)`, `1:16 warning Unknown parameter Styles of Div;`},
+	{`If(true){Div(){Span(Text}}`, `1:16 error Function Span is not closed;`},
+	{`P(Text)
+	Div(myclass, Span(Text)`, `2:2 error Function Div is not closed;`},
+	{`Button(Body: Send).Alerts(Text: Yes)`, `1:20 warning Unknown function Alerts;`},
+}
+
+func TestSyntaxFuncs(t *testing.T) {
+	if len(funcHandlers) != len(syntax.Funcs) {
+		t.Errorf(`wrong count of functions %d != %d`, len(funcHandlers), len(syntax.Funcs))
+	}
+	for name := range syntax.Funcs {
+		if handler, ok := funcHandlers[name]; !ok || handler.Func == nil || handler.Full == nil {
+			t.Errorf(`function %s has no handlers`, name)
+		}
+	}
+	if len(tailHandlers) != len(syntax.Tails) {
+		t.Errorf(`wrong count of tails %d != %d`, len(tailHandlers), len(syntax.Tails))
+	}
+	for tag, tail := range syntax.Tails {
+		if len(tailHandlers[tag]) != len(tail) {
+			t.Errorf(`wrong count of tails of %s`, tag)
+		}
+		for name := range tail {
+			if handler, ok := tailHandlers[tag][name]; !ok || handler.Func == nil || handler.Full == nil {
+				t.Errorf(`tail %s.%s has no handlers`, tag, name)
+			}
+		}
+	}
+}
@@ -30,13 +30,13 @@ func InsertInLogTx(transaction *model.DbTransaction, binaryTx []byte, time int64
 
 // CheckLogTx checks if this transaction exists
 // And it would have successfully passed a frontal test
-func CheckLogTx(txBinary []byte, transactions, txQueue bool) error {
+func CheckLogTx(dbTransaction *model.DbTransaction, txBinary []byte, transactions, txQueue bool) error {
 	searchedHash, err := crypto.Hash(txBinary)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Fatal(err)
 	}
 	logTx := &model.LogTransaction{}
-	found, err := logTx.GetByHash(searchedHash)
+	found, err := logTx.GetByHash(dbTransaction, searchedHash)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting log transaction by hash")
 		return utils.ErrInfo(err)
@@ -0,0 +1,99 @@
+package transaction
+
+import (
+	"bytes"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/mempool"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SyncMempool puts the verified unused transactions of the database into the mempool
+// and removes the transactions which have been used or deleted from the mempool.
+// The database keeps the transactions if the node is restarted
+func SyncMempool() error {
+	pool := mempool.GetPool()
+
+	hashes, err := model.GetUnusedTransactionHashes()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting unused transaction hashes")
+		return err
+	}
+
+	unused := make(map[string]bool, len(hashes))
+	missing := make([][]byte, 0)
+	for _, hash := range hashes {
+		unused[string(hash)] = true
+		if !pool.Has(hash) {
+			missing = append(missing, hash)
+		}
+	}
+	for _, hash := range pool.Hashes() {
+		if !unused[string(hash)] {
+			pool.Remove(hash)
+		}
+	}
+
+	trs, err := model.GetTransactionsByHashes(missing)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting transactions by hashes")
+		return err
+	}
+	for _, tr := range trs {
+		tx, err := newMempoolTx(tr)
+		if err != nil {
+			MarkTransactionBad(nil, tr.Hash, err.Error())
+			continue
+		}
+		dropped, err := pool.Add(tx)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.BadTxError, "tx_hash": tr.Hash, "error": err}).Debug("adding transaction to mempool")
+			MarkTransactionBad(nil, tr.Hash, err.Error())
+			continue
+		}
+		for _, d := range dropped {
+			MarkTransactionBad(nil, d.Tx.Hash, d.Reason.Error())
+		}
+	}
+	return nil
+}
+
+func newMempoolTx(tr *model.Transaction) (*mempool.Tx, error) {
+	t, err := UnmarshallTransaction(bytes.NewBuffer(tr.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &mempool.Tx{
+		Hash:     tr.Hash,
+		Data:     tr.Data,
+		KeyID:    tr.KeyID,
+		Type:     int64(tr.Type),
+		Seq:      t.TxTime,
+		HighRate: int8(tr.HighRate),
+	}
+	if t.TxSmart != nil {
+		tx.RequestID = t.TxSmart.RequestID
+		tx.Type = int64(t.TxSmart.Type)
+		tx.Fee = mempool.ParseFee(t.TxSmart.PayOver, t.TxSmart.MaxSum)
+		if t.TxSmart.Nonce > 0 {
+			tx.Nonce = t.TxSmart.Nonce
+			tx.Seq = t.TxSmart.Nonce
+		}
+	}
+	if tx.KeyID == 0 {
+		tx.KeyID = t.TxKeyID
+	}
+	return tx, nil
+}
+
+// NextNonce returns the next nonce of the key which is expected by the blockchain
+func NextNonce(keyID int64) int64 {
+	nonce, err := GetNonce(nil, keyID)
+	if err != nil {
+		return 0
+	}
+	return nonce + 1
+}
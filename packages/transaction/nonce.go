@@ -0,0 +1,101 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/GenesisKernel/go-genesis/packages/consts"
+	"github.com/GenesisKernel/go-genesis/packages/converter"
+	"github.com/GenesisKernel/go-genesis/packages/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNonceUsed is returned if the nonce of the transaction has been already used by the key
+	ErrNonceUsed = errors.New("Nonce has been already used")
+	// ErrNonceGap is returned if the nonce of the transaction isn't next to the last used nonce of the key
+	ErrNonceGap = errors.New("Nonce is not next to the last used nonce")
+	// ErrInvalidNonce is returned if the nonce is negative
+	ErrInvalidNonce = errors.New("Nonce must be positive")
+)
+
+// GetNonce returns the last used nonce of the key
+func GetNonce(dbTransaction *model.DbTransaction, keyID int64) (int64, error) {
+	kn := &model.KeyNonce{}
+	if _, err := kn.Get(dbTransaction, keyID); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "key_id": keyID}).Error("getting nonce of key")
+		return 0, err
+	}
+	return kn.Nonce, nil
+}
+
+// checkNonce refuses the replayed transaction with the used nonce.
+// The transaction with a gap is valid, it waits in the mempool for the missing transactions
+func (t *Transaction) checkNonce() error {
+	if t.TxSmart == nil || t.TxSmart.Nonce == 0 {
+		return nil
+	}
+	if t.TxSmart.Nonce < 0 {
+		return ErrInvalidNonce
+	}
+	last, err := GetNonce(t.DbTransaction, t.TxSmart.KeyID)
+	if err != nil {
+		return err
+	}
+	if t.TxSmart.Nonce <= last {
+		log.WithFields(log.Fields{"type": consts.DuplicateObject, "nonce": t.TxSmart.Nonce, "last_nonce": last}).Error("nonce has been already used")
+		return ErrNonceUsed
+	}
+	return nil
+}
+
+// useNonce saves the nonce of the played transaction as the last used nonce of the key.
+// The previous nonce is written to rollback_tx, so it's restored by the rollback of the transaction
+func (t *Transaction) useNonce() error {
+	if t.TxSmart == nil || t.TxSmart.Nonce == 0 {
+		return nil
+	}
+	kn := &model.KeyNonce{}
+	found, err := kn.Get(t.DbTransaction, t.TxSmart.KeyID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting nonce of key")
+		return err
+	}
+	if t.TxSmart.Nonce <= kn.Nonce {
+		return ErrNonceUsed
+	}
+	if t.TxSmart.Nonce != kn.Nonce+1 {
+		log.WithFields(log.Fields{"type": consts.InvalidObject, "nonce": t.TxSmart.Nonce, "last_nonce": kn.Nonce}).Error("gap between nonces")
+		return ErrNonceGap
+	}
+
+	rollbackTx := &model.RollbackTx{
+		TxHash:    t.TxHash,
+		NameTable: kn.TableName(),
+		TableID:   converter.Int64ToStr(t.TxSmart.KeyID),
+	}
+	if t.BlockData != nil {
+		rollbackTx.BlockID = t.BlockData.BlockID
+	}
+	if found {
+		data, err := json.Marshal(map[string]string{"nonce": converter.Int64ToStr(kn.Nonce)})
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling rollback data")
+			return err
+		}
+		rollbackTx.Data = string(data)
+	}
+	if err = rollbackTx.Create(t.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating rollback of nonce")
+		return err
+	}
+
+	kn.ID = t.TxSmart.KeyID
+	kn.Nonce = t.TxSmart.Nonce
+	if err = kn.Save(t.DbTransaction); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("saving nonce of key")
+		return err
+	}
+	return nil
+}
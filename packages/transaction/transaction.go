@@ -304,7 +304,7 @@ func CheckTransaction(data []byte) (*tx.Header, error) {
 }
 
 func (t *Transaction) Check(checkTime int64, checkForDupTr bool) error {
-	err := CheckLogTx(t.TxFullData, checkForDupTr, false)
+	err := CheckLogTx(t.DbTransaction, t.TxFullData, checkForDupTr, false)
 	if err != nil {
 		return err
 	}
@@ -330,12 +330,15 @@ func (t *Transaction) Check(checkTime int64, checkForDupTr bool) error {
 		}
 	}
 
-	return nil
+	return t.checkNonce()
 }
 
 func (t *Transaction) Play() (string, error) {
 	// smart-contract
 	if t.TxContract != nil {
+		if err := t.useNonce(); err != nil {
+			return "", err
+		}
 		// check that there are enough money in CallContract
 		return t.CallContract(smart.CallInit | smart.CallCondition | smart.CallAction)
 	}
@@ -2,7 +2,11 @@
 
 package utils
 
-import "github.com/GenesisKernel/go-genesis/packages/model"
+import (
+	"time"
+
+	"github.com/GenesisKernel/go-genesis/packages/model"
+)
 
 type intervalBlocksCounter interface {
 	count(state blockGenerationState) (int, error)
@@ -19,3 +23,10 @@ func (bc *blocksCounter) count(state blockGenerationState) (int, error) {
 	}
 	return len(blocks), nil
 }
+
+// BlocksCounterFunc returns the count of blocks generated by the node in the interval
+type BlocksCounterFunc func(start time.Time, duration time.Duration, nodePosition int64) (int, error)
+
+func (f BlocksCounterFunc) count(state blockGenerationState) (int, error) {
+	return f(state.start, state.duration, state.nodePosition)
+}
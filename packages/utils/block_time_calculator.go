@@ -86,6 +86,12 @@ func (btc *BlockTimeCalculator) SetClock(clock Clock) *BlockTimeCalculator {
 	return btc
 }
 
+// SetBlocksCounter replaces the counter of the blocks which have been generated in the intervals
+func (btc *BlockTimeCalculator) SetBlocksCounter(counter BlocksCounterFunc) *BlockTimeCalculator {
+	btc.blocksCounter = counter
+	return btc
+}
+
 func (btc *BlockTimeCalculator) setBlockCounter(counter intervalBlocksCounter) *BlockTimeCalculator {
 	btc.blocksCounter = counter
 	return btc
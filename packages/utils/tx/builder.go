@@ -8,18 +8,21 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
 	"github.com/GenesisKernel/go-genesis/packages/model"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/vmihailenco/msgpack.v2"
 )
 
 // BuildTransaction creates transaction
-func BuildTransaction(smartTx SmartContract, privKey, pubKey string, params ...string) error {
+func BuildTransaction(smartTx SmartContract, s signer.Signer, params ...string) error {
+	pubKey, err := s.PublicKey()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("getting node public key")
+		return err
+	}
 	signPrms := []string{smartTx.ForSign()}
 	signPrms = append(signPrms, params...)
-	signature, err := crypto.Sign(
-		privKey,
-		strings.Join(signPrms, ","),
-	)
+	signature, err := s.Sign(strings.Join(signPrms, ","))
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing by node private key")
 		return err
@@ -11,4 +11,7 @@ type Header struct {
 	NodePosition  int64
 	PublicKey     []byte
 	BinSignatures []byte
+	Nonce         int64   `msgpack:",omitempty"` // optional sequence number of transactions of the key
+	Signers       []int64 `msgpack:",omitempty"` // keys of the signatures of the multisig wallet
+	KeyType       int64   `msgpack:",omitempty"` // type of the key of the signature, ECDSA by default
 }
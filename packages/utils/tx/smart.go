@@ -1,6 +1,10 @@
 package tx
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/GenesisKernel/go-genesis/packages/crypto"
+)
 
 // SmartContract is storing smart contract data
 type SmartContract struct {
@@ -13,8 +17,15 @@ type SmartContract struct {
 	Data           []byte
 }
 
-// ForSign is converting SmartContract to string
+// ForSign is converting SmartContract to string, the nonce is signed only if it's specified
 func (s SmartContract) ForSign() string {
-	return fmt.Sprintf("%s,%d,%d,%d,%d,%d,%s,%s,%d", s.RequestID, s.Type, s.Time, s.KeyID, s.EcosystemID,
+	forSign := fmt.Sprintf("%s,%d,%d,%d,%d,%d,%s,%s,%d", s.RequestID, s.Type, s.Time, s.KeyID, s.EcosystemID,
 		s.TokenEcosystem, s.MaxSum, s.PayOver, s.SignedBy)
+	if s.Nonce != 0 {
+		forSign += fmt.Sprintf(",%d", s.Nonce)
+	}
+	if s.KeyType != 0 {
+		forSign += "," + crypto.KeyType(s.KeyType).String()
+	}
+	return forSign
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/GenesisKernel/go-genesis/packages/consts"
 	"github.com/GenesisKernel/go-genesis/packages/converter"
 	"github.com/GenesisKernel/go-genesis/packages/crypto"
+	"github.com/GenesisKernel/go-genesis/packages/keystore"
 	"github.com/GenesisKernel/go-genesis/packages/model"
 	uuid "github.com/satori/go.uuid"
 
@@ -245,8 +246,13 @@ func CopyFileContents(src, dst string) error {
 	return ErrInfo(err)
 }
 
-// CheckSign checks the signature
+// CheckSign checks the ECDSA signature
 func CheckSign(publicKeys [][]byte, forSign string, signs []byte, nodeKeyOrLogin bool) (bool, error) {
+	return CheckSignType(crypto.KeyECDSA, publicKeys, forSign, signs, nodeKeyOrLogin)
+}
+
+// CheckSignType checks the signature of the specified key type
+func CheckSignType(keyType crypto.KeyType, publicKeys [][]byte, forSign string, signs []byte, nodeKeyOrLogin bool) (bool, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r}).Error("recovered panic in check sign")
@@ -284,7 +290,7 @@ func CheckSign(publicKeys [][]byte, forSign string, signs []byte, nodeKeyOrLogin
 			return false, fmt.Errorf("sign error %d!=%d", len(publicKeys), len(signsSlice))
 		}
 	}
-	return crypto.CheckSign(publicKeys[0], forSign, signsSlice[0])
+	return keyType.CheckSign(publicKeys[0], forSign, signsSlice[0])
 }
 
 // MerkleTreeRoot rertun Merkle value
@@ -474,16 +480,18 @@ func GetParent() string {
 
 // GetNodeKeys returns node private key and public key
 func GetNodeKeys() (string, string, error) {
-	nprivkey, err := ioutil.ReadFile(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
+	nprivkey, err := keystore.Default().ReadKey(filepath.Join(conf.Config.KeysDir, consts.NodePrivateKeyFilename))
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading node private key from file")
 		return "", "", err
 	}
+	defer keystore.Zero(nprivkey)
 	key, err := hex.DecodeString(string(nprivkey))
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding private key from hex")
 		return "", "", err
 	}
+	defer keystore.Zero(key)
 	npubkey, err := crypto.PrivateToPublic(key)
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting node private key to public")
@@ -497,6 +505,27 @@ func ChooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 	maxBlockID := int64(-1)
 	var bestHost string
 
+	ShuffleSlice(hosts)
+
+	blockIDs, err := GetHostsBlockIDs(ctx, hosts, logger)
+	if err != nil {
+		return "", maxBlockID, err
+	}
+
+	for _, h := range hosts {
+		blockID, ok := blockIDs[GetHostPort(h)]
+		// If blockID is maximal then the current host is the best
+		if ok && blockID > maxBlockID {
+			maxBlockID = blockID
+			bestHost = GetHostPort(h)
+		}
+	}
+
+	return bestHost, maxBlockID, nil
+}
+
+// GetHostsBlockIDs returns the max block ids of the available hosts
+func GetHostsBlockIDs(ctx context.Context, hosts []string, logger *log.Entry) (map[string]int64, error) {
 	type blockAndHost struct {
 		host    string
 		blockID int64
@@ -504,13 +533,11 @@ func ChooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 	}
 	c := make(chan blockAndHost, len(hosts))
 
-	ShuffleSlice(hosts)
-
 	var wg sync.WaitGroup
 	for _, h := range hosts {
 		if ctx.Err() != nil {
 			logger.WithFields(log.Fields{"error": ctx.Err(), "type": consts.ContextError}).Error("context error")
-			return "", maxBlockID, ctx.Err()
+			return nil, ctx.Err()
 		}
 
 		wg.Add(1)
@@ -528,27 +555,21 @@ func ChooseBestHost(ctx context.Context, hosts []string, logger *log.Entry) (str
 	}
 	wg.Wait()
 
-	var errCount int
+	blockIDs := make(map[string]int64)
 	for i := 0; i < len(hosts); i++ {
 		bl := <-c
 
 		if bl.err != nil {
-			errCount++
 			continue
 		}
-
-		// If blockID is maximal then the current host is the best
-		if bl.blockID > maxBlockID {
-			maxBlockID = bl.blockID
-			bestHost = bl.host
-		}
+		blockIDs[bl.host] = bl.blockID
 	}
 
-	if errCount == len(hosts) {
-		return "", 0, ErrNodesUnavailable
+	if len(blockIDs) == 0 {
+		return nil, ErrNodesUnavailable
 	}
 
-	return bestHost, maxBlockID, nil
+	return blockIDs, nil
 }
 
 func GetHostBlockID(host string, logger *log.Entry) (int64, error) {
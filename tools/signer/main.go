@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/GenesisKernel/go-genesis/packages/keystore"
+	"github.com/GenesisKernel/go-genesis/packages/signer"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var socketPath *string = flag.String("socket", "signer.sock", "path to unix socket which is used by the node, see signerSocket of node config")
+var keyPath *string = flag.String("key", "NodePrivateKey", "path to node private key, plain or encrypted keystore file")
+var statePath *string = flag.String("state", "signer_state.json", "path to file with heights of signed blocks")
+var voteStatePath *string = flag.String("voteState", "signer_votes.json", "path to file with heights of signed finality votes")
+var window *int64 = flag.Int64("window", signer.DefaultWindow, "count of last heights which are kept to protect from double sign")
+var passFile *string = flag.String("keystorePassFile", "", "path to file with keystore passphrase, "+keystore.PassphraseEnv+" is used if it's empty")
+
+func readPrivateKey() (string, error) {
+	store := keystore.Default()
+	pass := []byte(os.Getenv(keystore.PassphraseEnv))
+	if len(*passFile) > 0 {
+		data, err := ioutil.ReadFile(*passFile)
+		if err != nil {
+			return "", err
+		}
+		pass = bytes.TrimRight(data, "\r\n")
+	}
+	if len(pass) > 0 {
+		store.Unlock(pass)
+		keystore.Zero(pass)
+	}
+	key, err := store.ReadKey(*keyPath)
+	if err != nil {
+		return "", err
+	}
+	defer keystore.Zero(key)
+	if _, err = hex.DecodeString(string(key)); err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+func main() {
+	flag.Parse()
+
+	privateKey, err := readPrivateKey()
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": *keyPath}).Fatal("reading node private key")
+	}
+	keystore.Default().Lock()
+
+	guard, err := signer.NewGuard(*statePath, *window)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": *statePath}).Fatal("loading signer state")
+	}
+	votes, err := signer.NewGuard(*voteStatePath, *window)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": *voteStatePath}).Fatal("loading signer state of votes")
+	}
+	server, err := signer.NewServer(privateKey, guard, votes)
+	if err != nil {
+		log.WithError(err).Fatal("creating signer")
+	}
+
+	// the socket is left if the previous signer has been killed
+	if _, err = os.Stat(*socketPath); err == nil {
+		if conn, err := net.Dial("unix", *socketPath); err == nil {
+			conn.Close()
+			log.WithFields(log.Fields{"path": *socketPath}).Fatal("signer is already running")
+		}
+		os.Remove(*socketPath)
+	}
+	l, err := net.Listen("unix", *socketPath)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"path": *socketPath}).Fatal("listening socket")
+	}
+	if err = os.Chmod(*socketPath, 0600); err != nil {
+		l.Close()
+		log.WithError(err).WithFields(log.Fields{"path": *socketPath}).Fatal("changing mode of socket")
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		l.Close()
+	}()
+
+	log.WithFields(log.Fields{"path": *socketPath}).Info("signer is started")
+	if err = server.Serve(l); err != nil {
+		log.WithError(err).Info("signer is stopped")
+	}
+}